@@ -1,36 +1,98 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
 
+	"github.com/Avepa/booking/pkg"
 	"github.com/Avepa/booking/pkg/handler"
+	"github.com/Avepa/booking/pkg/migrations"
 	"github.com/Avepa/booking/pkg/repository"
 	"github.com/Avepa/booking/pkg/repository/mysql"
+	"github.com/Avepa/booking/pkg/repository/postgres"
+	"github.com/Avepa/booking/pkg/repository/sqlite"
 	"github.com/Avepa/booking/pkg/server"
 	"github.com/Avepa/booking/pkg/service"
 )
 
 func main() {
-	DBcfg := &mysql.Config{
-		Host:     os.Getenv("DATABASE_HOST"),
-		Port:     os.Getenv("DATABASE_PORT"),
-		Username: os.Getenv("DATABASE_USERNAME"),
-		Password: os.Getenv("DATABASE_PASSWORD"),
-		DBName:   os.Getenv("DATABASE_DBName"),
+	storage := flag.String("storage", "database",
+		"where the data is kept: database (see DATABASE_DRIVER) or memory")
+	checkSchema := flag.Bool("check-schema", false,
+		"refuse to start when the MySQL schema has migrations that are not applied")
+	flag.Parse()
+
+	if flag.Arg(0) == "migrate" {
+		err := migrate(flag.Arg(1))
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	db, err := mysql.NewMySqlDB(DBcfg)
-	if err != nil {
-		log.Println(err)
+	if flag.Arg(0) == "keys" {
+		err := keys(flag.Args()[1:])
+		if err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var repos *repository.Repository
+	switch *storage {
+	case "database":
+		db, r, err := openRepository(os.Getenv("DATABASE_DRIVER"))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		defer db.Close()
+		repos = r
+
+		if *checkSchema {
+			err = checkMigrations(db)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+	case "memory":
+		repos = repository.NewMemoryRepository()
+
+		// the keys of the memory are lost on exit,
+		// so the CLI can not issue them, the key is printed
+		// once to stdout and not to the log
+		key, _, err := service.NewAPIKeysService(repos.APIKeys).
+			Issue(context.Background(), pkg.DefaultTenantID, pkg.RoleAdmin)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		fmt.Printf("DEMO ONLY: admin key of tenant %d, valid until the server stops:\n%s\n",
+			pkg.DefaultTenantID, key)
+	default:
+		log.Printf("unknown storage %q", *storage)
 		return
 	}
-	defer db.Close()
 
-	repos := repository.NewRepository(db)
-	serveces := service.NewService(repos)
+	serveces := service.NewService(repos, service.Config{
+		Dates: service.DateRules{
+			MinNights: envInt("BOOKING_MIN_NIGHTS", service.DefaultDateRules.MinNights),
+			MaxNights: envInt("BOOKING_MAX_NIGHTS", service.DefaultDateRules.MaxNights),
+			Horizon:   envInt("BOOKING_HORIZON_DAYS", service.DefaultDateRules.Horizon),
+			AllowPast: os.Getenv("BOOKING_ALLOW_PAST") == "true",
+		},
+	})
 	handlers := handler.NewHandler(serveces)
-	err = server.RunHTTPServer(
+	err := server.RunHTTPServer(
 		os.Getenv("HTTTPSERVER_PORT"),
 		handlers.Routes(),
 	)
@@ -40,3 +102,177 @@ func main() {
 		return
 	}
 }
+
+// connects to the database of the driver,
+// mysql is used when the driver is not set
+func openRepository(driver string) (*sql.DB, *repository.Repository, error) {
+	switch driver {
+	case "", "mysql":
+		db, err := mysql.NewMySqlDB(mysqlConfig())
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, repository.NewRepository(db), nil
+	case "postgres":
+		db, err := postgres.NewPostgresDB(&postgres.Config{
+			Host:     os.Getenv("DATABASE_HOST"),
+			Port:     os.Getenv("DATABASE_PORT"),
+			Username: os.Getenv("DATABASE_USERNAME"),
+			Password: os.Getenv("DATABASE_PASSWORD"),
+			DBName:   os.Getenv("DATABASE_DBName"),
+			SSLMode:  os.Getenv("DATABASE_SSLMODE"),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, repository.NewPostgresRepository(db), nil
+	case "sqlite":
+		path := os.Getenv("DATABASE_PATH")
+		if path == "" {
+			path = "booking.db"
+		}
+		db, err := sqlite.NewSQLiteDB(&sqlite.Config{Path: path})
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, repository.NewSQLiteRepository(db), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown DATABASE_DRIVER %q", driver)
+	}
+}
+
+func mysqlConfig() *mysql.Config {
+	return &mysql.Config{
+		Host:     os.Getenv("DATABASE_HOST"),
+		Port:     os.Getenv("DATABASE_PORT"),
+		Username: os.Getenv("DATABASE_USERNAME"),
+		Password: os.Getenv("DATABASE_PASSWORD"),
+		DBName:   os.Getenv("DATABASE_DBName"),
+	}
+}
+
+// runs the migrate subcommand on the MySQL database:
+// up applies all pending migrations, down reverts the last one,
+// status lists the migrations
+func migrate(command string) error {
+	cfg := mysqlConfig()
+	cfg.MultiStatements = true
+	db, err := mysql.NewMySqlDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := migrations.NewMySQLMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, migration := range done {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		migration, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+	default:
+		return errors.New("usage: migrate up|down|status")
+	}
+
+	return nil
+}
+
+// runs the keys subcommand on the database of DATABASE_DRIVER:
+// issue TENANT ROLE prints a new key, it is not shown again,
+// revoke ID refuses the requests with the key
+func keys(args []string) error {
+	usage := errors.New("usage: keys issue TENANT admin|staff|read-only | keys revoke ID")
+	if len(args) == 0 {
+		return usage
+	}
+
+	db, repos, err := openRepository(os.Getenv("DATABASE_DRIVER"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	s := service.NewAPIKeysService(repos.APIKeys)
+	ctx := context.Background()
+	switch {
+	case args[0] == "issue" && len(args) == 3:
+		tenant, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return pkg.ErrTenantNotValid.Wrap(err)
+		}
+		secret, key, err := s.Issue(ctx, tenant, pkg.Role(args[2]))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("key %d of tenant %d, role %s:\n%s\n", key.ID, key.TenantID, key.Role, secret)
+	case args[0] == "revoke" && len(args) == 2:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return pkg.ErrIdNotValid.Wrap(err)
+		}
+		err = s.Revoke(ctx, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("revoked key %d\n", id)
+	default:
+		return usage
+	}
+
+	return nil
+}
+
+// returns migrations.ErrSchemaBehind if the schema
+// has migrations that are not applied, only MySQL is checked
+func checkMigrations(db *sql.DB) error {
+	driver := os.Getenv("DATABASE_DRIVER")
+	if driver != "" && driver != "mysql" {
+		return fmt.Errorf("schema check is not supported for %q", driver)
+	}
+
+	m, err := migrations.NewMySQLMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	return m.Check(context.Background())
+}
+
+// returns the environment variable as a number,
+// or def if it is not set
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
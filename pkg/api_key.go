@@ -0,0 +1,39 @@
+package pkg
+
+// Role limits what the requests of an API key may do.
+type Role string
+
+const (
+	// RoleAdmin manages properties, rooms, types and rates
+	RoleAdmin Role = "admin"
+	// RoleStaff manages bookings and guests
+	RoleStaff Role = "staff"
+	// RoleReadOnly only reads
+	RoleReadOnly Role = "read-only"
+)
+
+// every role may do what the roles with a lower level may
+var roleLevels = map[Role]int{
+	RoleReadOnly: 1,
+	RoleStaff:    2,
+	RoleAdmin:    3,
+}
+
+// Valid reports whether the role is known.
+func (r Role) Valid() bool {
+	return roleLevels[r] != 0
+}
+
+// Allows reports whether the role may do what the required role may.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleLevels[r] >= roleLevels[required]
+}
+
+// APIKey authenticates the requests of a tenant,
+// only the hash of the key is stored.
+type APIKey struct {
+	ID       int64  `json:"key_id"`
+	TenantID int64  `json:"tenant_id"`
+	Role     Role   `json:"role"`
+	Hash     string `json:"-"`
+}
@@ -0,0 +1,28 @@
+package pkg
+
+import "testing"
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{RoleAdmin, RoleAdmin, true},
+		{RoleAdmin, RoleStaff, true},
+		{RoleAdmin, RoleReadOnly, true},
+		{RoleStaff, RoleAdmin, false},
+		{RoleStaff, RoleStaff, true},
+		{RoleStaff, RoleReadOnly, true},
+		{RoleReadOnly, RoleStaff, false},
+		{RoleReadOnly, RoleReadOnly, true},
+		{"owner", RoleReadOnly, false},
+		{"", RoleReadOnly, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Error("wrong answer for ", tt.role, " and ", tt.required, ": ", got)
+		}
+	}
+}
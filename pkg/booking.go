@@ -1,7 +1,55 @@
 package pkg
 
+type BookingStatus string
+
+const (
+	StatusPending    BookingStatus = "pending"
+	StatusConfirmed  BookingStatus = "confirmed"
+	StatusCheckedIn  BookingStatus = "checked_in"
+	StatusCheckedOut BookingStatus = "checked_out"
+	StatusCancelled  BookingStatus = "cancelled"
+	StatusNoShow     BookingStatus = "no_show"
+)
+
+// reports whether the status is one of the known statuses
+func (s BookingStatus) Valid() bool {
+	switch s {
+	case StatusPending, StatusConfirmed, StatusCheckedIn,
+		StatusCheckedOut, StatusCancelled, StatusNoShow:
+		return true
+	}
+	return false
+}
+
+// reports whether a booking in the status occupies the room
+func (s BookingStatus) Active() bool {
+	return s == StatusPending || s == StatusConfirmed || s == StatusCheckedIn
+}
+
+// Booking.Total is the price of the stay when it was booked,
+// it is nil for the bookings made before the prices were saved.
+// A zero GuestID is a booking without a guest.
+// Adults and Children are the number of people staying,
+// the bookings made before they were saved have one adult.
+// A booking of a room type keeps the type, a zero RoomID
+// is a booking whose room is assigned at check-in.
 type Booking struct {
-	ID    int64  `json:"booking_id"`
-	Start string `json:"date_start"`
-	End   string `json:"date_end"`
+	ID       int64         `json:"booking_id"`
+	RoomID   int64         `json:"room_id,omitempty"`
+	TypeID   int64         `json:"type_id,omitempty"`
+	GuestID  int64         `json:"guest_id,omitempty"`
+	Adults   int           `json:"adults"`
+	Children int           `json:"children"`
+	Start    string        `json:"date_start"`
+	End      string        `json:"date_end"`
+	Status   BookingStatus `json:"status"`
+	Total    *Money        `json:"total,omitempty"`
+}
+
+// BookingUpdate contains the fields of the booking to be changed,
+// nil fields are left unchanged.
+type BookingUpdate struct {
+	RoomID *int64  `json:"room_id"`
+	Start  *string `json:"date_start"`
+	End    *string `json:"date_end"`
 }
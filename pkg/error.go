@@ -1,14 +1,115 @@
 package pkg
 
-import "errors"
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error is an error of the service with a stable code for the API clients.
+// Errors with the same code match each other in errors.Is,
+// so a sentinel also matches its copies with a cause.
+type Error struct {
+	// machine-readable code, it does not change between versions
+	Code string
+	// HTTP status of the response
+	Status int
+	// message for the user
+	Message string
+	// the cause, it is logged but not shown to the user
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error with the cause.
+func (e *Error) Wrap(cause error) error {
+	c := *e
+	c.Err = cause
+	return &c
+}
 
 var (
-	ErrFailedGet       = errors.New("failed to get data")
-	ErrFailedSave      = errors.New("failed to save data")
-	ErrFailedDelete    = errors.New("failed to delete data")
-	ErrIDNotFound      = errors.New("id not found")
-	ErrDateIsIncorrect = errors.New("date is incorrect")
-	ErrNoForeignKey    = errors.New("Error 1452: Cannot add or update a child row: a foreign key constraint fails")
-	ErrPriceNotValid   = errors.New("incorrect price entry")
-	ErrIdNotValid      = errors.New("incorrect id entry")
+	ErrFailedGet        = &Error{"get_failed", http.StatusInternalServerError, "failed to get data", nil}
+	ErrFailedSave       = &Error{"save_failed", http.StatusInternalServerError, "failed to save data", nil}
+	ErrFailedDelete     = &Error{"delete_failed", http.StatusInternalServerError, "failed to delete data", nil}
+	ErrIDNotFound       = &Error{"id_not_found", http.StatusNotFound, "id not found", nil}
+	ErrDateIsIncorrect  = &Error{"date_not_valid", http.StatusBadRequest, "date is incorrect", nil}
+	ErrNoForeignKey     = &Error{"room_not_found", http.StatusBadRequest, "room does not exist", nil}
+	ErrPriceNotValid    = &Error{"price_not_valid", http.StatusBadRequest, "incorrect price entry", nil}
+	ErrCurrencyNotValid = &Error{"currency_not_valid", http.StatusBadRequest, "incorrect currency entry", nil}
+	ErrIdNotValid       = &Error{"id_not_valid", http.StatusBadRequest, "incorrect id entry", nil}
+	ErrBookingConflict  = &Error{"booking_conflict", http.StatusConflict, "room is already booked for these dates", nil}
+	ErrBodyNotValid     = &Error{"body_not_valid", http.StatusBadRequest, "incorrect request body", nil}
+	ErrBodyTooLarge     = &Error{"body_too_large", http.StatusRequestEntityTooLarge, "request body too large", nil}
+	ErrNothingToUpdate  = &Error{"nothing_to_update", http.StatusBadRequest, "no fields to update", nil}
+	ErrStatusNotValid   = &Error{"status_not_valid", http.StatusBadRequest, "incorrect status entry", nil}
+	ErrStatusChange     = &Error{"status_change_not_allowed", http.StatusConflict, "booking status does not allow this change", nil}
+	ErrRoomHasBookings  = &Error{"room_has_bookings", http.StatusConflict, "room has bookings that have not ended", nil}
+	ErrRoomHasGuests    = &Error{"room_has_guests", http.StatusConflict, "room has a checked in guest", nil}
+	ErrLimitNotValid    = &Error{"limit_not_valid", http.StatusBadRequest, "incorrect limit entry", nil}
+	ErrCursorNotValid   = &Error{"cursor_not_valid", http.StatusBadRequest, "incorrect cursor entry", nil}
+	ErrSortNotValid     = &Error{"sort_not_valid", http.StatusBadRequest, "incorrect sorting entry", nil}
+	ErrWeekdayNotValid  = &Error{"weekday_not_valid", http.StatusBadRequest, "incorrect weekday entry", nil}
+	ErrNameNotValid     = &Error{"name_not_valid", http.StatusBadRequest, "incorrect name entry", nil}
+	ErrEmailNotValid    = &Error{"email_not_valid", http.StatusBadRequest, "incorrect email entry", nil}
+	ErrPhoneNotValid    = &Error{"phone_not_valid", http.StatusBadRequest, "incorrect phone entry", nil}
+	ErrGuestExists      = &Error{"guest_exists", http.StatusConflict, "guest with this email already exists", nil}
+	ErrNoGuest          = &Error{"guest_not_found", http.StatusBadRequest, "guest does not exist", nil}
+	ErrCapacityNotValid = &Error{"capacity_not_valid", http.StatusBadRequest, "incorrect room capacity entry", nil}
+	ErrGuestsNotValid   = &Error{"guests_not_valid", http.StatusBadRequest, "incorrect number of guests", nil}
+	ErrCapacityExceeded = &Error{"capacity_exceeded", http.StatusBadRequest, "room does not fit the guests", nil}
+	ErrNoRoomType       = &Error{"room_type_not_found", http.StatusBadRequest, "room type does not exist", nil}
+	ErrRoomHasType      = &Error{"room_has_type", http.StatusConflict, "room takes these fields from its type", nil}
+	ErrNoFreeRoom       = &Error{"no_free_room", http.StatusConflict, "no room of the type is free for these dates", nil}
+	ErrAssignNotValid   = &Error{"assign_not_valid", http.StatusBadRequest, "incorrect assign entry", nil}
+	ErrNoProperty       = &Error{"property_not_found", http.StatusBadRequest, "property does not exist", nil}
+	ErrTimezoneNotValid = &Error{"timezone_not_valid", http.StatusBadRequest, "incorrect timezone entry", nil}
+	ErrCurrencyChange   = &Error{"currency_change_not_allowed", http.StatusBadRequest, "property with rooms or types can not change its currency", nil}
+	ErrTenantNotValid   = &Error{"tenant_not_valid", http.StatusBadRequest, "incorrect tenant entry", nil}
+	ErrRoleNotValid     = &Error{"role_not_valid", http.StatusBadRequest, "incorrect role entry", nil}
+	ErrUnauthenticated  = &Error{"unauthenticated", http.StatusUnauthorized, "missing or invalid api key", nil}
+	ErrForbidden        = &Error{"forbidden", http.StatusForbidden, "the role of the api key does not allow this request", nil}
 )
+
+// date range violations, returned wrapped in DateError
+var (
+	ErrDateOrder    = &Error{"date_order", http.StatusBadRequest, "must be after date_start", nil}
+	ErrStayTooShort = &Error{"stay_too_short", http.StatusBadRequest, "stay is too short", nil}
+	ErrStayTooLong  = &Error{"stay_too_long", http.StatusBadRequest, "stay is too long", nil}
+	ErrDateInPast   = &Error{"date_in_past", http.StatusBadRequest, "date is in the past", nil}
+	ErrDateTooFar   = &Error{"date_too_far", http.StatusBadRequest, "date is beyond the booking horizon", nil}
+)
+
+// DateError is a violation of the booking date rules
+// in the date_start or date_end field.
+type DateError struct {
+	Field string
+	Err   error
+	// the limit that was violated, e.g. "minimum 1 nights"
+	Limit string
+}
+
+func (e *DateError) Error() string {
+	if e.Limit == "" {
+		return fmt.Sprintf("%s: %s", e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s: %s (%s)", e.Field, e.Err, e.Limit)
+}
+
+func (e *DateError) Unwrap() error {
+	return e.Err
+}
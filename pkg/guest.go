@@ -0,0 +1,11 @@
+package pkg
+
+// Guest is the person a room is booked for,
+// the email is unique among the guests.
+type Guest struct {
+	ID       int64  `json:"guest_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone,omitempty"`
+	Document string `json:"document_number,omitempty"`
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type roleKey struct{}
+
+// authenticates the request by the API key of the
+// Authorization header: "Bearer <key>", the requests see
+// only the data of the tenant of the key,
+// returns 401 if the key is missing, unknown or revoked
+// or the header has another scheme
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var key *pkg.APIKey
+		secret, err := bearerKey(r.Header.Get("Authorization"))
+		if err == nil {
+			key, err = h.services.APIKeys.Authenticate(r.Context(), secret)
+		}
+		if err != nil {
+			if errors.Is(err, pkg.ErrUnauthenticated) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			HTTPError(w, err)
+			return
+		}
+
+		ctx := pkg.WithTenant(r.Context(), key.TenantID)
+		ctx = context.WithValue(ctx, roleKey{}, key.Role)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// returns the key of an Authorization header of the Bearer scheme,
+// the scheme is case-insensitive
+func bearerKey(header string) (string, error) {
+	const scheme = "Bearer "
+	if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+		return "", pkg.ErrUnauthenticated
+	}
+	return header[len(scheme):], nil
+}
+
+// passes the request to next only if the role of its key
+// allows what the required role may do, else returns 403
+func allow(required pkg.Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, _ := r.Context().Value(roleKey{}).(pkg.Role)
+		if !role.Allows(required) {
+			HTTPError(w, pkg.ErrForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+	mock_service "github.com/Avepa/booking/pkg/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+// serves the routes to the requests
+// of an admin key of the default tenant
+func adminRoutes(c *gomock.Controller, services *service.Service) http.Handler {
+	keys := mock_service.NewMockAPIKeys(c)
+	keys.EXPECT().Authenticate(gomock.Any(), "admin").
+		Return(&pkg.APIKey{ID: 1, TenantID: pkg.DefaultTenantID, Role: pkg.RoleAdmin}, nil).AnyTimes()
+	services.APIKeys = keys
+
+	routes := NewHandler(services).Routes()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set("Authorization", "Bearer admin")
+		routes.ServeHTTP(w, r)
+	})
+}
+
+func TestHandler_authenticate(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		authorization      string
+		expectedStatusCode int
+		expectedError      *pkg.Error
+	}{
+		{
+			name:               "OK read-only",
+			method:             "GET",
+			path:               "/properties",
+			authorization:      "Bearer reader",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "OK staff",
+			method:             "DELETE",
+			path:               "/bookings/delete?booking_id=5",
+			authorization:      "Bearer staff",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "OK admin",
+			method:             "DELETE",
+			path:               "/room/delete?room_id=2",
+			authorization:      "Bearer admin",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "No key",
+			method:             "GET",
+			path:               "/properties",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      pkg.ErrUnauthenticated,
+		},
+		{
+			name:               "Key without scheme",
+			method:             "GET",
+			path:               "/properties",
+			authorization:      "reader",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      pkg.ErrUnauthenticated,
+		},
+		{
+			name:               "Other scheme",
+			method:             "GET",
+			path:               "/properties",
+			authorization:      "Basic reader",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      pkg.ErrUnauthenticated,
+		},
+		{
+			name:               "OK lowercase scheme",
+			method:             "GET",
+			path:               "/properties",
+			authorization:      "bearer reader",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Revoked key",
+			method:             "GET",
+			path:               "/properties",
+			authorization:      "Bearer revoked",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedError:      pkg.ErrUnauthenticated,
+		},
+		{
+			name:               "Read-only writes",
+			method:             "DELETE",
+			path:               "/bookings/delete?booking_id=5",
+			authorization:      "Bearer reader",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      pkg.ErrForbidden,
+		},
+		{
+			name:               "Staff deletes a room",
+			method:             "DELETE",
+			path:               "/room/delete?room_id=2",
+			authorization:      "Bearer staff",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      pkg.ErrForbidden,
+		},
+		{
+			name:               "Staff deletes a room of a property",
+			method:             "DELETE",
+			path:               "/properties/1/room/delete?room_id=2",
+			authorization:      "Bearer staff",
+			expectedStatusCode: http.StatusForbidden,
+			expectedError:      pkg.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			keys := mock_service.NewMockAPIKeys(c)
+			keys.EXPECT().Authenticate(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, secret string) (*pkg.APIKey, error) {
+					switch secret {
+					case "reader":
+						return &pkg.APIKey{ID: 1, TenantID: 2, Role: pkg.RoleReadOnly}, nil
+					case "staff":
+						return &pkg.APIKey{ID: 2, TenantID: 2, Role: pkg.RoleStaff}, nil
+					case "admin":
+						return &pkg.APIKey{ID: 3, TenantID: 2, Role: pkg.RoleAdmin}, nil
+					}
+					return nil, pkg.ErrUnauthenticated
+				},
+			).AnyTimes()
+
+			// the services get the tenant of the key
+			inTenant := func(ctx context.Context) {
+				if tenant := pkg.TenantFrom(ctx); tenant != 2 {
+					t.Error("wrong tenant received: ", tenant)
+				}
+			}
+			properties := mock_service.NewMockProperties(c)
+			properties.EXPECT().List(gomock.Any()).DoAndReturn(func(ctx context.Context) ([]pkg.Property, error) {
+				inTenant(ctx)
+				return []pkg.Property{}, nil
+			}).AnyTimes()
+			properties.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&pkg.Property{ID: 1}, nil).AnyTimes()
+			bookings := mock_service.NewMockBookings(c)
+			bookings.EXPECT().Delete(gomock.Any(), int64(5)).DoAndReturn(func(ctx context.Context, id int64) error {
+				inTenant(ctx)
+				return nil
+			}).AnyTimes()
+			rooms := mock_service.NewMockRoom(c)
+			rooms.EXPECT().Delete(gomock.Any(), int64(2), false).DoAndReturn(func(ctx context.Context, id int64, force bool) error {
+				inTenant(ctx)
+				return nil
+			}).AnyTimes()
+
+			services := &service.Service{Properties: properties, Bookings: bookings, Room: rooms, APIKeys: keys}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			NewHandler(services).Routes().ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Error("wrong status code received: ", w.Code, w.Body.String())
+			}
+			if tt.expectedStatusCode == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Error("wrong WWW-Authenticate header received: ", w.Header().Get("WWW-Authenticate"))
+			}
+			if tt.expectedError != nil {
+				var body Error
+				json.NewDecoder(w.Body).Decode(&body)
+				if !reflect.DeepEqual(body, errorBody(tt.expectedError)) {
+					t.Error("wrong error received: ", body)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package handler
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
 
 	"github.com/Avepa/booking/pkg"
 )
@@ -13,65 +15,178 @@ type bookingID struct {
 	ID int64 `json:"booking_id"`
 }
 
+// bookingRequest is the body of a new booking, it has only
+// the fields a client may set, so the id, the status and
+// the total are not accepted, Assign is used only for a booking of a type
+type bookingRequest struct {
+	RoomID   int64  `json:"room_id"`
+	TypeID   int64  `json:"type_id"`
+	GuestID  int64  `json:"guest_id"`
+	Adults   int    `json:"adults"`
+	Children int    `json:"children"`
+	Start    string `json:"date_start"`
+	End      string `json:"date_end"`
+	Assign   string `json:"assign"`
+}
+
 // example request:
 //		http://localhost/bookings/create
 //
-// headers that are used:
+// JSON body, guest_id, adults and children are optional:
+//		{"room_id": 12, "guest_id": 3, "adults": 2, "children": 1,
+//		 "date_start": "2021-01-05", "date_end": "2021-01-08"}
+// the booking is made for one adult if adults is not passed
+//
+// a room type is booked with type_id instead of room_id:
+//		{"type_id": 2, "assign": "check_in",
+//		 "date_start": "2021-01-05", "date_end": "2021-01-08"}
+// assign is optional:
+//	 now - a free room of the type is assigned to the booking, by default;
+//	 check_in - the room is assigned when the booking is checked in.
+//
+// deprecated headers, used when the body is not JSON:
 //		room_id
 //		date_start
 //		date_end
 //
 // date format: 2006-01-02
 func (h *Handler) createBooking(w http.ResponseWriter, r *http.Request) {
+	var err error
+	var req bookingRequest
+
+	if isJSON(r) {
+		err = decodeJSON(r, &req)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+	} else {
+		deprecatedHeaders(w)
+		req.Start = r.Header.Get("date_start")
+		req.End = r.Header.Get("date_end")
+		req.RoomID, err = strconv.ParseInt(r.Header.Get("room_id"), 10, 64)
+		if err != nil {
+			HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+			return
+		}
+	}
+
 	booking := pkg.Booking{
-		Start: r.Header.Get("date_start"),
-		End:   r.Header.Get("date_end"),
+		GuestID:  req.GuestID,
+		Adults:   req.Adults,
+		Children: req.Children,
+		Start:    req.Start,
+		End:      req.End,
+	}
+	idRoom := req.RoomID
+
+	id := bookingID{}
+	if idRoom == 0 && req.TypeID != 0 {
+		var deferred bool
+		switch req.Assign {
+		case "", "now":
+		case "check_in":
+			deferred = true
+		default:
+			HTTPError(w, pkg.ErrAssignNotValid)
+			return
+		}
+		id.ID, err = h.services.Bookings.AddByType(r.Context(), req.TypeID, &booking, deferred)
+	} else {
+		id.ID, err = h.services.Bookings.Add(r.Context(), idRoom, &booking)
+	}
+	if err != nil {
+		HTTPError(w, err)
+		return
 	}
 
-	room := r.Header.Get("room_id")
-	idRoom, err := strconv.ParseInt(room, 10, 64)
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/bookings/245
+// JSON body, fields that are not passed are not changed:
+//		{"room_id": 14, "date_start": "2021-01-06", "date_end": "2021-01-09"}
+// returns the changed booking
+func (h *Handler) updateBooking(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	if err != nil {
-		err = pkg.ErrIdNotValid
-		log.Println(err)
-		HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
 		return
 	}
 
-	id := bookingID{}
-	id.ID, err = h.services.Bookings.Add(idRoom, &booking)
+	var update pkg.BookingUpdate
+	err = decodeJSON(r, &update)
 	if err != nil {
-		log.Println(err)
-		if err == pkg.ErrNoForeignKey || err == pkg.ErrDateIsIncorrect {
-			HTTPError(w, err.Error(), http.StatusBadRequest)
-		} else {
-			HTTPError(w, err.Error(), http.StatusInternalServerError)
-		}
+		HTTPError(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(id)
+	booking, err := h.services.Bookings.Update(r.Context(), id, &update)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(booking)
+}
+
+// booking status by the action of the request path
+var statusActions = map[string]pkg.BookingStatus{
+	"confirm":   pkg.StatusConfirmed,
+	"check-in":  pkg.StatusCheckedIn,
+	"check-out": pkg.StatusCheckedOut,
+	"cancel":    pkg.StatusCancelled,
+	"no-show":   pkg.StatusNoShow,
 }
 
 // example request:
-//		http://localhost/bookings/list?room_id=12
+//		http://localhost/bookings/245/confirm
+// actions:
+//		confirm   - pending to confirmed
+//		check-in  - confirmed to checked_in
+//		check-out - checked_in to checked_out
+//		cancel    - pending or confirmed to cancelled
+//		no-show   - confirmed to no_show
+// returns the changed booking
+func (h *Handler) changeBookingStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	booking, err := h.services.Bookings.SetStatus(r.Context(), id, statusActions[vars["action"]])
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(booking)
+}
+
+// example request:
+//		http://localhost/bookings/list?room_id=12&status=pending,confirmed
+// status is optional, without it bookings in all statuses are returned
 func (h *Handler) getBookings(w http.ResponseWriter, r *http.Request) {
 	idRoom := r.URL.Query().Get("room_id")
 	id, err := strconv.ParseInt(idRoom, 10, 64)
 	if err != nil {
-		err = pkg.ErrIdNotValid
-		log.Println(err)
-		HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
 		return
 	}
 
-	bookings, err := h.services.Bookings.Get(id)
-	if err != nil {
-		log.Println(err)
-		if err == pkg.ErrFailedGet {
-			HTTPError(w, err.Error(), http.StatusInternalServerError)
-		} else {
-			HTTPError(w, err.Error(), http.StatusBadRequest)
+	var status []pkg.BookingStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		for _, st := range strings.Split(s, ",") {
+			status = append(status, pkg.BookingStatus(st))
 		}
+	}
+
+	bookings, err := h.services.Bookings.Get(r.Context(), id, status)
+	if err != nil {
+		HTTPError(w, err)
 		return
 	}
 
@@ -80,25 +195,18 @@ func (h *Handler) getBookings(w http.ResponseWriter, r *http.Request) {
 
 // example request:
 //		http://localhost/bookings/delete?booking_id=245
+// the booking is cancelled and stays in the history
 func (h *Handler) deleteBookings(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("booking_id")
 	booking, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		err = pkg.ErrIdNotValid
-		log.Println(err)
-		HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
 		return
 	}
 
-	err = h.services.Bookings.Delete(booking)
+	err = h.services.Bookings.Delete(r.Context(), booking)
 	if err != nil {
-		log.Println(err)
-		if err == pkg.ErrFailedDelete {
-			HTTPError(w, err.Error(), http.StatusInternalServerError)
-		}
-		if err == pkg.ErrIDNotFound {
-			HTTPError(w, err.Error(), http.StatusBadRequest)
-		}
+		HTTPError(w, err)
 		return
 	}
 
@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Avepa/booking/pkg"
@@ -41,7 +43,7 @@ func TestHandler_createBookings(t *testing.T) {
 			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
 				idRoom := int64(1)
 				idBooking := int64(1)
-				r.EXPECT().Add(idRoom, booking).Return(idBooking, nil)
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).Return(idBooking, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponseBody: result{
@@ -71,7 +73,7 @@ func TestHandler_createBookings(t *testing.T) {
 			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
 				idRoom := int64(1)
 				idBooking := int64(0)
-				r.EXPECT().Add(idRoom, booking).
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).
 					Return(idBooking, pkg.ErrDateIsIncorrect)
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -89,7 +91,7 @@ func TestHandler_createBookings(t *testing.T) {
 			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
 				idRoom := int64(1)
 				idBooking := int64(0)
-				r.EXPECT().Add(idRoom, booking).
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).
 					Return(idBooking, pkg.ErrNoForeignKey)
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -97,6 +99,42 @@ func TestHandler_createBookings(t *testing.T) {
 				Err: pkg.ErrNoForeignKey.Error(),
 			},
 		},
+		{
+			name:    "Date rule violated",
+			inputID: "1",
+			inputBooking: &pkg.Booking{
+				Start: "2018-02-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				idRoom := int64(1)
+				idBooking := int64(0)
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).
+					Return(idBooking, &pkg.DateError{Field: "date_end", Err: pkg.ErrDateOrder})
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: "date_end: must be after date_start",
+			},
+		},
+		{
+			name:    "Booking conflict",
+			inputID: "1",
+			inputBooking: &pkg.Booking{
+				Start: "2018-01-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				idRoom := int64(1)
+				idBooking := int64(0)
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).
+					Return(idBooking, pkg.ErrBookingConflict)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: result{
+				Err: pkg.ErrBookingConflict.Error(),
+			},
+		},
 		{
 			name:    "Failed get",
 			inputID: "1",
@@ -107,7 +145,7 @@ func TestHandler_createBookings(t *testing.T) {
 			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
 				idRoom := int64(1)
 				idBooking := int64(0)
-				r.EXPECT().Add(idRoom, booking).
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).
 					Return(idBooking, pkg.ErrFailedGet)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -178,7 +216,7 @@ func TestHandler_getBookings(t *testing.T) {
 			input: "1",
 			mock: func(r *mock_service.MockBookings, bookings []pkg.Booking) {
 				id := int64(1)
-				r.EXPECT().Get(id).Return(bookings, nil)
+				r.EXPECT().Get(gomock.Any(), id, []pkg.BookingStatus(nil)).Return(bookings, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponseBody: []pkg.Booking{
@@ -199,32 +237,62 @@ func TestHandler_getBookings(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "OK status",
+			input: "1&status=confirmed,checked_in",
+			mock: func(r *mock_service.MockBookings, bookings []pkg.Booking) {
+				id := int64(1)
+				status := []pkg.BookingStatus{pkg.StatusConfirmed, pkg.StatusCheckedIn}
+				r.EXPECT().Get(gomock.Any(), id, status).Return(bookings, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: []pkg.Booking{
+				{
+					ID:     65,
+					RoomID: 1,
+					Start:  "2018-02-25",
+					End:    "2018-03-05",
+					Status: pkg.StatusConfirmed,
+				},
+			},
+		},
+		{
+			name:  "Status not valid",
+			input: "1&status=closed",
+			mock: func(r *mock_service.MockBookings, bookings []pkg.Booking) {
+				id := int64(1)
+				status := []pkg.BookingStatus{"closed"}
+				r.EXPECT().Get(gomock.Any(), id, status).Return(nil, pkg.ErrStatusNotValid)
+			},
+			expectedStatusCode:    http.StatusBadRequest,
+			expectedResponseError: errorBody(pkg.ErrStatusNotValid),
+		},
 		{
 			name:                  "Id not valid",
 			input:                 "asf",
 			mock:                  func(r *mock_service.MockBookings, bookings []pkg.Booking) {},
 			expectedStatusCode:    http.StatusBadRequest,
-			expectedResponseError: Error{pkg.ErrIdNotValid.Error()},
+			expectedResponseError: errorBody(pkg.ErrIdNotValid),
 		},
 		{
 			name:  "Failed get",
 			input: "1",
 			mock: func(r *mock_service.MockBookings, bookings []pkg.Booking) {
 				id := int64(1)
-				r.EXPECT().Get(id).Return(bookings, pkg.ErrFailedGet)
+				r.EXPECT().Get(gomock.Any(), id, []pkg.BookingStatus(nil)).Return(bookings, pkg.ErrFailedGet)
 			},
 			expectedStatusCode:    http.StatusInternalServerError,
-			expectedResponseError: Error{pkg.ErrFailedGet.Error()},
+			expectedResponseError: errorBody(pkg.ErrFailedGet),
 		},
 		{
 			name:  "ID not found",
 			input: "1",
 			mock: func(r *mock_service.MockBookings, bookings []pkg.Booking) {
 				id := int64(1)
-				r.EXPECT().Get(id).Return(bookings, pkg.ErrIDNotFound)
+				r.EXPECT().Get(gomock.Any(), id, []pkg.BookingStatus(nil)).Return(bookings, pkg.ErrIDNotFound)
 			},
-			expectedStatusCode:    http.StatusBadRequest,
-			expectedResponseError: Error{pkg.ErrIDNotFound.Error()},
+			expectedStatusCode:    http.StatusNotFound,
+			expectedResponseError: errorBody(pkg.ErrIDNotFound),
 		},
 	}
 
@@ -262,7 +330,7 @@ func TestHandler_getBookings(t *testing.T) {
 			} else if resp.StatusCode != http.StatusOK {
 				body := Error{}
 				json.NewDecoder(resp.Body).Decode(&body)
-				if body != tt.expectedResponseError {
+				if !reflect.DeepEqual(body, tt.expectedResponseError) {
 					t.Error("wrong error code received: ", resp.StatusCode)
 				}
 				return
@@ -303,7 +371,7 @@ func TestHandler_deleteBookings(t *testing.T) {
 			input:     1,
 			inputBody: "1",
 			mock: func(r *mock_service.MockBookings, id int64) {
-				r.EXPECT().Delete(id).Return(nil)
+				r.EXPECT().Delete(gomock.Any(), id).Return(nil)
 			},
 			expected:           Status{Status: "ok"},
 			expectedStatusCode: http.StatusOK,
@@ -313,27 +381,37 @@ func TestHandler_deleteBookings(t *testing.T) {
 			inputBody:            "Adf",
 			mock:                 func(r *mock_service.MockBookings, id int64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: Error{pkg.ErrIdNotValid.Error()},
+			expectedResponseBody: errorBody(pkg.ErrIdNotValid),
 		},
 		{
 			name:      "Failed delete",
 			input:     2,
 			inputBody: "2",
 			mock: func(r *mock_service.MockBookings, id int64) {
-				r.EXPECT().Delete(id).Return(pkg.ErrFailedDelete)
+				r.EXPECT().Delete(gomock.Any(), id).Return(pkg.ErrFailedDelete)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: Error{pkg.ErrFailedDelete.Error()},
+			expectedResponseBody: errorBody(pkg.ErrFailedDelete),
+		},
+		{
+			name:      "Status does not allow",
+			input:     4,
+			inputBody: "4",
+			mock: func(r *mock_service.MockBookings, id int64) {
+				r.EXPECT().Delete(gomock.Any(), id).Return(pkg.ErrStatusChange)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: errorBody(pkg.ErrStatusChange),
 		},
 		{
 			name:      "ID not found",
 			input:     3,
 			inputBody: "3",
 			mock: func(r *mock_service.MockBookings, id int64) {
-				r.EXPECT().Delete(id).Return(pkg.ErrIDNotFound)
+				r.EXPECT().Delete(gomock.Any(), id).Return(pkg.ErrIDNotFound)
 			},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: Error{pkg.ErrIDNotFound.Error()},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
 		},
 	}
 
@@ -379,9 +457,401 @@ func TestHandler_deleteBookings(t *testing.T) {
 
 			body := Error{}
 			json.NewDecoder(resp.Body).Decode(&body)
-			if body != tt.expectedResponseBody {
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
 				t.Error("wrong body received: ", err)
 			}
 		})
 	}
 }
+
+func TestHandler_createBookingJSON(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockBookings, booking *pkg.Booking)
+
+	type result struct {
+		ID  int64  `json:"booking_id"`
+		Err string `json:"error"`
+	}
+
+	tests := []struct {
+		name                 string
+		input                string
+		inputBooking         *pkg.Booking
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody result
+	}{
+		{
+			name:  "OK",
+			input: `{"room_id": 3, "date_start": "2018-01-05", "date_end": "2018-02-01"}`,
+			inputBooking: &pkg.Booking{
+				Start: "2018-01-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				idRoom := int64(3)
+				idBooking := int64(7)
+				r.EXPECT().Add(gomock.Any(), idRoom, booking).Return(idBooking, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 7,
+			},
+		},
+		{
+			name:  "OK with guest",
+			input: `{"room_id": 3, "guest_id": 2, "date_start": "2018-01-05", "date_end": "2018-02-01"}`,
+			inputBooking: &pkg.Booking{
+				GuestID: 2,
+				Start:   "2018-01-05",
+				End:     "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				r.EXPECT().Add(gomock.Any(), int64(3), booking).Return(int64(8), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 8,
+			},
+		},
+		{
+			name:  "OK type",
+			input: `{"type_id": 2, "date_start": "2018-01-05", "date_end": "2018-02-01"}`,
+			inputBooking: &pkg.Booking{
+				Start: "2018-01-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				r.EXPECT().AddByType(gomock.Any(), int64(2), booking, false).Return(int64(9), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 9,
+			},
+		},
+		{
+			name:  "OK type assigned at check-in",
+			input: `{"type_id": 2, "assign": "check_in", "date_start": "2018-01-05", "date_end": "2018-02-01"}`,
+			inputBooking: &pkg.Booking{
+				Start: "2018-01-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				r.EXPECT().AddByType(gomock.Any(), int64(2), booking, true).Return(int64(10), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 10,
+			},
+		},
+		{
+			name:  "No free room",
+			input: `{"type_id": 2, "assign": "now", "date_start": "2018-01-05", "date_end": "2018-02-01"}`,
+			inputBooking: &pkg.Booking{
+				Start: "2018-01-05",
+				End:   "2018-02-01",
+			},
+			mock: func(r *mock_service.MockBookings, booking *pkg.Booking) {
+				r.EXPECT().AddByType(gomock.Any(), int64(2), booking, false).Return(int64(0), pkg.ErrNoFreeRoom)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponseBody: result{
+				Err: pkg.ErrNoFreeRoom.Error(),
+			},
+		},
+		{
+			name:               "Assign not valid",
+			input:              `{"type_id": 2, "assign": "later", "date_start": "2018-01-05"}`,
+			mock:               func(r *mock_service.MockBookings, booking *pkg.Booking) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrAssignNotValid.Error(),
+			},
+		},
+		{
+			name:               "Unknown field",
+			input:              `{"room_id": 3, "date_start": "2018-01-05", "nights": 2}`,
+			mock:               func(r *mock_service.MockBookings, booking *pkg.Booking) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Server field",
+			input:              `{"room_id": 3, "date_start": "2018-01-05", "date_end": "2018-02-01", "status": "checked_out"}`,
+			mock:               func(r *mock_service.MockBookings, booking *pkg.Booking) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Server total",
+			input:              `{"room_id": 3, "date_start": "2018-01-05", "date_end": "2018-02-01", "total": {"amount": "0.01", "currency": "USD"}}`,
+			mock:               func(r *mock_service.MockBookings, booking *pkg.Booking) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Several values",
+			input:              `{"room_id": 3} {"room_id": 4}`,
+			mock:               func(r *mock_service.MockBookings, booking *pkg.Booking) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockBookings(c)
+			tt.mock(repo, tt.inputBooking)
+
+			services := &service.Service{Bookings: repo}
+			handler := Handler{services}
+			h := http.HandlerFunc(handler.createBooking)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL, "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			}
+
+			body := result{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if body != tt.expectedResponseBody {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_updateBooking(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockBookings, update *pkg.BookingUpdate)
+
+	room := int64(14)
+	end := "2021-01-09"
+
+	tests := []struct {
+		name                 string
+		input                string
+		inputUpdate          *pkg.BookingUpdate
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedBooking      pkg.Booking
+		expectedResponseBody Error
+	}{
+		{
+			name:        "OK",
+			input:       `{"room_id": 14, "date_end": "2021-01-09"}`,
+			inputUpdate: &pkg.BookingUpdate{RoomID: &room, End: &end},
+			mock: func(r *mock_service.MockBookings, update *pkg.BookingUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(245), update).Return(&pkg.Booking{
+					ID:     245,
+					RoomID: 14,
+					Start:  "2021-01-05",
+					End:    "2021-01-09",
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBooking: pkg.Booking{
+				ID:     245,
+				RoomID: 14,
+				Start:  "2021-01-05",
+				End:    "2021-01-09",
+			},
+		},
+		{
+			name:                 "Body not valid",
+			input:                `{"room_id": "14"}`,
+			mock:                 func(r *mock_service.MockBookings, update *pkg.BookingUpdate) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorBody(pkg.ErrBodyNotValid),
+		},
+		{
+			name:        "ID not found",
+			input:       `{"room_id": 14}`,
+			inputUpdate: &pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_service.MockBookings, update *pkg.BookingUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(245), update).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
+		},
+		{
+			name:        "Booking conflict",
+			input:       `{"room_id": 14}`,
+			inputUpdate: &pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_service.MockBookings, update *pkg.BookingUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(245), update).Return(nil, pkg.ErrBookingConflict)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: errorBody(pkg.ErrBookingConflict),
+		},
+		{
+			name:        "Date rule violated",
+			input:       `{"date_end": "2021-01-09"}`,
+			inputUpdate: &pkg.BookingUpdate{End: &end},
+			mock: func(r *mock_service.MockBookings, update *pkg.BookingUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(245), update).
+					Return(nil, &pkg.DateError{Field: "date_end", Err: pkg.ErrStayTooLong})
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: Error{
+				Err:     "date_end: stay is too long",
+				Code:    "stay_too_long",
+				Details: map[string]string{"field": "date_end"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockBookings(c)
+			tt.mock(repo, tt.inputUpdate)
+
+			services := &service.Service{Bookings: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			req, err := http.NewRequest("PATCH", srv.URL+"/bookings/245", strings.NewReader(tt.input))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			client := http.Client{}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode == http.StatusOK {
+				b := pkg.Booking{}
+				json.NewDecoder(resp.Body).Decode(&b)
+				if b != tt.expectedBooking {
+					t.Error("wrong booking received: ", b)
+				}
+				return
+			}
+
+			body := Error{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_changeBookingStatus(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockBookings)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedBooking      pkg.Booking
+		expectedResponseBody Error
+	}{
+		{
+			name: "OK check in",
+			path: "/bookings/245/check-in",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().SetStatus(gomock.Any(), int64(245), pkg.StatusCheckedIn).Return(&pkg.Booking{
+					ID:     245,
+					RoomID: 3,
+					Start:  "2021-01-05",
+					End:    "2021-01-09",
+					Status: pkg.StatusCheckedIn,
+				}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBooking: pkg.Booking{
+				ID:     245,
+				RoomID: 3,
+				Start:  "2021-01-05",
+				End:    "2021-01-09",
+				Status: pkg.StatusCheckedIn,
+			},
+		},
+		{
+			name: "Transition not allowed",
+			path: "/bookings/245/no-show",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().SetStatus(gomock.Any(), int64(245), pkg.StatusNoShow).Return(nil, pkg.ErrStatusChange)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: errorBody(pkg.ErrStatusChange),
+		},
+		{
+			name: "ID not found",
+			path: "/bookings/245/confirm",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().SetStatus(gomock.Any(), int64(245), pkg.StatusConfirmed).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockBookings(c)
+			tt.mock(repo)
+
+			services := &service.Service{Bookings: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+tt.path, "", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode == http.StatusOK {
+				b := pkg.Booking{}
+				json.NewDecoder(resp.Body).Decode(&b)
+				if b != tt.expectedBooking {
+					t.Error("wrong booking received: ", b)
+				}
+				return
+			}
+
+			body := Error{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/Avepa/booking/pkg"
 )
 
 type Status struct {
@@ -10,14 +14,44 @@ type Status struct {
 }
 
 type Error struct {
-	Err string `json:"error"`
+	Err     string            `json:"error"`
+	Code    string            `json:"code"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// the response of errors that are not pkg.Error,
+// their text is only logged
+var errInternal = &pkg.Error{
+	Code:    "internal",
+	Status:  http.StatusInternalServerError,
+	Message: http.StatusText(http.StatusInternalServerError),
 }
 
-func HTTPError(w http.ResponseWriter, err string, code int) {
-	e := Error{
-		Err: err,
+// HTTPError logs err and writes it as JSON,
+// the status, code and message are taken from pkg.Error.
+// The field and limit of pkg.DateError are written in details.
+func HTTPError(w http.ResponseWriter, err error) {
+	log.Println(err)
+
+	var e *pkg.Error
+	if !errors.As(err, &e) {
+		e = errInternal
+	}
+
+	body := Error{
+		Err:  e.Message,
+		Code: e.Code,
+	}
+
+	var dateErr *pkg.DateError
+	if e != errInternal && errors.As(err, &dateErr) {
+		body.Err = dateErr.Error()
+		body.Details = map[string]string{"field": dateErr.Field}
+		if dateErr.Limit != "" {
+			body.Details["limit"] = dateErr.Limit
+		}
 	}
 
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(e)
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(body)
 }
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// returns the body that HTTPError writes for the error
+func errorBody(err *pkg.Error) Error {
+	return Error{Err: err.Message, Code: err.Code}
+}
+
+// returns the JSON body of the error response
+func errorJSON(err *pkg.Error) string {
+	b, _ := json.Marshal(errorBody(err))
+	return string(b)
+}
+
+func TestHTTPError(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedBody       Error
+	}{
+		{
+			name:               "Sentinel",
+			err:                pkg.ErrIDNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       errorBody(pkg.ErrIDNotFound),
+		},
+		{
+			name:               "Cause is not shown",
+			err:                pkg.ErrFailedSave.Wrap(errors.New("Error 1062: Duplicate entry")),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       errorBody(pkg.ErrFailedSave),
+		},
+		{
+			name: "Date error",
+			err: &pkg.DateError{
+				Field: "date_end",
+				Err:   pkg.ErrStayTooShort,
+				Limit: "minimum 2 nights",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: Error{
+				Err:  "date_end: stay is too short (minimum 2 nights)",
+				Code: "stay_too_short",
+				Details: map[string]string{
+					"field": "date_end",
+					"limit": "minimum 2 nights",
+				},
+			},
+		},
+		{
+			name:               "Unknown error",
+			err:                sql.ErrConnDone,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody: Error{
+				Err:  http.StatusText(http.StatusInternalServerError),
+				Code: "internal",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HTTPError(w, tt.err)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", w.Code)
+			}
+
+			body := Error{}
+			json.NewDecoder(w.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	cause := sql.ErrConnDone
+	err := pkg.ErrFailedGet.Wrap(cause)
+
+	if !errors.Is(err, pkg.ErrFailedGet) || !errors.Is(err, cause) {
+		t.Error("wrapped error does not match its sentinel and cause")
+	}
+	if errors.Is(err, pkg.ErrFailedSave) {
+		t.Error("errors with different codes match")
+	}
+	if err.Error() != "failed to get data: "+cause.Error() {
+		t.Error("wrong message received: ", err)
+	}
+}
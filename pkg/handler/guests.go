@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type guestID struct {
+	ID int64 `json:"guest_id"`
+}
+
+// guestRequest is the body of a new guest,
+// the id of the guest is not accepted
+type guestRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Document string `json:"document_number"`
+}
+
+// example request:
+//		http://localhost/guests
+// JSON body, phone and document_number are optional:
+//		{"name": "Anna Smith", "email": "anna@example.com",
+//		 "phone": "+1 555 010 0000", "document_number": "AB123456"}
+// the email is unique among the guests
+func (h *Handler) addGuest(w http.ResponseWriter, r *http.Request) {
+	var req guestRequest
+	err := decodeJSON(r, &req)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+	guest := pkg.Guest{
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Document: req.Document,
+	}
+
+	id := guestID{}
+	id.ID, err = h.services.Guests.Add(r.Context(), &guest)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/guests?email=anna@example.com
+func (h *Handler) getGuestByEmail(w http.ResponseWriter, r *http.Request) {
+	guest, err := h.services.Guests.GetByEmail(r.Context(), r.URL.Query().Get("email"))
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(guest)
+}
+
+// example request:
+//		http://localhost/guests/3
+func (h *Handler) getGuest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	guest, err := h.services.Guests.GetByID(r.Context(), id)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(guest)
+}
+
+// example request:
+//		http://localhost/guests/3/bookings
+// returns the bookings of the guest sorted by start date
+func (h *Handler) getGuestBookings(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	bookings, err := h.services.Guests.GetBookings(r.Context(), id)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(bookings)
+}
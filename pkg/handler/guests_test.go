@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+	mock_service "github.com/Avepa/booking/pkg/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandler_addGuest(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockGuests)
+
+	tests := []struct {
+		name                 string
+		input                string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			input: `{"name": "Anna", "email": "anna@example.com", "phone": "+15550100", "document_number": "AB123"}`,
+			mock: func(r *mock_service.MockGuests) {
+				guest := &pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "+15550100", Document: "AB123"}
+				r.EXPECT().Add(gomock.Any(), guest).Return(int64(2), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"guest_id":2}`,
+		},
+		{
+			name:  "Guest exists",
+			input: `{"name": "Anna", "email": "anna@example.com"}`,
+			mock: func(r *mock_service.MockGuests) {
+				guest := &pkg.Guest{Name: "Anna", Email: "anna@example.com"}
+				r.EXPECT().Add(gomock.Any(), guest).Return(int64(0), pkg.ErrGuestExists)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: errorJSON(pkg.ErrGuestExists),
+		},
+		{
+			name:                 "Unknown field",
+			input:                `{"name": "Anna", "age": 30}`,
+			mock:                 func(r *mock_service.MockGuests) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrBodyNotValid),
+		},
+		{
+			name:                 "Server field",
+			input:                `{"guest_id": 7, "name": "Anna", "email": "anna@example.com"}`,
+			mock:                 func(r *mock_service.MockGuests) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrBodyNotValid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGuests(c)
+			tt.mock(repo)
+
+			services := &service.Service{Guests: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/guests", "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+			}
+			var body, expected interface{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			json.Unmarshal([]byte(tt.expectedResponseBody), &expected)
+			if !reflect.DeepEqual(body, expected) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_getGuest(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	guest := &pkg.Guest{ID: 2, Name: "Anna", Email: "anna@example.com"}
+
+	repo := mock_service.NewMockGuests(c)
+	repo.EXPECT().GetByEmail(gomock.Any(), "anna@example.com").Return(guest, nil)
+	repo.EXPECT().GetByEmail(gomock.Any(), "bob@example.com").Return(nil, pkg.ErrIDNotFound)
+	repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(guest, nil)
+
+	services := &service.Service{Guests: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	for _, path := range []string{"/guests?email=anna@example.com", "/guests/2"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := pkg.Guest{}
+		json.NewDecoder(resp.Body).Decode(&got)
+		if resp.StatusCode != http.StatusOK || got != *guest {
+			t.Error("wrong guest received: ", path, resp.StatusCode, got)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/guests?email=bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
+
+func TestHandler_getGuestBookings(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	bookings := []pkg.Booking{
+		{ID: 4, RoomID: 1, GuestID: 2, Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusConfirmed},
+	}
+
+	repo := mock_service.NewMockGuests(c)
+	repo.EXPECT().GetBookings(gomock.Any(), int64(2)).Return(bookings, nil)
+	repo.EXPECT().GetBookings(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+
+	services := &service.Service{Guests: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/guests/2/bookings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []pkg.Booking{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, bookings) {
+		t.Error("wrong bookings received: ", resp.StatusCode, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/guests/3/bookings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/gorilla/mux"
 
+	"github.com/Avepa/booking/pkg"
 	"github.com/Avepa/booking/pkg/service"
 )
 
@@ -14,16 +15,58 @@ func NewHandler(services *service.Service) *Handler {
 	return &Handler{services: services}
 }
 
+// Every request needs an API key, read-only keys may only read,
+// staff keys also manage bookings and guests,
+// admin keys manage properties, rooms, types and rates too.
 func (h *Handler) Routes() *mux.Router {
 	router := mux.NewRouter()
+	router.Use(h.authenticate)
 
-	router.HandleFunc("/room/add", h.addRoom).Methods("POST")
-	router.HandleFunc("/room/list", h.getRoom).Methods("GET")
-	router.HandleFunc("/room/delete", h.deleteRoom).Methods("DELETE")
+	router.HandleFunc("/properties", allow(pkg.RoleAdmin, h.addProperty)).Methods("POST")
+	router.HandleFunc("/properties", allow(pkg.RoleReadOnly, h.getProperties)).Methods("GET")
+	router.HandleFunc("/properties/{id:[0-9]+}", allow(pkg.RoleReadOnly, h.getProperty)).Methods("GET")
+	router.HandleFunc("/properties/{id:[0-9]+}", allow(pkg.RoleAdmin, h.updateProperty)).Methods("PUT")
 
-	router.HandleFunc("/bookings/create", h.createBooking).Methods("POST")
-	router.HandleFunc("/bookings/list", h.getBookings).Methods("GET")
-	router.HandleFunc("/bookings/delete", h.deleteBookings).Methods("DELETE")
+	// the same routes scoped to one property,
+	// the routes without the prefix see all properties
+	scoped := router.PathPrefix("/properties/{property:[0-9]+}").Subrouter()
+	scoped.Use(h.propertyScope)
+	h.propertyRoutes(scoped)
+	h.propertyRoutes(router)
+
+	router.HandleFunc("/guests", allow(pkg.RoleStaff, h.addGuest)).Methods("POST")
+	router.HandleFunc("/guests", allow(pkg.RoleReadOnly, h.getGuestByEmail)).Methods("GET")
+	router.HandleFunc("/guests/{id:[0-9]+}", allow(pkg.RoleReadOnly, h.getGuest)).Methods("GET")
+	router.HandleFunc("/guests/{id:[0-9]+}/bookings", allow(pkg.RoleReadOnly, h.getGuestBookings)).Methods("GET")
 
 	return router
 }
+
+// registers the routes of rooms, room types, rate plans and bookings
+func (h *Handler) propertyRoutes(router *mux.Router) {
+	router.HandleFunc("/room/add", allow(pkg.RoleAdmin, h.addRoom)).Methods("POST")
+	router.HandleFunc("/room/list", allow(pkg.RoleReadOnly, h.getRoom)).Methods("GET")
+	router.HandleFunc("/room/delete", allow(pkg.RoleAdmin, h.deleteRoom)).Methods("DELETE")
+	router.HandleFunc("/room/{id:[0-9]+}", allow(pkg.RoleAdmin, h.updateRoom)).Methods("PUT", "PATCH")
+	router.HandleFunc("/room/{id:[0-9]+}/restore", allow(pkg.RoleAdmin, h.restoreRoom)).Methods("POST")
+	router.HandleFunc("/rooms/available", allow(pkg.RoleReadOnly, h.getAvailableRooms)).Methods("GET")
+	router.HandleFunc("/rooms/{id:[0-9]+}/quote", allow(pkg.RoleReadOnly, h.getQuote)).Methods("GET")
+	router.HandleFunc("/rooms/{id:[0-9]+}/rates", allow(pkg.RoleAdmin, h.addRatePlan)).Methods("POST")
+	router.HandleFunc("/rooms/{id:[0-9]+}/rates", allow(pkg.RoleReadOnly, h.getRatePlans)).Methods("GET")
+	router.HandleFunc("/rates/{id:[0-9]+}", allow(pkg.RoleAdmin, h.updateRatePlan)).Methods("PUT")
+	router.HandleFunc("/rates/{id:[0-9]+}", allow(pkg.RoleAdmin, h.deleteRatePlan)).Methods("DELETE")
+
+	router.HandleFunc("/room-types", allow(pkg.RoleAdmin, h.addRoomType)).Methods("POST")
+	router.HandleFunc("/room-types", allow(pkg.RoleReadOnly, h.getRoomTypes)).Methods("GET")
+	router.HandleFunc("/room-types/{id:[0-9]+}", allow(pkg.RoleReadOnly, h.getRoomType)).Methods("GET")
+	router.HandleFunc("/room-types/{id:[0-9]+}", allow(pkg.RoleAdmin, h.updateRoomType)).Methods("PUT")
+
+	router.HandleFunc("/bookings/create", allow(pkg.RoleStaff, h.createBooking)).Methods("POST")
+	router.HandleFunc("/bookings/list", allow(pkg.RoleReadOnly, h.getBookings)).Methods("GET")
+	router.HandleFunc("/bookings/delete", allow(pkg.RoleStaff, h.deleteBookings)).Methods("DELETE")
+	router.HandleFunc("/bookings/{id:[0-9]+}", allow(pkg.RoleStaff, h.updateBooking)).Methods("PATCH")
+	router.HandleFunc(
+		"/bookings/{id:[0-9]+}/{action:confirm|check-in|check-out|cancel|no-show}",
+		allow(pkg.RoleStaff, h.changeBookingStatus),
+	).Methods("POST")
+}
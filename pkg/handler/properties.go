@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+)
+
+type propertyID struct {
+	ID int64 `json:"property_id"`
+}
+
+// propertyRequest is the body of a new or replaced property,
+// the id of the property is not accepted
+type propertyRequest struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Timezone string `json:"timezone"`
+	Currency string `json:"currency"`
+}
+
+// decodes a propertyRequest from the body
+func decodeProperty(r *http.Request) (pkg.Property, error) {
+	var req propertyRequest
+	err := decodeJSON(r, &req)
+	if err != nil {
+		return pkg.Property{}, err
+	}
+
+	return pkg.Property{
+		Name:     req.Name,
+		Address:  req.Address,
+		Timezone: req.Timezone,
+		Currency: req.Currency,
+	}, nil
+}
+
+// example request:
+//		http://localhost/properties
+// JSON body, the address is optional, the timezone is UTC if it is not set:
+//		{"name": "Seaside", "address": "1 Beach Road",
+//		 "timezone": "Europe/Lisbon", "currency": "EUR"}
+// rooms and types of the property are priced in its currency
+func (h *Handler) addProperty(w http.ResponseWriter, r *http.Request) {
+	p, err := decodeProperty(r)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	id := propertyID{}
+	id.ID, err = h.services.Properties.Add(r.Context(), &p)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/properties
+// returns all properties sorted by id
+func (h *Handler) getProperties(w http.ResponseWriter, r *http.Request) {
+	properties, err := h.services.Properties.List(r.Context())
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(properties)
+}
+
+// example request:
+//		http://localhost/properties/2
+func (h *Handler) getProperty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	p, err := h.services.Properties.GetByID(r.Context(), id)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(p)
+}
+
+// example request:
+//		http://localhost/properties/2
+// replaces the property, the body is the same as when it is added,
+// returns the saved property
+func (h *Handler) updateProperty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	p, err := decodeProperty(r)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	saved, err := h.services.Properties.Update(r.Context(), id, &p)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(saved)
+}
+
+// scopes the requests under /properties/{property} to the property,
+// returns 404 if it does not exist
+func (h *Handler) propertyScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(mux.Vars(r)["property"], 10, 64)
+		if err != nil {
+			HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+			return
+		}
+
+		_, err = h.services.Properties.GetByID(r.Context(), id)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(service.WithProperty(r.Context(), id)))
+	})
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+	mock_service "github.com/Avepa/booking/pkg/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandler_addProperty(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockProperties)
+
+	tests := []struct {
+		name                 string
+		input                string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			input: `{"name": "Seaside", "address": "1 Beach Road", "timezone": "Europe/Lisbon", "currency": "EUR"}`,
+			mock: func(r *mock_service.MockProperties) {
+				p := &pkg.Property{Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"}
+				r.EXPECT().Add(gomock.Any(), p).Return(int64(2), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"property_id":2}`,
+		},
+		{
+			name:  "Timezone not valid",
+			input: `{"name": "Seaside", "timezone": "Europe/Atlantis", "currency": "EUR"}`,
+			mock: func(r *mock_service.MockProperties) {
+				p := &pkg.Property{Name: "Seaside", Timezone: "Europe/Atlantis", Currency: "EUR"}
+				r.EXPECT().Add(gomock.Any(), p).Return(int64(0), pkg.ErrTimezoneNotValid)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrTimezoneNotValid),
+		},
+		{
+			name:                 "Unknown field",
+			input:                `{"name": "Seaside", "stars": 4}`,
+			mock:                 func(r *mock_service.MockProperties) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrBodyNotValid),
+		},
+		{
+			name:                 "Server field",
+			input:                `{"property_id": 7, "name": "Seaside", "timezone": "Europe/Lisbon", "currency": "EUR"}`,
+			mock:                 func(r *mock_service.MockProperties) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrBodyNotValid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockProperties(c)
+			tt.mock(repo)
+
+			services := &service.Service{Properties: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/properties", "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+			}
+			var body, expected interface{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			json.Unmarshal([]byte(tt.expectedResponseBody), &expected)
+			if !reflect.DeepEqual(body, expected) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_getProperties(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	properties := []pkg.Property{
+		{ID: 1, Name: "Default", Timezone: "UTC", Currency: "USD"},
+		{ID: 2, Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"},
+	}
+
+	repo := mock_service.NewMockProperties(c)
+	repo.EXPECT().List(gomock.Any()).Return(properties, nil)
+	repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&properties[1], nil)
+	repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+
+	services := &service.Service{Properties: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []pkg.Property{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, properties) {
+		t.Error("wrong properties received: ", resp.StatusCode, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/properties/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seaside := pkg.Property{}
+	json.NewDecoder(resp.Body).Decode(&seaside)
+	if resp.StatusCode != http.StatusOK || seaside != properties[1] {
+		t.Error("wrong property received: ", resp.StatusCode, seaside)
+	}
+
+	resp, err = http.Get(srv.URL + "/properties/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
+
+func TestHandler_updateProperty(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	seaside := &pkg.Property{Name: "Seaside", Address: "2 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"}
+	saved := *seaside
+	saved.ID = 2
+
+	repo := mock_service.NewMockProperties(c)
+	repo.EXPECT().Update(gomock.Any(), int64(2), seaside).Return(&saved, nil)
+
+	services := &service.Service{Properties: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	req, err := http.NewRequest(
+		"PUT",
+		srv.URL+"/properties/2",
+		strings.NewReader(`{"name": "Seaside", "address": "2 Beach Road", "timezone": "Europe/Lisbon", "currency": "EUR"}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := pkg.Property{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || got != saved {
+		t.Error("wrong property received: ", resp.StatusCode, got)
+	}
+
+	// the id of the property is taken only from the path
+	req, err = http.NewRequest(
+		"PUT",
+		srv.URL+"/properties/2",
+		strings.NewReader(`{"property_id": 3, "name": "Seaside", "timezone": "Europe/Lisbon", "currency": "EUR"}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
+
+func TestHandler_propertyScope(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	properties := mock_service.NewMockProperties(c)
+	properties.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.Property{ID: 2, Name: "Seaside"}, nil).Times(2)
+	properties.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+	rooms := mock_service.NewMockRoom(c)
+	rooms.EXPECT().Get(gomock.Any(), "price", "", &pkg.RoomFilter{}).Return(&pkg.RoomPage{Rooms: []pkg.Room{}}, nil)
+	types := mock_service.NewMockRoomTypes(c)
+	types.EXPECT().List(gomock.Any()).Return([]pkg.RoomType{}, nil)
+
+	services := &service.Service{Properties: properties, Room: rooms, RoomTypes: types}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	for _, path := range []string{"/properties/2/room/list?sorting=price", "/properties/2/room-types"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Error("wrong status code received: ", path, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/properties/3/room/list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body Error
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusNotFound || !reflect.DeepEqual(body, errorBody(pkg.ErrIDNotFound)) {
+		t.Error("wrong error received: ", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type ratePlanID struct {
+	ID int64 `json:"rate_id"`
+}
+
+// example request:
+//		http://localhost/rooms/12/rates
+// JSON body, the price must be in the currency of the room:
+//		{"name": "summer weekends", "date_start": "2021-06-01", "date_end": "2021-09-01",
+//		 "weekdays": ["fri", "sat"], "price": {"amount": "7.50", "currency": "USD"}, "priority": 1}
+// date_start, date_end and weekdays are optional,
+// of the plans for the same night the one with the highest priority is used
+func (h *Handler) addRatePlan(w http.ResponseWriter, r *http.Request) {
+	room, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	var plan pkg.RatePlan
+	err = decodeJSON(r, &plan)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	id := ratePlanID{}
+	id.ID, err = h.services.RatePlans.Add(r.Context(), room, &plan)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/rooms/12/rates
+// returns the rate plans of the room sorted by id
+func (h *Handler) getRatePlans(w http.ResponseWriter, r *http.Request) {
+	room, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	plans, err := h.services.RatePlans.Get(r.Context(), room)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(plans)
+}
+
+// example request:
+//		http://localhost/rates/3
+// replaces the plan, the body is the same as when it is added,
+// returns the saved plan
+func (h *Handler) updateRatePlan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	var plan pkg.RatePlan
+	err = decodeJSON(r, &plan)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	saved, err := h.services.RatePlans.Update(r.Context(), id, &plan)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(saved)
+}
+
+// example request:
+//		http://localhost/rates/3
+// the totals of the bookings made with the plan do not change
+func (h *Handler) deleteRatePlan(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	err = h.services.RatePlans.Delete(r.Context(), id)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	s := Status{
+		Status: "ok",
+	}
+
+	json.NewEncoder(w).Encode(s)
+}
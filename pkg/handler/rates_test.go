@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+	mock_service "github.com/Avepa/booking/pkg/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandler_addRatePlan(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRatePlans)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		input                string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			path: "/rooms/12/rates",
+			input: `{"name": "summer weekends", "date_start": "2018-06-01", "date_end": "2018-09-01",` +
+				` "weekdays": ["fri", "sat"], "price": {"amount": "7.50", "currency": "USD"}, "priority": 1}`,
+			mock: func(r *mock_service.MockRatePlans) {
+				plan := &pkg.RatePlan{
+					Name:     "summer weekends",
+					Start:    "2018-06-01",
+					End:      "2018-09-01",
+					Weekdays: 1<<time.Friday | 1<<time.Saturday,
+					Price:    pkg.Money{Amount: 750, Currency: "USD"},
+					Priority: 1,
+				}
+				r.EXPECT().Add(gomock.Any(), int64(12), plan).Return(int64(3), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"rate_id":3}`,
+		},
+		{
+			name:                 "Weekday not valid",
+			path:                 "/rooms/12/rates",
+			input:                `{"weekdays": ["holiday"], "price": "7.50"}`,
+			mock:                 func(r *mock_service.MockRatePlans) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrWeekdayNotValid),
+		},
+		{
+			name:  "Room not found",
+			path:  "/rooms/13/rates",
+			input: `{"price": "7.50"}`,
+			mock: func(r *mock_service.MockRatePlans) {
+				plan := &pkg.RatePlan{Price: pkg.Money{Amount: 750, Currency: "USD"}}
+				r.EXPECT().Add(gomock.Any(), int64(13), plan).Return(int64(0), pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorJSON(pkg.ErrIDNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRatePlans(c)
+			tt.mock(repo)
+
+			services := &service.Service{RatePlans: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+tt.path, "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+			}
+			var body, expected interface{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			json.Unmarshal([]byte(tt.expectedResponseBody), &expected)
+			if !reflect.DeepEqual(body, expected) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_getRatePlans(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	plans := []pkg.RatePlan{
+		{ID: 1, RoomID: 12, Name: "weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 750, Currency: "USD"}},
+		{ID: 2, RoomID: 12, Name: "summer", Start: "2018-06-01", End: "2018-09-01", Price: pkg.Money{Amount: 900, Currency: "USD"}},
+	}
+
+	repo := mock_service.NewMockRatePlans(c)
+	repo.EXPECT().Get(gomock.Any(), int64(12)).Return(plans, nil)
+	repo.EXPECT().Get(gomock.Any(), int64(13)).Return(nil, pkg.ErrIDNotFound)
+
+	services := &service.Service{RatePlans: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/rooms/12/rates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []pkg.RatePlan{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, plans) {
+		t.Error("wrong plans received: ", resp.StatusCode, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/rooms/13/rates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
+
+func TestHandler_updateRatePlan(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	plan := &pkg.RatePlan{Name: "high season", Start: "2018-07-01", Price: pkg.Money{Amount: 1200, Currency: "USD"}}
+	saved := *plan
+	saved.ID, saved.RoomID = 3, 12
+
+	repo := mock_service.NewMockRatePlans(c)
+	repo.EXPECT().Update(gomock.Any(), int64(3), plan).Return(&saved, nil)
+
+	services := &service.Service{RatePlans: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	req, err := http.NewRequest(
+		"PUT",
+		srv.URL+"/rates/3",
+		strings.NewReader(`{"name": "high season", "date_start": "2018-07-01", "price": "12"}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := pkg.RatePlan{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || got != saved {
+		t.Error("wrong plan received: ", resp.StatusCode, got)
+	}
+}
+
+func TestHandler_deleteRatePlan(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_service.NewMockRatePlans(c)
+	repo.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(4)).Return(pkg.ErrIDNotFound)
+
+	services := &service.Service{RatePlans: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	for path, status := range map[string]int{
+		"/rates/3": http.StatusOK,
+		"/rates/4": http.StatusNotFound,
+	} {
+		req, err := http.NewRequest("DELETE", srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != status {
+			t.Error("wrong status received: ", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// maximum size of a JSON request body
+const maxBodySize = 1 << 20
+
+// reports whether the request body is declared as JSON
+func isJSON(r *http.Request) bool {
+	t, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && t == "application/json"
+}
+
+// decodes a single JSON value from the request body into v,
+// bodies larger than maxBodySize and unknown fields are rejected
+func decodeJSON(r *http.Request, v interface{}) error {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return pkg.ErrBodyNotValid.Wrap(err)
+	}
+	if len(body) > maxBodySize {
+		return pkg.ErrBodyTooLarge
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(v)
+	if err != nil {
+		// errors of the fields with their own decoding, e.g. pkg.Money
+		var e *pkg.Error
+		if errors.As(err, &e) {
+			return err
+		}
+		return pkg.ErrBodyNotValid.Wrap(err)
+	}
+	if dec.More() {
+		return pkg.ErrBodyNotValid
+	}
+
+	return nil
+}
+
+// marks the response of a request
+// that passed its data in the headers
+func deprecatedHeaders(w http.ResponseWriter) {
+	w.Header().Set("Deprecation", "true")
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"github.com/gorilla/mux"
+
 	"github.com/Avepa/booking/pkg"
 )
 
@@ -13,58 +15,270 @@ type roomID struct {
 	ID int64 `json:"room_id"`
 }
 
+// roomRequest is the body of a new room, it has only
+// the fields a client may set, so the id and the date
+// of the room are not accepted
+type roomRequest struct {
+	PropertyID  int64     `json:"property_id"`
+	TypeID      int64     `json:"type_id"`
+	Description string    `json:"description"`
+	Price       pkg.Money `json:"price"`
+	MaxAdults   int       `json:"max_adults"`
+	MaxChildren int       `json:"max_children"`
+}
+
 // example request:
 //		http://localhost/room/add
-// header that are used:
+// JSON body, the amount is a decimal string:
+//		{"description": "room with one bed", "price": {"amount": "5.41", "currency": "USD"},
+//		 "max_adults": 2, "max_children": 1}
+// max_adults is 2 and max_children is 0 if not passed,
+// a room of a type is added with only its type_id:
+//		{"type_id": 2}
+// the room goes to the property of its type or of property_id,
+// the default property 1 if neither is passed,
+// /properties/2/room/add adds it to the property 2
+// deprecated headers, used when the body is not JSON:
 //		"description",
-//		"price"
+//		"price",
+//		"currency" - USD if not passed
 func (h *Handler) addRoom(w http.ResponseWriter, r *http.Request) {
 	var err error
 	var room pkg.Room
 
-	room.Description = r.Header.Get("description")
-	price := r.Header.Get("price")
-	room.Price, err = strconv.ParseFloat(price, 64)
+	if isJSON(r) {
+		var req roomRequest
+		err = decodeJSON(r, &req)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+		room = pkg.Room{
+			PropertyID:  req.PropertyID,
+			TypeID:      req.TypeID,
+			Description: req.Description,
+			Price:       req.Price,
+			MaxAdults:   req.MaxAdults,
+			MaxChildren: req.MaxChildren,
+		}
+	} else {
+		deprecatedHeaders(w)
+		room.Description = r.Header.Get("description")
+		currency := r.Header.Get("currency")
+		if currency == "" {
+			currency = pkg.DefaultCurrency
+		}
+		room.Price, err = pkg.ParseMoney(r.Header.Get("price"), currency)
+		if err != nil {
+			HTTPError(w, err)
+			return
+		}
+	}
+
+	id := roomID{}
+	id.ID, err = h.services.Room.Add(r.Context(), &room)
 	if err != nil {
-		err = pkg.ErrPriceNotValid
-		log.Println(err)
-		HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, err)
 		return
 	}
 
-	id := roomID{}
-	id.ID, err = h.services.Room.Add(&room)
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/room/12
+// PATCH changes only the passed fields:
+//		{"price": {"amount": "6.50", "currency": "USD"}}
+// PUT requires the description and the price, the capacity is optional:
+//		{"description": "room with two beds", "price": {"amount": "6.50", "currency": "USD"},
+//		 "max_adults": 3, "max_children": 0}
+// a room of a type is changed only through its type
+func (h *Handler) updateRoom(w http.ResponseWriter, r *http.Request) {
+	room, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
 	if err != nil {
-		log.Println(err)
-		if err == pkg.ErrFailedSave {
-			HTTPError(w, err.Error(), http.StatusInternalServerError)
-		} else {
-			HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	var update pkg.RoomUpdate
+	err = decodeJSON(r, &update)
+	if err == nil && r.Method == http.MethodPut {
+		if update.Description == nil || update.Price == nil {
+			err = pkg.ErrBodyNotValid
 		}
+	}
+	if err != nil {
+		HTTPError(w, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(id)
+	err = h.services.Room.Update(r.Context(), room, &update)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	s := Status{
+		Status: "ok",
+	}
+
+	json.NewEncoder(w).Encode(s)
 }
 
 // example request:
 //		http://localhost/room/list?sorting=data
 // default rooms are sorted by descending date
 // rooms can be sorted by:
-//	 price increase - price;
-//	 descending price - price_desc;
+//	 price increase - price, grouped by currency;
+//	 descending price - price_desc, grouped by currency;
 //	 date - date;
 //   descending date - date_desc, лиюо, любое другое значение
+//
+// optional parameters:
+//	 limit - page size, from 1 to 100, 20 by default;
+//	 cursor - next_cursor of the previous page;
+//	 currency - only rooms with prices in the currency;
+//	 min_price, max_price - price range, requires currency;
+//	 adults, children - only rooms with places for so many adults and children,
+//	   guests is the same as adults;
+//	 type_id - only rooms of the type;
+//	 date_start, date_end - the stay, each room gets stay_price,
+//	   the price of the stay at the rates of its rate plans.
+// /properties/2/room/list lists only the rooms of the property.
 func (h *Handler) getRoom(w http.ResponseWriter, r *http.Request) {
-	sort := r.URL.Query().Get("sorting")
-	rooms, err := h.services.Room.Get(sort)
-	if err != nil {
-		log.Println(err)
-		HTTPError(
-			w,
-			http.StatusText(http.StatusInternalServerError),
-			http.StatusInternalServerError,
-		)
+	query := r.URL.Query()
+	sort := query.Get("sorting")
+
+	var err error
+	filter := pkg.RoomFilter{
+		Currency:  query.Get("currency"),
+		StayStart: query.Get("date_start"),
+		StayEnd:   query.Get("date_end"),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 {
+			HTTPError(w, pkg.ErrLimitNotValid)
+			return
+		}
+	}
+
+	filter.MinPrice, err = queryPrice(query.Get("min_price"), filter.Currency)
+	if err == nil {
+		filter.MaxPrice, err = queryPrice(query.Get("max_price"), filter.Currency)
+	}
+	if err == nil {
+		filter.Adults, filter.Children, err = queryGuests(query)
+	}
+	if err == nil {
+		filter.TypeID, err = queryID(query.Get("type_id"))
+	}
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	page, err := h.services.Room.Get(r.Context(), sort, query.Get("cursor"), &filter)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// returns the price in minor units of the currency,
+// or nil for an empty parameter
+func queryPrice(value, currency string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if currency == "" {
+		return nil, pkg.ErrCurrencyNotValid
+	}
+
+	price, err := pkg.ParseMoney(value, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &price.Amount, nil
+}
+
+// returns the numbers of adults and children, zero for empty
+// parameters, guests is the old name of adults
+func queryGuests(query url.Values) (int, int, error) {
+	value := query.Get("adults")
+	if value == "" {
+		value = query.Get("guests")
+	}
+
+	var adults, children int
+	var err error
+	if value != "" {
+		adults, err = strconv.Atoi(value)
+		if err != nil || adults < 1 {
+			return 0, 0, pkg.ErrGuestsNotValid
+		}
+	}
+	if value := query.Get("children"); value != "" {
+		children, err = strconv.Atoi(value)
+		if err != nil || children < 0 {
+			return 0, 0, pkg.ErrGuestsNotValid
+		}
+	}
+
+	return adults, children, nil
+}
+
+// returns the id, or zero for an empty parameter
+func queryID(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 1 {
+		return 0, pkg.ErrIdNotValid
+	}
+
+	return id, nil
+}
+
+// example request:
+//		http://localhost/rooms/available?date_start=2021-01-05&date_end=2021-01-08&sorting=price&adults=2&children=1
+// returns rooms without bookings in the range,
+// sorting types are the same as in /room/list,
+// adults and children are optional, only rooms with places for them,
+// guests is the same as adults,
+// type_id is optional, only rooms of the type
+//
+// date format: 2006-01-02
+func (h *Handler) getAvailableRooms(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	adults, children, err := queryGuests(query)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	typeID, err := queryID(query.Get("type_id"))
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	rooms, err := h.services.Room.GetAvailable(
+		r.Context(),
+		query.Get("date_start"),
+		query.Get("date_end"),
+		query.Get("sorting"),
+		adults,
+		children,
+		typeID,
+	)
+	if err != nil {
+		HTTPError(w, err)
 		return
 	}
 
@@ -72,26 +286,73 @@ func (h *Handler) getRoom(w http.ResponseWriter, r *http.Request) {
 }
 
 // example request:
-//		http://localhost/room/delete?room_id=12
+//		http://localhost/rooms/12/quote?date_start=2021-01-05&date_end=2021-01-08
+// returns the price of each night of the stay and the total
+// at the rates of the room's rate plans, the dates are checked as for a booking
+//
+// date format: 2006-01-02
+func (h *Handler) getQuote(w http.ResponseWriter, r *http.Request) {
+	room, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	query := r.URL.Query()
+	quote, err := h.services.Bookings.Quote(
+		r.Context(),
+		room,
+		query.Get("date_start"),
+		query.Get("date_end"),
+	)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(quote)
+}
+
+// example request:
+//		http://localhost/room/delete?room_id=12&force=true
+// the room is archived, its bookings stay in the history,
+// a room with bookings that have not ended is archived only with force,
+// a room with a checked in guest is not archived
 func (h *Handler) deleteRoom(w http.ResponseWriter, r *http.Request) {
 	id := r.URL.Query().Get("room_id")
 	room, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
-		err = pkg.ErrIdNotValid
-		log.Println(err)
-		HTTPError(w, err.Error(), http.StatusBadRequest)
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
 		return
 	}
 
-	err = h.services.Room.Delete(room)
+	force := r.URL.Query().Get("force") == "true"
+	err = h.services.Room.Delete(r.Context(), room, force)
 	if err != nil {
-		log.Println(err)
-		if err == pkg.ErrFailedDelete {
-			HTTPError(w, err.Error(), http.StatusInternalServerError)
-		}
-		if err == pkg.ErrIDNotFound {
-			HTTPError(w, err.Error(), http.StatusBadRequest)
-		}
+		HTTPError(w, err)
+		return
+	}
+
+	s := Status{
+		Status: "ok",
+	}
+
+	json.NewEncoder(w).Encode(s)
+}
+
+// example request:
+//		http://localhost/room/12/restore
+// returns the archived room to the lists
+func (h *Handler) restoreRoom(w http.ResponseWriter, r *http.Request) {
+	room, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	err = h.services.Room.Restore(r.Context(), room)
+	if err != nil {
+		HTTPError(w, err)
 		return
 	}
 
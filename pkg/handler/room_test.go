@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Avepa/booking/pkg"
@@ -20,6 +22,7 @@ func TestHandler_addRoom(t *testing.T) {
 
 	type input struct {
 		Price       string
+		Currency    string
 		Description string
 	}
 
@@ -44,17 +47,37 @@ func TestHandler_addRoom(t *testing.T) {
 			},
 			inputRoom: &pkg.Room{
 				Description: "Good room",
-				Price:       5.41,
+				Price:       pkg.Money{Amount: 541, Currency: "USD"},
 			},
 			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
 				id := int64(1)
-				r.EXPECT().Add(room).Return(id, nil)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, nil)
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponseBody: result{
 				ID: 1,
 			},
 		},
+		{
+			name: "OK currency",
+			input: input{
+				Description: "Good room",
+				Price:       "5400",
+				Currency:    "JPY",
+			},
+			inputRoom: &pkg.Room{
+				Description: "Good room",
+				Price:       pkg.Money{Amount: 5400, Currency: "JPY"},
+			},
+			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
+				id := int64(2)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 2,
+			},
+		},
 		{
 			name: "Price not valid",
 			input: input{
@@ -75,11 +98,11 @@ func TestHandler_addRoom(t *testing.T) {
 			},
 			inputRoom: &pkg.Room{
 				Description: "Good room",
-				Price:       3.55,
+				Price:       pkg.Money{Amount: 355, Currency: "USD"},
 			},
 			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
 				id := int64(0)
-				r.EXPECT().Add(room).Return(id, pkg.ErrFailedSave)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, pkg.ErrFailedSave)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
 			expectedResponseBody: result{
@@ -94,11 +117,11 @@ func TestHandler_addRoom(t *testing.T) {
 			},
 			inputRoom: &pkg.Room{
 				Description: "Good room",
-				Price:       3.55,
+				Price:       pkg.Money{Amount: 355, Currency: "USD"},
 			},
 			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
 				id := int64(0)
-				r.EXPECT().Add(room).Return(id, pkg.ErrDateIsIncorrect)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, pkg.ErrDateIsIncorrect)
 			},
 			expectedStatusCode: http.StatusBadRequest,
 			expectedResponseBody: result{
@@ -129,6 +152,7 @@ func TestHandler_addRoom(t *testing.T) {
 
 			req.Header.Add("description", tt.input.Description)
 			req.Header.Add("price", tt.input.Price)
+			req.Header.Add("currency", tt.input.Currency)
 
 			client := http.Client{}
 			resp, err := client.Do(req)
@@ -141,6 +165,9 @@ func TestHandler_addRoom(t *testing.T) {
 				t.Error("wrong error code received: ", resp.StatusCode)
 				return
 			}
+			if resp.Header.Get("Deprecation") != "true" {
+				t.Error("header request not marked as deprecated")
+			}
 
 			body := result{}
 			json.NewDecoder(resp.Body).Decode(&body)
@@ -152,70 +179,129 @@ func TestHandler_addRoom(t *testing.T) {
 }
 
 func TestHandler_getRoom(t *testing.T) {
-	type mockBehavior func(r *mock_service.MockRoom, room []pkg.Room, sort string)
+	type mockBehavior func(r *mock_service.MockRoom, page *pkg.RoomPage)
+
+	var minPrice, maxPrice int64 = 450, 600
 
 	tests := []struct {
 		name                 string
-		input                string
+		query                string
 		mock                 mockBehavior
 		expectedStatusCode   int
-		expectedResponseBody []pkg.Room
+		expectedError        string
+		expectedResponseBody *pkg.RoomPage
 	}{
 		{
 			name:  "OK",
-			input: "date",
-			mock: func(r *mock_service.MockRoom, room []pkg.Room, sort string) {
-				r.EXPECT().Get(sort).Return(room, nil)
+			query: "sorting=date",
+			mock: func(r *mock_service.MockRoom, page *pkg.RoomPage) {
+				r.EXPECT().Get(gomock.Any(), "date", "", &pkg.RoomFilter{}).Return(page, nil)
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponseBody: []pkg.Room{
-				{
-					ID:          3,
-					Description: "Good",
-					Price:       4.99,
-					Date:        "2018.01.10",
+			expectedResponseBody: &pkg.RoomPage{
+				Rooms: []pkg.Room{
+					{
+						ID:          3,
+						Description: "Good",
+						Price:       pkg.Money{Amount: 499, Currency: "USD"},
+						Date:        "2018.01.10",
+					},
+					{
+						ID:          2,
+						Description: "Luxury",
+						Price:       pkg.Money{Amount: 599, Currency: "USD"},
+						Date:        "2018.01.08",
+					},
+					{
+						ID:          1,
+						Description: "VIP",
+						Price:       pkg.Money{Amount: 799, Currency: "USD"},
+						Date:        "2018.01.06",
+					},
 				},
-				{
-					ID:          2,
-					Description: "Luxury",
-					Price:       5.99,
-					Date:        "2018.01.08",
+				Total: 3,
+			},
+		},
+		{
+			name:  "OK page",
+			query: "sorting=price&limit=1&cursor=MQ&currency=USD&min_price=4.5&max_price=6",
+			mock: func(r *mock_service.MockRoom, page *pkg.RoomPage) {
+				filter := &pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, MaxPrice: &maxPrice, Limit: 1}
+				r.EXPECT().Get(gomock.Any(), "price", "MQ", filter).Return(page, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: &pkg.RoomPage{
+				Rooms: []pkg.Room{
+					{
+						ID:          2,
+						Description: "Luxury",
+						Price:       pkg.Money{Amount: 599, Currency: "USD"},
+						Date:        "2018.01.08",
+					},
 				},
-				{
-					ID:          1,
-					Description: "VIP",
-					Price:       7.99,
-					Date:        "2018.01.06",
+				NextCursor: "Mg",
+				Total:      2,
+			},
+		},
+		{
+			name:  "OK stay",
+			query: "date_start=2018-01-05&date_end=2018-01-07",
+			mock: func(r *mock_service.MockRoom, page *pkg.RoomPage) {
+				filter := &pkg.RoomFilter{StayStart: "2018-01-05", StayEnd: "2018-01-07"}
+				r.EXPECT().Get(gomock.Any(), "", "", filter).Return(page, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: &pkg.RoomPage{
+				Rooms: []pkg.Room{
+					{
+						ID:          2,
+						Description: "Luxury",
+						Price:       pkg.Money{Amount: 599, Currency: "USD"},
+						Date:        "2018.01.08",
+						StayPrice:   &pkg.Money{Amount: 1398, Currency: "USD"},
+					},
 				},
+				Total: 1,
+			},
+		},
+		{
+			name:               "Limit not valid",
+			query:              "limit=abc",
+			mock:               func(r *mock_service.MockRoom, page *pkg.RoomPage) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      pkg.ErrLimitNotValid.Error(),
+		},
+		{
+			name:               "Price not valid",
+			query:              "currency=USD&max_price=abc",
+			mock:               func(r *mock_service.MockRoom, page *pkg.RoomPage) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      pkg.ErrPriceNotValid.Error(),
+		},
+		{
+			name:               "Price without currency",
+			query:              "min_price=4.5",
+			mock:               func(r *mock_service.MockRoom, page *pkg.RoomPage) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      pkg.ErrCurrencyNotValid.Error(),
+		},
+		{
+			name:  "Cursor not valid",
+			query: "cursor=abc",
+			mock: func(r *mock_service.MockRoom, page *pkg.RoomPage) {
+				r.EXPECT().Get(gomock.Any(), "", "abc", &pkg.RoomFilter{}).Return(nil, pkg.ErrCursorNotValid)
 			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      pkg.ErrCursorNotValid.Error(),
 		},
 		{
 			name:  "Internal server error",
-			input: "date",
-			mock: func(r *mock_service.MockRoom, room []pkg.Room, sort string) {
-				r.EXPECT().Get(sort).Return(nil, sql.ErrConnDone)
+			query: "sorting=date",
+			mock: func(r *mock_service.MockRoom, page *pkg.RoomPage) {
+				r.EXPECT().Get(gomock.Any(), "date", "", &pkg.RoomFilter{}).Return(nil, sql.ErrConnDone)
 			},
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedResponseBody: []pkg.Room{
-				{
-					ID:          3,
-					Description: "Good",
-					Price:       4.99,
-					Date:        "2018.01.10",
-				},
-				{
-					ID:          2,
-					Description: "Luxury",
-					Price:       5.99,
-					Date:        "2018.01.08",
-				},
-				{
-					ID:          1,
-					Description: "VIP",
-					Price:       7.99,
-					Date:        "2018.01.06",
-				},
-			},
+			expectedError:      http.StatusText(http.StatusInternalServerError),
 		},
 	}
 
@@ -225,7 +311,7 @@ func TestHandler_getRoom(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockRoom(c)
-			tt.mock(repo, tt.expectedResponseBody, tt.input)
+			tt.mock(repo, tt.expectedResponseBody)
 
 			services := &service.Service{Room: repo}
 			handler := Handler{services}
@@ -233,8 +319,7 @@ func TestHandler_getRoom(t *testing.T) {
 			srv := httptest.NewServer(h)
 			defer srv.Close()
 
-			url := fmt.Sprintf("%s/?%s=%s", srv.URL, "sorting", tt.input)
-			req, err := http.NewRequest("GET", url, nil)
+			req, err := http.NewRequest("GET", srv.URL+"/?"+tt.query, nil)
 			if err != nil {
 				t.Error(err)
 				return
@@ -253,20 +338,22 @@ func TestHandler_getRoom(t *testing.T) {
 			} else if resp.StatusCode != http.StatusOK {
 				body := Error{}
 				json.NewDecoder(resp.Body).Decode(&body)
-				if body.Err != http.StatusText(http.StatusInternalServerError) {
-					t.Error("wrong error code received: ", resp.StatusCode)
-					return
+				if body.Err != tt.expectedError {
+					t.Error("wrong error received: ", body.Err)
 				}
 				return
 			}
 
-			body := []pkg.Room{}
+			body := pkg.RoomPage{}
 			json.NewDecoder(resp.Body).Decode(&body)
-			if len(body) != len(tt.expectedResponseBody) {
-				t.Error(body)
+			if body.Total != tt.expectedResponseBody.Total ||
+				body.NextCursor != tt.expectedResponseBody.NextCursor ||
+				len(body.Rooms) != len(tt.expectedResponseBody.Rooms) {
+				t.Error("wrong body received: ", body)
+				return
 			}
-			for i := range body {
-				if body[i] != tt.expectedResponseBody[i] {
+			for i := range body.Rooms {
+				if !reflect.DeepEqual(body.Rooms[i], tt.expectedResponseBody.Rooms[i]) {
 					b := fmt.Sprint(body)
 					t.Error("wrong body received: ", b)
 					return
@@ -283,6 +370,7 @@ func TestHandler_deleteRoom(t *testing.T) {
 		name                 string
 		input                int64
 		inputBody            string
+		force                string
 		mock                 mockBehavior
 		expected             Status
 		expectedStatusCode   int
@@ -293,37 +381,58 @@ func TestHandler_deleteRoom(t *testing.T) {
 			input:     1,
 			inputBody: "1",
 			mock: func(r *mock_service.MockRoom, id int64) {
-				r.EXPECT().Delete(id).Return(nil)
+				r.EXPECT().Delete(gomock.Any(), id, false).Return(nil)
+			},
+			expected:           Status{Status: "ok"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:      "OK force",
+			input:     1,
+			inputBody: "1",
+			force:     "true",
+			mock: func(r *mock_service.MockRoom, id int64) {
+				r.EXPECT().Delete(gomock.Any(), id, true).Return(nil)
 			},
 			expected:           Status{Status: "ok"},
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			name:      "Has bookings",
+			input:     4,
+			inputBody: "4",
+			mock: func(r *mock_service.MockRoom, id int64) {
+				r.EXPECT().Delete(gomock.Any(), id, false).Return(pkg.ErrRoomHasBookings)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: errorBody(pkg.ErrRoomHasBookings),
+		},
 		{
 			name:                 "ID not valid",
 			inputBody:            "Adf",
 			mock:                 func(r *mock_service.MockRoom, id int64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: Error{pkg.ErrIdNotValid.Error()},
+			expectedResponseBody: errorBody(pkg.ErrIdNotValid),
 		},
 		{
 			name:      "Failed delete",
 			input:     2,
 			inputBody: "2",
 			mock: func(r *mock_service.MockRoom, id int64) {
-				r.EXPECT().Delete(id).Return(pkg.ErrFailedDelete)
+				r.EXPECT().Delete(gomock.Any(), id, false).Return(pkg.ErrFailedDelete)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: Error{pkg.ErrFailedDelete.Error()},
+			expectedResponseBody: errorBody(pkg.ErrFailedDelete),
 		},
 		{
 			name:      "ID not found",
 			input:     3,
 			inputBody: "3",
 			mock: func(r *mock_service.MockRoom, id int64) {
-				r.EXPECT().Delete(id).Return(pkg.ErrIDNotFound)
+				r.EXPECT().Delete(gomock.Any(), id, false).Return(pkg.ErrIDNotFound)
 			},
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: Error{pkg.ErrIDNotFound.Error()},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
 		},
 	}
 
@@ -341,7 +450,7 @@ func TestHandler_deleteRoom(t *testing.T) {
 			srv := httptest.NewServer(h)
 			defer srv.Close()
 
-			url := fmt.Sprintf("%s/?%s=%s", srv.URL, "room_id", tt.inputBody)
+			url := fmt.Sprintf("%s/?%s=%s&force=%s", srv.URL, "room_id", tt.inputBody, tt.force)
 			req, err := http.NewRequest("DELETE", url, nil)
 			if err != nil {
 				t.Error(err)
@@ -369,9 +478,621 @@ func TestHandler_deleteRoom(t *testing.T) {
 
 			body := Error{}
 			json.NewDecoder(resp.Body).Decode(&body)
-			if body != tt.expectedResponseBody {
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
 				t.Error("wrong body received: ", err)
 			}
 		})
 	}
 }
+
+func TestHandler_getAvailableRooms(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRoom, room []pkg.Room)
+
+	tests := []struct {
+		name                 string
+		start                string
+		end                  string
+		guests               string
+		adults               string
+		children             string
+		typeID               string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody []pkg.Room
+		expectedError        Error
+	}{
+		{
+			name:   "OK",
+			start:  "2018-01-05",
+			end:    "2018-01-10",
+			guests: "3",
+			mock: func(r *mock_service.MockRoom, room []pkg.Room) {
+				r.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-10", "price", 3, 0, int64(0)).
+					Return(room, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: []pkg.Room{
+				{
+					ID:          3,
+					Description: "Good",
+					Price:       pkg.Money{Amount: 499, Currency: "USD"},
+					Date:        "2018.01.10",
+				},
+				{
+					ID:          1,
+					Description: "VIP",
+					Price:       pkg.Money{Amount: 799, Currency: "USD"},
+					Date:        "2018.01.06",
+				},
+			},
+		},
+		{
+			name:  "Date is incorrect",
+			start: "2018.01.05",
+			end:   "2018-01-10",
+			mock: func(r *mock_service.MockRoom, room []pkg.Room) {
+				r.EXPECT().GetAvailable(gomock.Any(), "2018.01.05", "2018-01-10", "price", 0, 0, int64(0)).
+					Return(nil, pkg.ErrDateIsIncorrect)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      errorBody(pkg.ErrDateIsIncorrect),
+		},
+		{
+			name:  "Internal server error",
+			start: "2018-01-05",
+			end:   "2018-01-10",
+			mock: func(r *mock_service.MockRoom, room []pkg.Room) {
+				r.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-10", "price", 0, 0, int64(0)).
+					Return(nil, sql.ErrConnDone)
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedError:      Error{Err: http.StatusText(http.StatusInternalServerError), Code: "internal"},
+		},
+		{
+			name:     "OK adults and children",
+			start:    "2018-01-05",
+			end:      "2018-01-10",
+			adults:   "2",
+			children: "1",
+			mock: func(r *mock_service.MockRoom, room []pkg.Room) {
+				r.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-10", "price", 2, 1, int64(0)).
+					Return(room, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: []pkg.Room{
+				{
+					ID:          3,
+					Description: "Good",
+					Price:       pkg.Money{Amount: 499, Currency: "USD"},
+					Date:        "2018.01.10",
+					MaxAdults:   2,
+					MaxChildren: 1,
+				},
+			},
+		},
+		{
+			name:               "Children not valid",
+			start:              "2018-01-05",
+			end:                "2018-01-10",
+			children:           "-1",
+			mock:               func(r *mock_service.MockRoom, room []pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      errorBody(pkg.ErrGuestsNotValid),
+		},
+		{
+			name:               "Guests not valid",
+			start:              "2018-01-05",
+			end:                "2018-01-10",
+			guests:             "two",
+			mock:               func(r *mock_service.MockRoom, room []pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      errorBody(pkg.ErrGuestsNotValid),
+		},
+		{
+			name:   "OK type",
+			start:  "2018-01-05",
+			end:    "2018-01-10",
+			typeID: "2",
+			mock: func(r *mock_service.MockRoom, room []pkg.Room) {
+				r.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-10", "price", 0, 0, int64(2)).
+					Return(room, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: []pkg.Room{
+				{
+					ID:          4,
+					TypeID:      2,
+					Description: "Double",
+					Price:       pkg.Money{Amount: 12000, Currency: "USD"},
+					Date:        "2018.01.10",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:               "Type not valid",
+			start:              "2018-01-05",
+			end:                "2018-01-10",
+			typeID:             "-2",
+			mock:               func(r *mock_service.MockRoom, room []pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedError:      errorBody(pkg.ErrIdNotValid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRoom(c)
+			tt.mock(repo, tt.expectedResponseBody)
+
+			services := &service.Service{Room: repo}
+			handler := Handler{services}
+			h := http.HandlerFunc(handler.getAvailableRooms)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			url := fmt.Sprintf(
+				"%s/?date_start=%s&date_end=%s&sorting=price&guests=%s&adults=%s&children=%s&type_id=%s",
+				srv.URL, tt.start, tt.end, tt.guests, tt.adults, tt.children, tt.typeID,
+			)
+			req, err := http.NewRequest("GET", url, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			client := http.Client{}
+			resp, err := client.Do(req)
+			if err != nil && err != io.EOF {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode != http.StatusOK {
+				body := Error{}
+				json.NewDecoder(resp.Body).Decode(&body)
+				if !reflect.DeepEqual(body, tt.expectedError) {
+					t.Error("wrong body received: ", body)
+				}
+				return
+			}
+
+			body := []pkg.Room{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if len(body) != len(tt.expectedResponseBody) {
+				t.Error(body)
+			}
+			for i := range body {
+				if body[i] != tt.expectedResponseBody[i] {
+					t.Error("wrong body received: ", body)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_addRoomJSON(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRoom, room *pkg.Room)
+
+	type result struct {
+		ID  int64  `json:"room_id"`
+		Err string `json:"error"`
+	}
+
+	tests := []struct {
+		name                 string
+		input                string
+		inputRoom            *pkg.Room
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody result
+	}{
+		{
+			name:  "OK",
+			input: `{"description": "Комната с видом на море", "price": {"amount": "5.41", "currency": "EUR"}}`,
+			inputRoom: &pkg.Room{
+				Description: "Комната с видом на море",
+				Price:       pkg.Money{Amount: 541, Currency: "EUR"},
+			},
+			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
+				id := int64(1)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 1,
+			},
+		},
+		{
+			name:  "OK number price",
+			input: `{"description": "Good room", "price": 5.41}`,
+			inputRoom: &pkg.Room{
+				Description: "Good room",
+				Price:       pkg.Money{Amount: 541, Currency: "USD"},
+			},
+			mock: func(r *mock_service.MockRoom, room *pkg.Room) {
+				id := int64(1)
+				r.EXPECT().Add(gomock.Any(), room).Return(id, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: result{
+				ID: 1,
+			},
+		},
+		{
+			name:               "Unknown field",
+			input:              `{"description": "Good room", "price": 5.41, "floor": 2}`,
+			mock:               func(r *mock_service.MockRoom, room *pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Server field",
+			input:              `{"room_id": 7, "description": "Good room", "price": 5.41}`,
+			mock:               func(r *mock_service.MockRoom, room *pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Price not valid",
+			input:              `{"description": "Good room", "price": "Ls"}`,
+			mock:               func(r *mock_service.MockRoom, room *pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrPriceNotValid.Error(),
+			},
+		},
+		{
+			name:               "Currency not valid",
+			input:              `{"description": "Good room", "price": {"amount": "5.41", "currency": "XXX"}}`,
+			mock:               func(r *mock_service.MockRoom, room *pkg.Room) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponseBody: result{
+				Err: pkg.ErrCurrencyNotValid.Error(),
+			},
+		},
+		{
+			name:               "Body too large",
+			input:              `{"description": "` + strings.Repeat("a", maxBodySize) + `"}`,
+			mock:               func(r *mock_service.MockRoom, room *pkg.Room) {},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedResponseBody: result{
+				Err: pkg.ErrBodyTooLarge.Error(),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRoom(c)
+			tt.mock(repo, tt.inputRoom)
+
+			services := &service.Service{Room: repo}
+			handler := Handler{services}
+			h := http.HandlerFunc(handler.addRoom)
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL, "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			}
+			if resp.Header.Get("Deprecation") != "" {
+				t.Error("JSON request marked as deprecated")
+			}
+
+			body := result{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if body != tt.expectedResponseBody {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_updateRoom(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRoom, update *pkg.RoomUpdate)
+
+	description := "Good room"
+	price := pkg.Money{Amount: 541, Currency: "USD"}
+
+	tests := []struct {
+		name                 string
+		method               string
+		path                 string
+		input                string
+		inputUpdate          *pkg.RoomUpdate
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody Error
+	}{
+		{
+			name:        "OK patch",
+			method:      "PATCH",
+			path:        "/room/12",
+			input:       `{"price": 5.41}`,
+			inputUpdate: &pkg.RoomUpdate{Price: &price},
+			mock: func(r *mock_service.MockRoom, update *pkg.RoomUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(12), update).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:        "OK put",
+			method:      "PUT",
+			path:        "/room/12",
+			input:       `{"description": "Good room", "price": 5.41}`,
+			inputUpdate: &pkg.RoomUpdate{Description: &description, Price: &price},
+			mock: func(r *mock_service.MockRoom, update *pkg.RoomUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(12), update).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                 "Put without all fields",
+			method:               "PUT",
+			path:                 "/room/12",
+			input:                `{"price": 5.41}`,
+			mock:                 func(r *mock_service.MockRoom, update *pkg.RoomUpdate) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorBody(pkg.ErrBodyNotValid),
+		},
+		{
+			name:        "ID not found",
+			method:      "PATCH",
+			path:        "/room/12",
+			input:       `{"price": 5.41}`,
+			inputUpdate: &pkg.RoomUpdate{Price: &price},
+			mock: func(r *mock_service.MockRoom, update *pkg.RoomUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(12), update).Return(pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
+		},
+		{
+			name:        "Price not valid",
+			method:      "PATCH",
+			path:        "/room/12",
+			input:       `{"price": 5.41}`,
+			inputUpdate: &pkg.RoomUpdate{Price: &price},
+			mock: func(r *mock_service.MockRoom, update *pkg.RoomUpdate) {
+				r.EXPECT().Update(gomock.Any(), int64(12), update).Return(pkg.ErrPriceNotValid)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorBody(pkg.ErrPriceNotValid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRoom(c)
+			tt.mock(repo, tt.inputUpdate)
+
+			services := &service.Service{Room: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.input))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			client := http.Client{}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode == http.StatusOK {
+				s := Status{}
+				json.NewDecoder(resp.Body).Decode(&s)
+				if s.Status != "ok" {
+					t.Error("wrong status received")
+				}
+				return
+			}
+
+			body := Error{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_restoreRoom(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRoom)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody Error
+	}{
+		{
+			name: "OK",
+			path: "/room/12/restore",
+			mock: func(r *mock_service.MockRoom) {
+				r.EXPECT().Restore(gomock.Any(), int64(12)).Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "ID not found",
+			path: "/room/13/restore",
+			mock: func(r *mock_service.MockRoom) {
+				r.EXPECT().Restore(gomock.Any(), int64(13)).Return(pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
+		},
+		{
+			name: "Failed save",
+			path: "/room/14/restore",
+			mock: func(r *mock_service.MockRoom) {
+				r.EXPECT().Restore(gomock.Any(), int64(14)).Return(pkg.ErrFailedSave)
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: errorBody(pkg.ErrFailedSave),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRoom(c)
+			tt.mock(repo)
+
+			services := &service.Service{Room: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+tt.path, "", nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode == http.StatusOK {
+				s := Status{}
+				json.NewDecoder(resp.Body).Decode(&s)
+				if s.Status != "ok" {
+					t.Error("wrong status received")
+				}
+				return
+			}
+
+			body := Error{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_getQuote(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockBookings)
+
+	price := pkg.Money{Amount: 1999, Currency: "USD"}
+	quote := &pkg.Quote{
+		RoomID: 12,
+		Start:  "2018-01-05",
+		End:    "2018-01-07",
+		Nights: 2,
+		Lines: []pkg.QuoteLine{
+			{Date: "2018-01-05", Price: price},
+			{Date: "2018-01-06", Price: price},
+		},
+		Total: pkg.Money{Amount: 3998, Currency: "USD"},
+	}
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody Error
+	}{
+		{
+			name: "OK",
+			path: "/rooms/12/quote?date_start=2018-01-05&date_end=2018-01-07",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().Quote(gomock.Any(), int64(12), "2018-01-05", "2018-01-07").Return(quote, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "ID not found",
+			path: "/rooms/13/quote?date_start=2018-01-05&date_end=2018-01-07",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().Quote(gomock.Any(), int64(13), "2018-01-05", "2018-01-07").Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: errorBody(pkg.ErrIDNotFound),
+		},
+		{
+			name: "Date is incorrect",
+			path: "/rooms/12/quote?date_start=2018.01.05",
+			mock: func(r *mock_service.MockBookings) {
+				r.EXPECT().Quote(gomock.Any(), int64(12), "2018.01.05", "").Return(nil, pkg.ErrDateIsIncorrect)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorBody(pkg.ErrDateIsIncorrect),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockBookings(c)
+			tt.mock(repo)
+
+			services := &service.Service{Bookings: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+				return
+			} else if resp.StatusCode == http.StatusOK {
+				q := &pkg.Quote{}
+				json.NewDecoder(resp.Body).Decode(q)
+				if !reflect.DeepEqual(q, quote) {
+					t.Error("wrong quote received: ", q)
+				}
+				return
+			}
+
+			body := Error{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			if !reflect.DeepEqual(body, tt.expectedResponseBody) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type roomTypeID struct {
+	ID int64 `json:"type_id"`
+}
+
+// example request:
+//		http://localhost/room-types
+// JSON body, the capacity is optional like for a room:
+//		{"name": "Double Deluxe", "description": "two beds, sea view",
+//		 "price": {"amount": "120.00", "currency": "USD"}, "max_adults": 2, "max_children": 1}
+// rooms added with the type_id take its description, price and capacity
+func (h *Handler) addRoomType(w http.ResponseWriter, r *http.Request) {
+	var t pkg.RoomType
+	err := decodeJSON(r, &t)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	id := roomTypeID{}
+	id.ID, err = h.services.RoomTypes.Add(r.Context(), &t)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(id)
+}
+
+// example request:
+//		http://localhost/room-types
+// returns all types sorted by id
+func (h *Handler) getRoomTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := h.services.RoomTypes.List(r.Context())
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(types)
+}
+
+// example request:
+//		http://localhost/room-types/2
+func (h *Handler) getRoomType(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	t, err := h.services.RoomTypes.GetByID(r.Context(), id)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+// example request:
+//		http://localhost/room-types/2
+// replaces the type, the body is the same as when it is added,
+// the rooms of the type get the new description, price and capacity,
+// returns the saved type
+func (h *Handler) updateRoomType(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		HTTPError(w, pkg.ErrIdNotValid.Wrap(err))
+		return
+	}
+
+	var t pkg.RoomType
+	err = decodeJSON(r, &t)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	saved, err := h.services.RoomTypes.Update(r.Context(), id, &t)
+	if err != nil {
+		HTTPError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(saved)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/service"
+	mock_service "github.com/Avepa/booking/pkg/service/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandler_addRoomType(t *testing.T) {
+	type mockBehavior func(r *mock_service.MockRoomTypes)
+
+	tests := []struct {
+		name                 string
+		input                string
+		mock                 mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			input: `{"name": "Double Deluxe", "description": "two beds",` +
+				` "price": {"amount": "120.00", "currency": "USD"}, "max_adults": 2, "max_children": 1}`,
+			mock: func(r *mock_service.MockRoomTypes) {
+				double := &pkg.RoomType{
+					Name:        "Double Deluxe",
+					Description: "two beds",
+					Price:       pkg.Money{Amount: 12000, Currency: "USD"},
+					MaxAdults:   2,
+					MaxChildren: 1,
+				}
+				r.EXPECT().Add(gomock.Any(), double).Return(int64(2), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"type_id":2}`,
+		},
+		{
+			name:  "Name not valid",
+			input: `{"name": " ", "price": "120"}`,
+			mock: func(r *mock_service.MockRoomTypes) {
+				t := &pkg.RoomType{Name: " ", Price: pkg.Money{Amount: 12000, Currency: "USD"}}
+				r.EXPECT().Add(gomock.Any(), t).Return(int64(0), pkg.ErrNameNotValid)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrNameNotValid),
+		},
+		{
+			name:                 "Unknown field",
+			input:                `{"name": "Single", "rooms": 4}`,
+			mock:                 func(r *mock_service.MockRoomTypes) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: errorJSON(pkg.ErrBodyNotValid),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRoomTypes(c)
+			tt.mock(repo)
+
+			services := &service.Service{RoomTypes: repo}
+			srv := httptest.NewServer(adminRoutes(c, services))
+			defer srv.Close()
+
+			resp, err := http.Post(srv.URL+"/room-types", "application/json", strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.expectedStatusCode {
+				t.Error("wrong error code received: ", resp.StatusCode)
+			}
+			var body, expected interface{}
+			json.NewDecoder(resp.Body).Decode(&body)
+			json.Unmarshal([]byte(tt.expectedResponseBody), &expected)
+			if !reflect.DeepEqual(body, expected) {
+				t.Error("wrong body received: ", body)
+			}
+		})
+	}
+}
+
+func TestHandler_getRoomTypes(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	types := []pkg.RoomType{
+		{ID: 1, Name: "Single", Price: pkg.Money{Amount: 8000, Currency: "USD"}, MaxAdults: 1},
+		{ID: 2, Name: "Double Deluxe", Description: "two beds", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2, MaxChildren: 1},
+	}
+
+	repo := mock_service.NewMockRoomTypes(c)
+	repo.EXPECT().List(gomock.Any()).Return(types, nil)
+	repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&types[1], nil)
+	repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+
+	services := &service.Service{RoomTypes: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/room-types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []pkg.RoomType{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || !reflect.DeepEqual(got, types) {
+		t.Error("wrong types received: ", resp.StatusCode, got)
+	}
+
+	resp, err = http.Get(srv.URL + "/room-types/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	double := pkg.RoomType{}
+	json.NewDecoder(resp.Body).Decode(&double)
+	if resp.StatusCode != http.StatusOK || double != types[1] {
+		t.Error("wrong type received: ", resp.StatusCode, double)
+	}
+
+	resp, err = http.Get(srv.URL + "/room-types/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("wrong error code received: ", resp.StatusCode)
+	}
+}
+
+func TestHandler_updateRoomType(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	double := &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 11000, Currency: "USD"}, MaxAdults: 2}
+	saved := *double
+	saved.ID = 2
+
+	repo := mock_service.NewMockRoomTypes(c)
+	repo.EXPECT().Update(gomock.Any(), int64(2), double).Return(&saved, nil)
+
+	services := &service.Service{RoomTypes: repo}
+	srv := httptest.NewServer(adminRoutes(c, services))
+	defer srv.Close()
+
+	req, err := http.NewRequest(
+		"PUT",
+		srv.URL+"/room-types/2",
+		strings.NewReader(`{"name": "Double", "price": "110", "max_adults": 2}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := pkg.RoomType{}
+	json.NewDecoder(resp.Body).Decode(&got)
+	if resp.StatusCode != http.StatusOK || got != saved {
+		t.Error("wrong type received: ", resp.StatusCode, got)
+	}
+}
@@ -0,0 +1,301 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// numbered up and down files of the MySQL schema,
+// they are built into the binary
+//
+//go:embed mysql/*.sql
+var mysqlFiles embed.FS
+
+var (
+	ErrSchemaBehind    = errors.New("database schema is behind, run migrate up")
+	ErrNothingToRevert = errors.New("no applied migrations")
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies the migrations in version order
+// and records them in the schema_migrations table.
+// MySQL commits every DDL statement at once, so a migration
+// that fails halfway has to be cleaned up by hand.
+// The database has to accept several statements in one query,
+// for MySQL it is the multiStatements=true parameter.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMySQLMigrator uses the migrations built into the binary.
+func NewMySQLMigrator(db *sql.DB) (*Migrator, error) {
+	files, err := fs.Sub(mysqlFiles, "mysql")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMigrator(db, files)
+}
+
+// NewMigrator reads the migrations from the root of files,
+// named like 0001_create_room.up.sql and 0001_create_room.down.sql.
+func NewMigrator(db *sql.DB, files fs.FS) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// reads the migration files sorted by version,
+// every version needs both the up and the down file
+func load(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, e := range entries {
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file name %q is not valid", e.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration file name %q is not valid", e.Name())
+		}
+
+		body, err := fs.ReadFile(files, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations %q and %q have the same version", m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d needs both up and down files", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// creates the schema_migrations table if it does not exist
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(
+		ctx,
+		"CREATE TABLE IF NOT EXISTS `schema_migrations` ("+
+			"`version` BIGINT NOT NULL PRIMARY KEY,"+
+			"`name` VARCHAR(255) NOT NULL,"+
+			"`applied_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)",
+	)
+	return err
+}
+
+// returns the applied versions, none while the schema_migrations
+// table does not exist, the catalog is checked first so that
+// reading the versions does not change the database
+func (m *Migrator) applied(ctx context.Context) (map[int64]bool, error) {
+	var tables int
+	err := m.db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM `information_schema`.`tables`"+
+			" WHERE `table_schema` = DATABASE() AND `table_name` = 'schema_migrations'",
+	).Scan(&tables)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool)
+	if tables == 0 {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT `version` FROM `schema_migrations`")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		err = rows.Scan(&version)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// runs the whole file as one query, so the server
+// and not a split on ";" finds where the statements end
+func (m *Migrator) exec(ctx context.Context, file string) error {
+	_, err := m.db.ExecContext(ctx, file)
+	return err
+}
+
+// Up applies the migrations that are not applied yet
+// and returns them, the first error stops the rest.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	err := m.createTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make([]Migration, 0)
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		err = m.exec(ctx, migration.Up)
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err = m.db.ExecContext(
+			ctx,
+			"INSERT INTO `schema_migrations` (`version`, `name`) VALUES (?, ?)",
+			migration.Version,
+			migration.Name,
+		)
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last applied migration and returns it.
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var last int64
+	for version := range applied {
+		if version > last {
+			last = version
+		}
+	}
+	if last == 0 {
+		return nil, ErrNothingToRevert
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version != last {
+			continue
+		}
+
+		err = m.exec(ctx, migration.Down)
+		if err != nil {
+			return nil, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		_, err = m.db.ExecContext(
+			ctx,
+			"DELETE FROM `schema_migrations` WHERE `version` = ?",
+			migration.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return &migration, nil
+	}
+
+	return nil, fmt.Errorf("migration %d is not known to this build", last)
+}
+
+// Status returns every migration of the build
+// and whether it is applied, all of them are pending
+// in a database that has never been migrated.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status = append(status, Status{
+			Migration: migration,
+			Applied:   applied[migration.Version],
+		})
+	}
+
+	return status, nil
+}
+
+// Check returns ErrSchemaBehind if some migration is not applied.
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range status {
+		if !s.Applied {
+			return ErrSchemaBehind
+		}
+	}
+
+	return nil
+}
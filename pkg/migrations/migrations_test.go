@@ -0,0 +1,306 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// the ";" in the literal is not the end of a statement
+const addStatus = "ALTER TABLE b ADD s VARCHAR(8); UPDATE b SET s = 'new;old';"
+
+var testFiles = fstest.MapFS{
+	"0002_add_status.up.sql":      {Data: []byte(addStatus)},
+	"0002_add_status.down.sql":    {Data: []byte("ALTER TABLE b DROP s;")},
+	"0001_create_tables.up.sql":   {Data: []byte("CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);")},
+	"0001_create_tables.down.sql": {Data: []byte("DROP TABLE b;\nDROP TABLE a;")},
+}
+
+// expects the creation of the schema_migrations table
+func expectTable(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS `schema_migrations`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expects the catalog query, tables is 0 if
+// the schema_migrations table does not exist
+func expectCatalog(mock sqlmock.Sqlmock, tables int) {
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `information_schema`.`tables`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tables))
+}
+
+// expects the schema_migrations table and the select of the versions
+func expectApplied(mock sqlmock.Sqlmock, versions ...int64) {
+	expectCatalog(mock, 1)
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, v := range versions {
+		rows.AddRow(v)
+	}
+	mock.ExpectQuery("SELECT `version` FROM `schema_migrations`").WillReturnRows(rows)
+}
+
+func TestNewMySQLMigrator(t *testing.T) {
+	m, err := NewMySQLMigrator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.migrations) == 0 || m.migrations[0].Version != 1 {
+		t.Error("embedded migrations are not loaded: ", m.migrations)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name:  "OK sorted",
+			files: testFiles,
+			want:  []int64{1, 2},
+		},
+		{
+			name: "No down file",
+			files: fstest.MapFS{
+				"0001_create_tables.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Same version",
+			files: fstest.MapFS{
+				"0001_create_tables.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+				"0001_drop_tables.down.sql": {Data: []byte("DROP TABLE a;")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Name not valid",
+			files: fstest.MapFS{
+				"create_tables.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatal(err)
+			}
+			if len(migrations) != len(tt.want) {
+				t.Fatal("wrong migrations received: ", migrations)
+			}
+			for i := range tt.want {
+				if migrations[i].Version != tt.want[i] {
+					t.Error("migrations sorted incorrectly: ", migrations)
+				}
+			}
+		})
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := NewMigrator(db, testFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "OK pending",
+			mock: func() {
+				expectTable(mock)
+				expectApplied(mock, 1)
+				mock.ExpectExec(regexp.QuoteMeta(addStatus)).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `schema_migrations` (`version`, `name`) VALUES (?, ?)")).
+					WithArgs(2, "add_status").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: []int64{2},
+		},
+		{
+			name: "OK up to date",
+			mock: func() {
+				expectTable(mock)
+				expectApplied(mock, 1, 2)
+			},
+			want: []int64{},
+		},
+		{
+			name: "Failed migration",
+			mock: func() {
+				expectTable(mock)
+				expectApplied(mock)
+				mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);")).WillReturnError(errors.New("table exists"))
+			},
+			want:    []int64{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			done, err := m.Up(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatal(err)
+			}
+			if len(done) != len(tt.want) {
+				t.Fatal("wrong migrations applied: ", done)
+			}
+			for i := range tt.want {
+				if done[i].Version != tt.want[i] {
+					t.Error("wrong migrations applied: ", done)
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := NewMigrator(db, testFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK last",
+			mock: func() {
+				expectApplied(mock, 1, 2)
+				mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE b DROP s;")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `schema_migrations` WHERE `version` = ?")).
+					WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: 2,
+		},
+		{
+			name: "Nothing To Revert",
+			mock: func() {
+				expectApplied(mock)
+			},
+			wantErr: ErrNothingToRevert,
+		},
+		{
+			name: "Never migrated",
+			mock: func() {
+				expectCatalog(mock, 0)
+			},
+			wantErr: ErrNothingToRevert,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			migration, err := m.Down(context.Background())
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if err == nil && migration.Version != tt.want {
+				t.Error("wrong migration reverted: ", migration)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	expectApplied(mock, 3)
+	if _, err := m.Down(context.Background()); err == nil {
+		t.Error("unknown migration reverted")
+	}
+}
+
+func TestMigrator_Check(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := NewMigrator(db, testFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectApplied(mock, 1)
+	if err := m.Check(context.Background()); err != ErrSchemaBehind {
+		t.Error("incorrect error received: ", err)
+	}
+
+	expectApplied(mock, 1, 2)
+	if err := m.Check(context.Background()); err != nil {
+		t.Error(err)
+	}
+
+	// a database that has never been migrated is not changed
+	expectCatalog(mock, 0)
+	if err := m.Check(context.Background()); err != ErrSchemaBehind {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m, err := NewMigrator(db, testFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectApplied(mock, 1)
+	status, err := m.Status(context.Background())
+	if err != nil || len(status) != 2 || !status[0].Applied || status[1].Applied {
+		t.Error("wrong status received: ", status, err)
+	}
+
+	// every migration is pending without the schema_migrations table
+	expectCatalog(mock, 0)
+	status, err = m.Status(context.Background())
+	if err != nil || len(status) != 2 || status[0].Applied || status[1].Applied {
+		t.Error("wrong status received: ", status, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE `bookings`
+  ADD `status` 				VARCHAR(16) NOT NULL DEFAULT 'pending' AFTER `date_end`;
@@ -0,0 +1,6 @@
+ALTER TABLE `bookings`
+  DROP FOREIGN KEY `bookings_room`,
+  ADD FOREIGN KEY (`room_id`) REFERENCES `room` (`id`) ON DELETE CASCADE;
+
+ALTER TABLE `room`
+  DROP `deleted_at`;
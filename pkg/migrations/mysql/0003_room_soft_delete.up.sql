@@ -0,0 +1,6 @@
+ALTER TABLE `room`
+  ADD `deleted_at` 			DATETIME NULL AFTER `date`;
+
+ALTER TABLE `bookings`
+  DROP FOREIGN KEY `bookings_ibfk_1`,
+  ADD CONSTRAINT `bookings_room` FOREIGN KEY (`room_id`) REFERENCES `room` (`id`) ON DELETE RESTRICT;
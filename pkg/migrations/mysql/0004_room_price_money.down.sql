@@ -0,0 +1,11 @@
+ALTER TABLE `room`
+  ADD `price` 				FLOAT NOT NULL DEFAULT 0 AFTER `description`;
+
+UPDATE `room` SET `price` = `price_amount` / 100;
+
+ALTER TABLE `room`
+  DROP INDEX `room_price_date`,
+  DROP `price_amount`,
+  DROP `price_currency`,
+  ALTER `price` DROP DEFAULT,
+  ADD INDEX `SERCH` (`price` ASC, `date` ASC) INVISIBLE;
@@ -0,0 +1,12 @@
+ALTER TABLE `room`
+  ADD `price_amount` 		BIGINT NOT NULL DEFAULT 0 AFTER `price`,
+  ADD `price_currency` 		CHAR(3) NOT NULL DEFAULT 'USD' AFTER `price_amount`;
+
+UPDATE `room` SET `price_amount` = ROUND(`price` * 100);
+
+ALTER TABLE `room`
+  DROP INDEX `SERCH`,
+  DROP `price`,
+  ALTER `price_amount` DROP DEFAULT,
+  ALTER `price_currency` DROP DEFAULT,
+  ADD INDEX `room_price_date` (`price_currency`, `price_amount`, `date`);
@@ -0,0 +1,3 @@
+ALTER TABLE `bookings`
+  DROP `total_amount`,
+  DROP `total_currency`;
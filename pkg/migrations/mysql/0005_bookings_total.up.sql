@@ -0,0 +1,3 @@
+ALTER TABLE `bookings`
+  ADD `total_amount` 		BIGINT NULL AFTER `status`,
+  ADD `total_currency` 		CHAR(3) NULL AFTER `total_amount`;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `rate_plans` (
+  `id` 					INT NOT NULL AUTO_INCREMENT,
+  `room_id` 			INT NOT NULL,
+  `name` 				VARCHAR(255) NOT NULL,
+  `date_start` 			DATE NULL,
+  `date_end` 			DATE NULL,
+  `weekdays` 			TINYINT UNSIGNED NOT NULL DEFAULT 0,
+  `price_amount` 		BIGINT NOT NULL,
+  `price_currency` 		CHAR(3) NOT NULL,
+  `priority` 			INT NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`),
+  INDEX `rate_plans_room` (`room_id` ASC),
+  FOREIGN KEY (`room_id`) REFERENCES `room` (`id`) ON DELETE RESTRICT
+);
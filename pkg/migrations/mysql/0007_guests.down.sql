@@ -0,0 +1,6 @@
+ALTER TABLE `bookings`
+  DROP FOREIGN KEY `bookings_guest`,
+  DROP INDEX `bookings_guest_date`,
+  DROP `guest_id`;
+
+DROP TABLE IF EXISTS `guests`;
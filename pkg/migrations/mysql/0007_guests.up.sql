@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS `guests` (
+  `id` 					INT NOT NULL AUTO_INCREMENT,
+  `name` 				VARCHAR(255) NOT NULL,
+  `email` 				VARCHAR(255) NOT NULL,
+  `phone` 				VARCHAR(32) NOT NULL DEFAULT '',
+  `document_number` 	VARCHAR(64) NOT NULL DEFAULT '',
+
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `guests_email` (`email`)
+);
+
+ALTER TABLE `bookings`
+  ADD `guest_id` 		INT NULL AFTER `room_id`,
+  ADD INDEX `bookings_guest_date` (`guest_id`, `date_start`),
+  ADD CONSTRAINT `bookings_guest` FOREIGN KEY (`guest_id`) REFERENCES `guests` (`id`) ON DELETE RESTRICT;
@@ -0,0 +1,7 @@
+ALTER TABLE `bookings`
+  DROP `children`,
+  DROP `adults`;
+
+ALTER TABLE `room`
+  DROP `max_children`,
+  DROP `max_adults`;
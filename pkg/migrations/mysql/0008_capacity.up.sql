@@ -0,0 +1,7 @@
+ALTER TABLE `room`
+  ADD `max_adults` 		INT NOT NULL DEFAULT 2,
+  ADD `max_children` 	INT NOT NULL DEFAULT 0;
+
+ALTER TABLE `bookings`
+  ADD `adults` 			INT NOT NULL DEFAULT 1 AFTER `guest_id`,
+  ADD `children` 		INT NOT NULL DEFAULT 0 AFTER `adults`;
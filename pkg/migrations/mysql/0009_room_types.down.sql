@@ -0,0 +1,14 @@
+DELETE FROM `bookings` WHERE `room_id` IS NULL;
+
+ALTER TABLE `bookings`
+  DROP FOREIGN KEY `bookings_type`,
+  DROP INDEX `bookings_type_date`,
+  DROP `type_id`,
+  MODIFY `room_id` 		INT NOT NULL;
+
+ALTER TABLE `room`
+  DROP FOREIGN KEY `room_type`,
+  DROP INDEX `room_type`,
+  DROP `type_id`;
+
+DROP TABLE IF EXISTS `room_types`;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS `room_types` (
+  `id` 					INT NOT NULL AUTO_INCREMENT,
+  `name` 				VARCHAR(255) NOT NULL,
+  `description` 		VARCHAR(1024) NOT NULL,
+  `price_amount` 		BIGINT NOT NULL,
+  `price_currency` 		CHAR(3) NOT NULL,
+  `max_adults` 			INT NOT NULL DEFAULT 2,
+  `max_children` 		INT NOT NULL DEFAULT 0,
+
+  PRIMARY KEY (`id`)
+);
+
+ALTER TABLE `room`
+  ADD `type_id` 		INT NULL,
+  ADD INDEX `room_type` (`type_id`),
+  ADD CONSTRAINT `room_type` FOREIGN KEY (`type_id`) REFERENCES `room_types` (`id`) ON DELETE RESTRICT;
+
+ALTER TABLE `bookings`
+  MODIFY `room_id` 		INT NULL,
+  ADD `type_id` 		INT NULL AFTER `room_id`,
+  ADD INDEX `bookings_type_date` (`type_id`, `date_start`),
+  ADD CONSTRAINT `bookings_type` FOREIGN KEY (`type_id`) REFERENCES `room_types` (`id`) ON DELETE RESTRICT;
@@ -0,0 +1,11 @@
+ALTER TABLE `room`
+  DROP FOREIGN KEY `room_property`,
+  DROP INDEX `room_property`,
+  DROP `property_id`;
+
+ALTER TABLE `room_types`
+  DROP FOREIGN KEY `room_types_property`,
+  DROP INDEX `room_types_property`,
+  DROP `property_id`;
+
+DROP TABLE IF EXISTS `properties`;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS `properties` (
+  `id` 					INT NOT NULL AUTO_INCREMENT,
+  `name` 				VARCHAR(255) NOT NULL,
+  `address` 			VARCHAR(1024) NOT NULL DEFAULT '',
+  `timezone` 			VARCHAR(64) NOT NULL,
+  `currency` 			CHAR(3) NOT NULL,
+
+  PRIMARY KEY (`id`)
+);
+
+-- the rooms and types saved before properties belong to the first one
+INSERT INTO `properties` (`id`, `name`, `timezone`, `currency`) VALUES (1, 'Default', 'UTC', 'USD');
+
+ALTER TABLE `room_types`
+  ADD `property_id` 	INT NOT NULL DEFAULT 1 AFTER `id`,
+  ADD INDEX `room_types_property` (`property_id`),
+  ADD CONSTRAINT `room_types_property` FOREIGN KEY (`property_id`) REFERENCES `properties` (`id`) ON DELETE RESTRICT;
+
+ALTER TABLE `room`
+  ADD `property_id` 	INT NOT NULL DEFAULT 1 AFTER `id`,
+  ADD INDEX `room_property` (`property_id`, `date`),
+  ADD CONSTRAINT `room_property` FOREIGN KEY (`property_id`) REFERENCES `properties` (`id`) ON DELETE RESTRICT;
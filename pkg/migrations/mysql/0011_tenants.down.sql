@@ -0,0 +1,22 @@
+ALTER TABLE `guests`
+  DROP INDEX `guests_email`,
+  ADD UNIQUE INDEX `guests_email` (`email`),
+  DROP `tenant_id`;
+
+ALTER TABLE `rate_plans`
+  DROP `tenant_id`;
+
+ALTER TABLE `bookings`
+  DROP `tenant_id`;
+
+ALTER TABLE `room`
+  DROP INDEX `room_tenant`,
+  DROP `tenant_id`;
+
+ALTER TABLE `room_types`
+  DROP INDEX `room_types_tenant`,
+  DROP `tenant_id`;
+
+ALTER TABLE `properties`
+  DROP INDEX `properties_tenant`,
+  DROP `tenant_id`;
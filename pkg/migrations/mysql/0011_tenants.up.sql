@@ -0,0 +1,24 @@
+-- the data saved before tenants belongs to the first one
+ALTER TABLE `properties`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`,
+  ADD INDEX `properties_tenant` (`tenant_id`);
+
+ALTER TABLE `room_types`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`,
+  ADD INDEX `room_types_tenant` (`tenant_id`);
+
+ALTER TABLE `room`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`,
+  ADD INDEX `room_tenant` (`tenant_id`, `date`);
+
+ALTER TABLE `bookings`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`;
+
+ALTER TABLE `rate_plans`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`;
+
+-- the same email can be used by the guests of different tenants
+ALTER TABLE `guests`
+  ADD `tenant_id` 		INT NOT NULL DEFAULT 1 AFTER `id`,
+  DROP INDEX `guests_email`,
+  ADD UNIQUE INDEX `guests_email` (`tenant_id`, `email`);
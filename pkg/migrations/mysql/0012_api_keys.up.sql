@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS `api_keys` (
+  `id` 					INT NOT NULL AUTO_INCREMENT,
+  `tenant_id` 			INT NOT NULL,
+  `role` 				VARCHAR(16) NOT NULL,
+  `key_hash` 			CHAR(64) NOT NULL,
+  `created_at` 			DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `revoked_at` 			DATETIME NULL,
+
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `api_keys_hash` (`key_hash`)
+);
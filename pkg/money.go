@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for prices passed
+// without a currency by the old API clients.
+const DefaultCurrency = "USD"
+
+// digits after the decimal point of the ISO 4217 currencies
+var currencyExponent = map[string]int{
+	"AED": 2, "ARS": 2, "AUD": 2, "BGN": 2, "BHD": 3, "BRL": 2,
+	"BYN": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2, "CZK": 2,
+	"DKK": 2, "EGP": 2, "EUR": 2, "GBP": 2, "GEL": 2, "HKD": 2,
+	"HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3,
+	"JPY": 0, "KRW": 0, "KWD": 3, "KZT": 2, "MXN": 2, "MYR": 2,
+	"NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2, "PLN": 2, "RON": 2,
+	"RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3,
+	"TRY": 2, "UAH": 2, "USD": 2, "UZS": 2, "VND": 0, "ZAR": 2,
+}
+
+// Money is an amount in the minor units of the currency,
+// e.g. 1999 USD is 19.99 dollars.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// ParseMoney reads a decimal amount like "19.99",
+// the amount can not have more fraction digits than the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	exp, ok := currencyExponent[currency]
+	if !ok {
+		return Money{}, ErrCurrencyNotValid
+	}
+
+	negative := strings.HasPrefix(amount, "-")
+	whole, frac := strings.TrimPrefix(amount, "-"), ""
+	if i := strings.IndexByte(whole, '.'); i >= 0 {
+		whole, frac = whole[:i], whole[i+1:]
+		if frac == "" {
+			return Money{}, ErrPriceNotValid
+		}
+	}
+	if whole == "" || len(frac) > exp || !digits(whole) || !digits(frac) {
+		return Money{}, ErrPriceNotValid
+	}
+
+	units, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, ErrPriceNotValid
+	}
+	if negative {
+		units = -units
+	}
+
+	return Money{Amount: units, Currency: currency}, nil
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate returns ErrCurrencyNotValid for an unknown currency
+// and ErrPriceNotValid for a negative amount.
+func (m Money) Validate() error {
+	if _, ok := currencyExponent[m.Currency]; !ok {
+		return ErrCurrencyNotValid
+	}
+	if m.Amount < 0 {
+		return ErrPriceNotValid
+	}
+	return nil
+}
+
+// AmountString returns the amount with the decimal point, e.g. "19.99".
+func (m Money) AmountString() string {
+	exp := currencyExponent[m.Currency]
+	s, sign := strconv.FormatInt(m.Amount, 10), ""
+	if strings.HasPrefix(s, "-") {
+		s, sign = s[1:], "-"
+	}
+	if exp == 0 {
+		return sign + s
+	}
+	if len(s) <= exp {
+		s = strings.Repeat("0", exp-len(s)+1) + s
+	}
+	return sign + s[:len(s)-exp] + "." + s[len(s)-exp:]
+}
+
+func (m Money) String() string {
+	return m.AmountString() + " " + m.Currency
+}
+
+// the amount is read as a string or a number
+type moneyJSON struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON writes the amount as a decimal string:
+//
+//	{"amount": "19.99", "currency": "USD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.AmountString(), m.Currency})
+}
+
+// UnmarshalJSON reads the amount as a string or a number,
+// a price without the currency is read in DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v := moneyJSON{Currency: DefaultCurrency}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&v)
+		if err != nil {
+			return ErrPriceNotValid.Wrap(err)
+		}
+	} else {
+		err := json.Unmarshal(data, &v.Amount)
+		if err != nil {
+			return ErrPriceNotValid.Wrap(err)
+		}
+	}
+
+	money, err := ParseMoney(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
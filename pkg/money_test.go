@@ -0,0 +1,206 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{
+			name:     "OK",
+			amount:   "19.99",
+			currency: "USD",
+			want:     Money{Amount: 1999, Currency: "USD"},
+		},
+		{
+			name:     "OK whole",
+			amount:   "7",
+			currency: "EUR",
+			want:     Money{Amount: 700, Currency: "EUR"},
+		},
+		{
+			name:     "OK one fraction digit",
+			amount:   "6.5",
+			currency: "USD",
+			want:     Money{Amount: 650, Currency: "USD"},
+		},
+		{
+			name:     "OK no minor units",
+			amount:   "5400",
+			currency: "JPY",
+			want:     Money{Amount: 5400, Currency: "JPY"},
+		},
+		{
+			name:     "OK three fraction digits",
+			amount:   "1.005",
+			currency: "KWD",
+			want:     Money{Amount: 1005, Currency: "KWD"},
+		},
+		{
+			name:     "OK negative",
+			amount:   "-0.01",
+			currency: "USD",
+			want:     Money{Amount: -1, Currency: "USD"},
+		},
+		{
+			name:     "Too many fraction digits",
+			amount:   "0.001",
+			currency: "USD",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Fraction in JPY",
+			amount:   "10.5",
+			currency: "JPY",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Exponent",
+			amount:   "1e3",
+			currency: "USD",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Empty fraction",
+			amount:   "5.",
+			currency: "USD",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Empty",
+			amount:   "",
+			currency: "USD",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Overflow",
+			amount:   "92233720368547758.08",
+			currency: "USD",
+			wantErr:  ErrPriceNotValid,
+		},
+		{
+			name:     "Currency not valid",
+			amount:   "5",
+			currency: "usd",
+			wantErr:  ErrCurrencyNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := ParseMoney(tt.amount, tt.currency)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if money != tt.want {
+				t.Error("wrong money received: ", money)
+			}
+		})
+	}
+}
+
+func TestMoney_AmountString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 1999, Currency: "USD"}, "19.99"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05"},
+		{Money{Amount: 0, Currency: "EUR"}, "0.00"},
+		{Money{Amount: -150, Currency: "USD"}, "-1.50"},
+		{Money{Amount: 5400, Currency: "JPY"}, "5400"},
+		{Money{Amount: 1005, Currency: "KWD"}, "1.005"},
+	}
+
+	for _, tt := range tests {
+		if s := tt.money.AmountString(); s != tt.want {
+			t.Error("wrong amount received: ", s, " want ", tt.want)
+		}
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Money
+		wantErr error
+	}{
+		{
+			name:  "OK object",
+			input: `{"amount": "19.99", "currency": "EUR"}`,
+			want:  Money{Amount: 1999, Currency: "EUR"},
+		},
+		{
+			name:  "OK object number",
+			input: `{"amount": 19.99, "currency": "EUR"}`,
+			want:  Money{Amount: 1999, Currency: "EUR"},
+		},
+		{
+			name:  "OK object without currency",
+			input: `{"amount": "19.99"}`,
+			want:  Money{Amount: 1999, Currency: DefaultCurrency},
+		},
+		{
+			name:  "OK number",
+			input: `5.41`,
+			want:  Money{Amount: 541, Currency: DefaultCurrency},
+		},
+		{
+			name:  "OK string",
+			input: `"5.41"`,
+			want:  Money{Amount: 541, Currency: DefaultCurrency},
+		},
+		{
+			name:    "Unknown field",
+			input:   `{"amount": "19.99", "currency": "EUR", "rate": 1}`,
+			wantErr: ErrPriceNotValid,
+		},
+		{
+			name:    "Not a number",
+			input:   `"Ls"`,
+			wantErr: ErrPriceNotValid,
+		},
+		{
+			name:    "Currency not valid",
+			input:   `{"amount": "19.99", "currency": "XXX"}`,
+			wantErr: ErrCurrencyNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var money Money
+			err := json.Unmarshal([]byte(tt.input), &money)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if money != tt.want {
+				t.Error("wrong money received: ", money)
+			}
+
+			data, err := json.Marshal(money)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back Money
+			if err := json.Unmarshal(data, &back); err != nil || back != money {
+				t.Error("wrong json written: ", string(data))
+			}
+		})
+	}
+}
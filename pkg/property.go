@@ -0,0 +1,15 @@
+package pkg
+
+// Property is a hotel, its rooms and room types are priced
+// in its currency, the timezone is an IANA name like "Europe/Paris".
+type Property struct {
+	ID       int64  `json:"property_id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Timezone string `json:"timezone"`
+	Currency string `json:"currency"`
+}
+
+// DefaultPropertyID is the property of the rooms and types
+// added without one, it holds the rooms saved before properties.
+const DefaultPropertyID = 1
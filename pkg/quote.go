@@ -0,0 +1,19 @@
+package pkg
+
+// QuoteLine is the price of one night of the stay,
+// Date is the night's arrival date.
+type QuoteLine struct {
+	Date  string `json:"date"`
+	Price Money  `json:"price"`
+}
+
+// Quote is the price of a stay in the room,
+// one line for each night from Start to End.
+type Quote struct {
+	RoomID int64       `json:"room_id"`
+	Start  string      `json:"date_start"`
+	End    string      `json:"date_end"`
+	Nights int         `json:"nights"`
+	Lines  []QuoteLine `json:"lines"`
+	Total  Money       `json:"total"`
+}
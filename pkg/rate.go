@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RatePlan overrides the nightly price of the room on the nights
+// from Start to End, End excluded, that fall on one of Weekdays.
+// An empty Start, End or Weekdays does not limit the plan.
+// Of the plans for the same night the one with the highest
+// Priority is used, plans with the same priority are ordered by id,
+// the newest one wins.
+type RatePlan struct {
+	ID       int64    `json:"rate_id"`
+	RoomID   int64    `json:"room_id"`
+	Name     string   `json:"name"`
+	Start    string   `json:"date_start,omitempty"`
+	End      string   `json:"date_end,omitempty"`
+	Weekdays Weekdays `json:"weekdays,omitempty"`
+	Price    Money    `json:"price"`
+	Priority int      `json:"priority"`
+}
+
+// reports whether the plan is used for the night,
+// date is the night's arrival date in the 2006-01-02 format
+func (p *RatePlan) Applies(date string, day time.Weekday) bool {
+	if p.Start != "" && date < p.Start {
+		return false
+	}
+	if p.End != "" && date >= p.End {
+		return false
+	}
+	return p.Weekdays == 0 || p.Weekdays.Has(day)
+}
+
+// Weekdays is a set of days of the week, the bit 1<<time.Sunday
+// is Sunday, the bit 1<<time.Saturday is Saturday.
+type Weekdays uint8
+
+// AllWeekdays is the set of all seven days.
+const AllWeekdays Weekdays = 1<<7 - 1
+
+var weekdayNames = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+func (d Weekdays) Has(day time.Weekday) bool {
+	return d&(1<<uint(day)) != 0
+}
+
+// MarshalJSON writes the set as the short day names:
+//
+//	["fri", "sat"]
+func (d Weekdays) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, 7)
+	for day, name := range weekdayNames {
+		if d.Has(time.Weekday(day)) {
+			names = append(names, name)
+		}
+	}
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON reads the short day names,
+// returns ErrWeekdayNotValid for an unknown name.
+func (d *Weekdays) UnmarshalJSON(data []byte) error {
+	var names []string
+	err := json.Unmarshal(data, &names)
+	if err != nil {
+		return ErrWeekdayNotValid.Wrap(err)
+	}
+
+	var set Weekdays
+	for _, name := range names {
+		found := false
+		for day, n := range weekdayNames {
+			if n == name {
+				set |= 1 << uint(day)
+				found = true
+			}
+		}
+		if !found {
+			return ErrWeekdayNotValid
+		}
+	}
+
+	*d = set
+	return nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWeekdays_JSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Weekdays
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: `["sat", "fri", "sun"]`,
+			want:  1<<time.Friday | 1<<time.Saturday | 1<<time.Sunday,
+		},
+		{
+			name:  "OK empty",
+			input: `[]`,
+			want:  0,
+		},
+		{
+			name:    "Unknown day",
+			input:   `["Friday"]`,
+			wantErr: ErrWeekdayNotValid,
+		},
+		{
+			name:    "Not a list",
+			input:   `"fri"`,
+			wantErr: ErrWeekdayNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Weekdays
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatal("incorrect error received: ", err)
+			}
+			if got != tt.want {
+				t.Error("wrong days received: ", got)
+			}
+		})
+	}
+
+	b, err := json.Marshal(Weekdays(1<<time.Sunday | 1<<time.Friday))
+	if err != nil || string(b) != `["sun","fri"]` {
+		t.Error("wrong JSON received: ", string(b), err)
+	}
+}
+
+func TestRatePlan_Applies(t *testing.T) {
+	plan := RatePlan{Start: "2018-06-01", End: "2018-09-01", Weekdays: 1 << time.Saturday}
+
+	tests := []struct {
+		date string
+		day  time.Weekday
+		want bool
+	}{
+		{"2018-06-02", time.Saturday, true},
+		{"2018-06-01", time.Friday, false},
+		{"2018-05-26", time.Saturday, false},
+		{"2018-09-01", time.Saturday, false},
+		{"2018-08-25", time.Saturday, true},
+	}
+
+	for _, tt := range tests {
+		if got := plan.Applies(tt.date, tt.day); got != tt.want {
+			t.Error("wrong result for ", tt.date, ": ", got)
+		}
+	}
+
+	if !(&RatePlan{}).Applies("2018-06-01", time.Friday) {
+		t.Error("plan without limits does not apply")
+	}
+}
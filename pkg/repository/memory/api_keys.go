@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type APIKeysMemory struct {
+	s *Store
+}
+
+func NewAPIKeysMemory(s *Store) *APIKeysMemory {
+	return &APIKeysMemory{s: s}
+}
+
+// The key belongs to the tenant of its tenant_id field,
+// the keys are not scoped by the tenant of the context,
+// they name it. On successful creation,
+// in the id field records the key id.
+func (r *APIKeysMemory) Add(ctx context.Context, key *pkg.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.keyID++
+	key.ID = r.s.keyID
+	saved := *key
+	r.s.keys[key.ID] = &saved
+	return nil
+}
+
+// the revoked key is forgotten,
+// returns pkg.ErrIDNotFound if there is no such key
+func (r *APIKeysMemory) Revoke(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.keys[id]; !ok {
+		return pkg.ErrIDNotFound
+	}
+
+	delete(r.s.keys, id)
+	return nil
+}
+
+// returns pkg.ErrIDNotFound if there is no key with the hash
+func (r *APIKeysMemory) GetByHash(ctx context.Context, hash string) (*pkg.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, key := range r.s.keys {
+		if key.Hash == hash {
+			found := *key
+			return &found, nil
+		}
+	}
+
+	return nil, pkg.ErrIDNotFound
+}
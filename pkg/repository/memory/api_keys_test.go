@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestAPIKeysMemory(t *testing.T) {
+	ctx := context.Background()
+	r := NewAPIKeysMemory(newTestStore())
+
+	key := pkg.APIKey{TenantID: 2, Role: pkg.RoleStaff, Hash: "ab12"}
+	if err := r.Add(ctx, &key); err != nil {
+		t.Fatal(err)
+	}
+	if key.ID != 1 {
+		t.Error("wrong id received: ", key.ID)
+	}
+
+	got, err := r.GetByHash(ctx, "ab12")
+	if err != nil || *got != key {
+		t.Error("wrong key received: ", got, err)
+	}
+	if _, err := r.GetByHash(ctx, "cd34"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := r.Revoke(ctx, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByHash(ctx, "ab12"); err != pkg.ErrIDNotFound {
+		t.Error("revoked key received: ", err)
+	}
+	if err := r.Revoke(ctx, key.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
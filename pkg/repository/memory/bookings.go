@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type BookingsMemory struct {
+	s *Store
+}
+
+func NewBookingsMemory(s *Store) *BookingsMemory {
+	return &BookingsMemory{s: s}
+}
+
+// The booking gets the type of the room, with a zero room
+// the type of the booking is booked without a room.
+// Returns pkg.ErrNoForeignKey if the room does not exist or is archived,
+// pkg.ErrNoGuest if the guest does not exist,
+// pkg.ErrBookingConflict if the dates overlap
+// with an existing booking of the room or the type
+// has no free room on one of the nights.
+// The room, the type and the guest must belong to the tenant of the context.
+func (r *BookingsMemory) Add(ctx context.Context, room int64, bookings *pkg.Booking) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if room == 0 && bookings.TypeID == 0 {
+		return pkg.ErrNoForeignKey
+	}
+	if _, ok := r.s.guests[bookings.GuestID]; bookings.GuestID != 0 && (!ok || !r.s.owns(ctx, guestsTable, bookings.GuestID)) {
+		return pkg.ErrNoGuest
+	}
+
+	if room != 0 {
+		stored, ok := r.s.activeRoom(ctx, room)
+		if !ok {
+			return pkg.ErrNoForeignKey
+		}
+
+		err := r.s.checkConflict(room, bookings.Start, bookings.End, 0)
+		if err != nil {
+			return err
+		}
+		bookings.TypeID = stored.TypeID
+	}
+
+	if bookings.TypeID != 0 {
+		err := r.s.checkType(ctx, bookings.TypeID, room, bookings.Start, bookings.End, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.s.bookID++
+	bookings.ID = r.s.bookID
+	bookings.RoomID = room
+	b := *bookings
+	if b.Total != nil {
+		total := *b.Total
+		b.Total = &total
+	}
+	r.s.bookings[b.ID] = &b
+	r.s.own(ctx, bookingsTable, b.ID)
+	return nil
+}
+
+// Passes a copy of the booking to change,
+// the changed booking is saved only if change returns nil
+// with the type of its room.
+// Returns pkg.ErrBookingConflict if the changed dates of an active
+// booking overlap with another active booking of the room
+// or the type has no free room on one of the nights.
+func (r *BookingsMemory) Update(ctx context.Context, id int64, change func(booking *pkg.Booking) error) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.bookings[id]
+	if !ok || !r.s.owns(ctx, bookingsTable, id) {
+		return pkg.ErrIDNotFound
+	}
+
+	b := *stored
+	err := change(&b)
+	if err != nil {
+		return err
+	}
+
+	if b.Status.Active() {
+		if b.RoomID != 0 {
+			room, ok := r.s.activeRoom(ctx, b.RoomID)
+			if !ok {
+				return pkg.ErrNoForeignKey
+			}
+
+			err = r.s.checkConflict(b.RoomID, b.Start, b.End, id)
+			if err != nil {
+				return err
+			}
+			b.TypeID = room.TypeID
+		}
+
+		if b.TypeID != 0 {
+			err = r.s.checkType(ctx, b.TypeID, b.RoomID, b.Start, b.End, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	b.ID = id
+	*stored = b
+	return nil
+}
+
+// returns a copy of the booking
+func (r *BookingsMemory) GetByID(ctx context.Context, id int64) (*pkg.Booking, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.bookings[id]
+	if !ok || !r.s.owns(ctx, bookingsTable, id) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	b := *stored
+	return &b, nil
+}
+
+// returns bookings by room id
+// sorted by start date,
+// if statuses are passed, only bookings in them
+func (r *BookingsMemory) Get(ctx context.Context, id int64, status []pkg.BookingStatus) ([]pkg.Booking, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	bookings := make([]pkg.Booking, 0, 1)
+	for _, b := range r.s.bookings {
+		if b.RoomID == id && hasStatus(status, b.Status) && r.s.owns(ctx, bookingsTable, b.ID) {
+			bookings = append(bookings, *b)
+		}
+	}
+
+	if len(bookings) == 0 {
+		if _, ok := r.s.room(ctx, id); !ok {
+			return nil, pkg.ErrIDNotFound
+		}
+	}
+
+	sortByStart(bookings)
+	return bookings, nil
+}
+
+// sorts the bookings by start date,
+// bookings with the same start are ordered by id
+func sortByStart(bookings []pkg.Booking) {
+	sort.Slice(bookings, func(i, j int) bool {
+		if bookings[i].Start != bookings[j].Start {
+			return bookings[i].Start < bookings[j].Start
+		}
+		return bookings[i].ID < bookings[j].ID
+	})
+}
+
+// an empty list passes any status
+func hasStatus(list []pkg.BookingStatus, status pkg.BookingStatus) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the bookings of the guest sorted by start date
+func (r *BookingsMemory) GetByGuest(ctx context.Context, guest int64) ([]pkg.Booking, error) {
+	r.s.mu.RLock()
+	bookings := make([]pkg.Booking, 0)
+	for _, b := range r.s.bookings {
+		if b.GuestID == guest && r.s.owns(ctx, bookingsTable, b.ID) {
+			bookings = append(bookings, *b)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sortByStart(bookings)
+	return bookings, nil
+}
@@ -0,0 +1,336 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestBookingsMemory_Add(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	NewRoomMemory(s).Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r := NewBookingsMemory(s)
+
+	tests := []struct {
+		name    string
+		room    int64
+		start   string
+		end     string
+		want    int64
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			room:  1,
+			start: "2018-02-03",
+			end:   "2018-02-10",
+			want:  1,
+		},
+		{
+			name:  "OK back to back",
+			room:  1,
+			start: "2018-02-10",
+			end:   "2018-02-12",
+			want:  2,
+		},
+		{
+			name:    "Booking Conflict",
+			room:    1,
+			start:   "2018-02-05",
+			end:     "2018-02-06",
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name:    "No Foreign Key",
+			room:    2,
+			start:   "2018-02-03",
+			end:     "2018-02-10",
+			wantErr: pkg.ErrNoForeignKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &pkg.Booking{Start: tt.start, End: tt.end, Status: pkg.StatusPending}
+			err := r.Add(ctx, tt.room, booking)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && booking.ID != tt.want {
+				t.Error("wrong id received: ", booking.ID)
+			}
+		})
+	}
+}
+
+func TestBookingsMemory_AddConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	NewRoomMemory(s).Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r := NewBookingsMemory(s)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			booking := &pkg.Booking{Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusPending}
+			errs <- r.Add(ctx, 1, booking)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	saved := 0
+	for err := range errs {
+		if err == nil {
+			saved++
+		} else if err != pkg.ErrBookingConflict {
+			t.Error(err)
+		}
+	}
+	if saved != 1 {
+		t.Error("wrong number of bookings saved: ", saved)
+	}
+}
+
+func TestBookingsMemory_Update(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	NewRoomMemory(s).Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r := NewBookingsMemory(s)
+	r.Add(ctx, 1, &pkg.Booking{Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusPending})
+	r.Add(ctx, 1, &pkg.Booking{Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending})
+
+	tests := []struct {
+		name    string
+		id      int64
+		change  func(b *pkg.Booking) error
+		wantErr error
+		want    pkg.Booking
+	}{
+		{
+			name: "Booking Conflict",
+			id:   1,
+			change: func(b *pkg.Booking) error {
+				b.End = "2018-02-11"
+				return nil
+			},
+			wantErr: pkg.ErrBookingConflict,
+			want:    pkg.Booking{ID: 1, RoomID: 1, Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusPending},
+		},
+		{
+			name: "Change error",
+			id:   1,
+			change: func(b *pkg.Booking) error {
+				b.Status = pkg.StatusConfirmed
+				return pkg.ErrStatusChange
+			},
+			wantErr: pkg.ErrStatusChange,
+			want:    pkg.Booking{ID: 1, RoomID: 1, Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusPending},
+		},
+		{
+			name: "OK cancel",
+			id:   2,
+			change: func(b *pkg.Booking) error {
+				b.Status = pkg.StatusCancelled
+				return nil
+			},
+			want: pkg.Booking{ID: 2, RoomID: 1, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusCancelled},
+		},
+		{
+			name: "OK after cancel",
+			id:   1,
+			change: func(b *pkg.Booking) error {
+				b.End = "2018-02-11"
+				return nil
+			},
+			want: pkg.Booking{ID: 1, RoomID: 1, Start: "2018-02-03", End: "2018-02-11", Status: pkg.StatusPending},
+		},
+		{
+			name:    "ID Not Found",
+			id:      9,
+			change:  func(b *pkg.Booking) error { return nil },
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Update(ctx, tt.id, tt.change)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if b, ok := s.bookings[tt.id]; ok && *b != tt.want {
+				t.Error("wrong booking saved: ", *b)
+			}
+		})
+	}
+}
+
+func TestBookingsMemory_Get(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	rooms := NewRoomMemory(s)
+	rooms.Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	rooms.Add(ctx, &pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}})
+	r := NewBookingsMemory(s)
+	total := &pkg.Money{Amount: 1400, Currency: "USD"}
+	r.Add(ctx, 1, &pkg.Booking{Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending, Total: total})
+	r.Add(ctx, 1, &pkg.Booking{Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusConfirmed})
+	total.Amount = 0
+
+	tests := []struct {
+		name    string
+		room    int64
+		status  []pkg.BookingStatus
+		want    []int64
+		wantErr error
+	}{
+		{
+			name: "OK sorted by start",
+			room: 1,
+			want: []int64{2, 1},
+		},
+		{
+			name:   "OK status",
+			room:   1,
+			status: []pkg.BookingStatus{pkg.StatusPending},
+			want:   []int64{1},
+		},
+		{
+			name: "OK no bookings",
+			room: 2,
+			want: []int64{},
+		},
+		{
+			name:    "ID Not Found",
+			room:    3,
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookings, err := r.Get(ctx, tt.room, tt.status)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if len(bookings) != len(tt.want) {
+				t.Fatal("wrong number of bookings received: ", bookings)
+			}
+			for i := range tt.want {
+				if bookings[i].ID != tt.want[i] {
+					t.Error("array sorted incorrectly: ", bookings)
+				}
+			}
+		})
+	}
+
+	bookings, err := r.Get(ctx, 1, nil)
+	if err != nil || bookings[0].Total != nil || bookings[1].Total == nil ||
+		*bookings[1].Total != (pkg.Money{Amount: 1400, Currency: "USD"}) {
+		t.Error("wrong totals received: ", bookings, err)
+	}
+}
+
+func TestBookingsMemory_Type(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	double := &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 500, Currency: "USD"}, MaxAdults: 2}
+	NewRoomTypesMemory(s).Add(ctx, double)
+	for i := 0; i < 2; i++ {
+		NewRoomMemory(s).Add(ctx, &pkg.Room{Description: "Good", Price: double.Price, MaxAdults: 2, TypeID: double.ID})
+	}
+	r := NewBookingsMemory(s)
+
+	tests := []struct {
+		name    string
+		room    int64
+		typeID  int64
+		start   string
+		end     string
+		want    pkg.Booking
+		wantErr error
+	}{
+		{
+			name:  "OK room of the type",
+			room:  1,
+			start: "2018-02-03",
+			end:   "2018-02-10",
+			want:  pkg.Booking{ID: 1, RoomID: 1, TypeID: double.ID},
+		},
+		{
+			name:   "OK without a room",
+			typeID: double.ID,
+			start:  "2018-02-05",
+			end:    "2018-02-12",
+			want:   pkg.Booking{ID: 2, TypeID: double.ID},
+		},
+		{
+			name:    "No free room of the type",
+			room:    2,
+			start:   "2018-02-08",
+			end:     "2018-02-09",
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name:   "OK after the type is free",
+			typeID: double.ID,
+			start:  "2018-02-10",
+			end:    "2018-02-14",
+			want:   pkg.Booking{ID: 3, TypeID: double.ID},
+		},
+		{
+			name:    "No Room Type",
+			typeID:  7,
+			start:   "2018-02-03",
+			end:     "2018-02-10",
+			wantErr: pkg.ErrNoRoomType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &pkg.Booking{TypeID: tt.typeID, Start: tt.start, End: tt.end, Status: pkg.StatusPending}
+			err := r.Add(ctx, tt.room, booking)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			} else if err == nil && (booking.ID != tt.want.ID || booking.RoomID != tt.want.RoomID || booking.TypeID != tt.want.TypeID) {
+				t.Error("wrong booking saved: ", booking)
+			}
+		})
+	}
+
+	err := r.Update(ctx, 2, func(b *pkg.Booking) error {
+		b.RoomID = 2
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.GetByID(ctx, 2)
+	want := pkg.Booking{ID: 2, RoomID: 2, TypeID: double.ID, Start: "2018-02-05", End: "2018-02-12", Status: pkg.StatusPending}
+	if err != nil || *got != want {
+		t.Error("wrong booking received: ", got, err)
+	}
+
+	_, err = r.GetByID(ctx, 9)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the free room is kept for the booking without a room
+	rooms, err := NewRoomMemory(s).GetAvailable(ctx, "2018-02-10", "2018-02-12", pkg.RoomQuery{Sort: pkg.SortByDate})
+	if err != nil || len(rooms) != 0 {
+		t.Error("wrong available rooms received: ", rooms, err)
+	}
+	rooms, err = NewRoomMemory(s).GetAvailable(ctx, "2018-02-12", "2018-02-14", pkg.RoomQuery{Sort: pkg.SortByDate})
+	if err != nil || len(rooms) != 2 {
+		t.Error("wrong available rooms received: ", rooms, err)
+	}
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type GuestsMemory struct {
+	s *Store
+}
+
+func NewGuestsMemory(s *Store) *GuestsMemory {
+	return &GuestsMemory{s: s}
+}
+
+// The guest belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the guest id.
+// Returns pkg.ErrGuestExists if the email is taken in the tenant.
+func (r *GuestsMemory) Add(ctx context.Context, guest *pkg.Guest) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, g := range r.s.guests {
+		if g.Email == guest.Email && r.s.owns(ctx, guestsTable, g.ID) {
+			return pkg.ErrGuestExists
+		}
+	}
+
+	r.s.guestID++
+	guest.ID = r.s.guestID
+	saved := *guest
+	r.s.guests[guest.ID] = &saved
+	r.s.own(ctx, guestsTable, guest.ID)
+	return nil
+}
+
+func (r *GuestsMemory) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	g, ok := r.s.guests[id]
+	if !ok || !r.s.owns(ctx, guestsTable, id) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	found := *g
+	return &found, nil
+}
+
+func (r *GuestsMemory) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, g := range r.s.guests {
+		if g.Email == email && r.s.owns(ctx, guestsTable, g.ID) {
+			found := *g
+			return &found, nil
+		}
+	}
+
+	return nil, pkg.ErrIDNotFound
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// date format of rooms and bookings
+const form = "2006-01-02"
+
+type storedRoom struct {
+	pkg.Room
+	deleted bool
+}
+
+// tables of the store
+const (
+	propertiesTable = "properties"
+	roomsTable      = "rooms"
+	typesTable      = "types"
+	bookingsTable   = "bookings"
+	ratesTable      = "rates"
+	guestsTable     = "guests"
+)
+
+// identifies a row of a table
+type row struct {
+	table string
+	id    int64
+}
+
+// Store keeps properties, rooms, room types, bookings, rate plans and guests
+// in memory, the repositories of the same store see each other's rows, so the
+// room, the type and the guest of a booking are checked like foreign keys.
+// Every row belongs to the tenant of the context it was added with,
+// the rows of other tenants are not found.
+type Store struct {
+	mu         sync.RWMutex
+	tenants    map[row]int64
+	properties map[int64]*pkg.Property
+	rooms      map[int64]*storedRoom
+	types      map[int64]*pkg.RoomType
+	bookings   map[int64]*pkg.Booking
+	rates      map[int64]*pkg.RatePlan
+	guests     map[int64]*pkg.Guest
+	keys       map[int64]*pkg.APIKey
+	propertyID int64
+	roomID     int64
+	typeID     int64
+	bookID     int64
+	rateID     int64
+	guestID    int64
+	keyID      int64
+	now        func() time.Time
+}
+
+// The store starts with the default property,
+// like the databases after the migrations.
+func NewStore() *Store {
+	return &Store{
+		tenants: map[row]int64{
+			{propertiesTable, pkg.DefaultPropertyID}: pkg.DefaultTenantID,
+		},
+		properties: map[int64]*pkg.Property{
+			pkg.DefaultPropertyID: {ID: pkg.DefaultPropertyID, Name: "Default", Timezone: "UTC", Currency: "USD"},
+		},
+		rooms:      make(map[int64]*storedRoom),
+		types:      make(map[int64]*pkg.RoomType),
+		bookings:   make(map[int64]*pkg.Booking),
+		rates:      make(map[int64]*pkg.RatePlan),
+		guests:     make(map[int64]*pkg.Guest),
+		keys:       make(map[int64]*pkg.APIKey),
+		propertyID: pkg.DefaultPropertyID,
+		now:        time.Now,
+	}
+}
+
+func (s *Store) today() string {
+	return s.now().Format(form)
+}
+
+// records the tenant of the context as the owner of the row,
+// the caller holds the lock
+func (s *Store) own(ctx context.Context, table string, id int64) {
+	s.tenants[row{table, id}] = pkg.TenantFrom(ctx)
+}
+
+// reports whether the row belongs to the tenant of the context,
+// the caller holds the lock
+func (s *Store) owns(ctx context.Context, table string, id int64) bool {
+	tenant, ok := s.tenants[row{table, id}]
+	return ok && tenant == pkg.TenantFrom(ctx)
+}
+
+// returns the room of the tenant if it exists,
+// the caller holds the lock
+func (s *Store) room(ctx context.Context, id int64) (*storedRoom, bool) {
+	r, ok := s.rooms[id]
+	if !ok || !s.owns(ctx, roomsTable, id) {
+		return nil, false
+	}
+	return r, true
+}
+
+// returns the room of the tenant if it is not archived,
+// the caller holds the lock
+func (s *Store) activeRoom(ctx context.Context, id int64) (*storedRoom, bool) {
+	r, ok := s.room(ctx, id)
+	if !ok || r.deleted {
+		return nil, false
+	}
+	return r, true
+}
+
+// checks that the room has no active booking, except the exclude one,
+// whose dates overlap with the range from start to end,
+// the caller holds the lock
+func (s *Store) checkConflict(roomID int64, start, end string, exclude int64) error {
+	for _, b := range s.bookings {
+		if b.RoomID == roomID && b.ID != exclude && b.Status.Active() &&
+			b.Start < end && b.End > start {
+			return pkg.ErrBookingConflict
+		}
+	}
+	return nil
+}
+
+// checks that the active bookings of the type, except the exclude
+// one, and the booking of the room from start to end can be given
+// rooms of the type, with the same room on all nights of a booking,
+// the caller holds the lock
+func (s *Store) checkType(ctx context.Context, typeID, roomID int64, start, end string, exclude int64) error {
+	if _, ok := s.types[typeID]; !ok || !s.owns(ctx, typesTable, typeID) {
+		return pkg.ErrNoRoomType
+	}
+
+	rooms := make([]int64, 0)
+	for id, r := range s.rooms {
+		if r.TypeID == typeID && !r.deleted && s.owns(ctx, roomsTable, id) {
+			rooms = append(rooms, id)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i] < rooms[j] })
+
+	stays := make([]pkg.Booking, 0)
+	for _, b := range s.bookings {
+		if b.TypeID == typeID && b.ID != exclude && b.Status.Active() &&
+			b.Start < end && b.End > start && s.owns(ctx, bookingsTable, b.ID) {
+			stays = append(stays, *b)
+		}
+	}
+
+	stays = append(stays, pkg.Booking{RoomID: roomID, Start: start, End: end})
+	if !pkg.Assignable(rooms, stays, start, end) {
+		return pkg.ErrBookingConflict
+	}
+	return nil
+}
+
+// reports whether the type has more rooms without bookings
+// from start to end than bookings without a room, a room
+// without a type is not limited, the caller holds the lock
+func (s *Store) typeHasRoom(typeID int64, start, end string) bool {
+	if typeID == 0 {
+		return true
+	}
+
+	unassigned := 0
+	for _, b := range s.bookings {
+		if b.TypeID == typeID && b.RoomID == 0 && b.Status.Active() &&
+			b.Start < end && b.End > start {
+			unassigned++
+		}
+	}
+
+	free := 0
+	for id, r := range s.rooms {
+		if r.TypeID == typeID && !r.deleted && s.checkConflict(id, start, end, 0) == nil {
+			free++
+		}
+	}
+	return unassigned < free
+}
+
+// sorts the rooms by the field, rooms sorted by price
+// are grouped by currency, rooms with the same value are ordered by id
+func sortRooms(rooms []pkg.Room, field pkg.RoomSort, desc bool) {
+	sort.Slice(rooms, func(i, j int) bool {
+		if field == pkg.SortByPrice && rooms[i].Price.Currency != rooms[j].Price.Currency {
+			return rooms[i].Price.Currency < rooms[j].Price.Currency
+		}
+
+		a, b := rooms[i], rooms[j]
+		if desc {
+			a, b = b, a
+		}
+		switch {
+		case field == pkg.SortByPrice && a.Price.Amount != b.Price.Amount:
+			return a.Price.Amount < b.Price.Amount
+		case field == pkg.SortByDate && a.Date != b.Date:
+			return a.Date < b.Date
+		}
+		return rooms[i].ID < rooms[j].ID
+	})
+}
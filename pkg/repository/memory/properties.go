@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type PropertiesMemory struct {
+	s *Store
+}
+
+func NewPropertiesMemory(s *Store) *PropertiesMemory {
+	return &PropertiesMemory{s: s}
+}
+
+// The property belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the property id.
+func (r *PropertiesMemory) Add(ctx context.Context, p *pkg.Property) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.propertyID++
+	p.ID = r.s.propertyID
+	saved := *p
+	r.s.properties[p.ID] = &saved
+	r.s.own(ctx, propertiesTable, p.ID)
+	return nil
+}
+
+// Replaces the property, its rooms and types are not changed.
+func (r *PropertiesMemory) Update(ctx context.Context, id int64, p *pkg.Property) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.properties[id]; !ok || !r.s.owns(ctx, propertiesTable, id) {
+		return pkg.ErrIDNotFound
+	}
+
+	saved := *p
+	saved.ID = id
+	r.s.properties[id] = &saved
+	return nil
+}
+
+// returns a copy of the property
+func (r *PropertiesMemory) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	p, ok := r.s.properties[id]
+	if !ok || !r.s.owns(ctx, propertiesTable, id) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	found := *p
+	return &found, nil
+}
+
+// returns the properties of the tenant sorted by id
+func (r *PropertiesMemory) List(ctx context.Context) ([]pkg.Property, error) {
+	r.s.mu.RLock()
+	properties := make([]pkg.Property, 0, len(r.s.properties))
+	for _, p := range r.s.properties {
+		if r.s.owns(ctx, propertiesTable, p.ID) {
+			properties = append(properties, *p)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(properties, func(i, j int) bool { return properties[i].ID < properties[j].ID })
+	return properties, nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RatePlansMemory struct {
+	s *Store
+}
+
+func NewRatePlansMemory(s *Store) *RatePlansMemory {
+	return &RatePlansMemory{s: s}
+}
+
+// The plan belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the plan id.
+// Returns pkg.ErrNoForeignKey if the room does not exist.
+func (r *RatePlansMemory) Add(ctx context.Context, plan *pkg.RatePlan) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.room(ctx, plan.RoomID); !ok {
+		return pkg.ErrNoForeignKey
+	}
+
+	r.s.rateID++
+	plan.ID = r.s.rateID
+	saved := *plan
+	r.s.rates[plan.ID] = &saved
+	r.s.own(ctx, ratesTable, plan.ID)
+	return nil
+}
+
+// Replaces the plan, its room is not changed.
+func (r *RatePlansMemory) Update(ctx context.Context, id int64, plan *pkg.RatePlan) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	old, ok := r.s.rates[id]
+	if !ok || !r.s.owns(ctx, ratesTable, id) {
+		return pkg.ErrIDNotFound
+	}
+
+	saved := *plan
+	saved.ID, saved.RoomID = id, old.RoomID
+	r.s.rates[id] = &saved
+	return nil
+}
+
+// Deletes the plan, the totals of the bookings
+// priced with it do not change.
+func (r *RatePlansMemory) Delete(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.rates[id]; !ok || !r.s.owns(ctx, ratesTable, id) {
+		return pkg.ErrIDNotFound
+	}
+
+	delete(r.s.rates, id)
+	delete(r.s.tenants, row{ratesTable, id})
+	return nil
+}
+
+func (r *RatePlansMemory) GetByID(ctx context.Context, id int64) (*pkg.RatePlan, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	plan, ok := r.s.rates[id]
+	if !ok || !r.s.owns(ctx, ratesTable, id) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	found := *plan
+	return &found, nil
+}
+
+// returns the plans of the rooms of the tenant sorted by room and id
+func (r *RatePlansMemory) Get(ctx context.Context, rooms []int64) ([]pkg.RatePlan, error) {
+	ids := make(map[int64]bool, len(rooms))
+	for _, id := range rooms {
+		ids[id] = true
+	}
+
+	r.s.mu.RLock()
+	plans := make([]pkg.RatePlan, 0)
+	for _, p := range r.s.rates {
+		if ids[p.RoomID] && r.s.owns(ctx, ratesTable, p.ID) {
+			plans = append(plans, *p)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(plans, func(i, j int) bool {
+		if plans[i].RoomID != plans[j].RoomID {
+			return plans[i].RoomID < plans[j].RoomID
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	return plans, nil
+}
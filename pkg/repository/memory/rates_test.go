@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestRatePlansMemory_Add(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	NewRoomMemory(s).Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r := NewRatePlansMemory(s)
+
+	tests := []struct {
+		name    string
+		plan    pkg.RatePlan
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			plan: pkg.RatePlan{
+				RoomID: 1,
+				Name:   "Summer",
+				Start:  "2018-06-01",
+				End:    "2018-09-01",
+				Price:  pkg.Money{Amount: 700, Currency: "USD"},
+			},
+			want: 1,
+		},
+		{
+			name: "OK without dates",
+			plan: pkg.RatePlan{
+				RoomID:   1,
+				Name:     "Weekend",
+				Weekdays: 1<<time.Saturday | 1<<time.Sunday,
+				Price:    pkg.Money{Amount: 600, Currency: "USD"},
+			},
+			want: 2,
+		},
+		{
+			name:    "No Foreign Key",
+			plan:    pkg.RatePlan{RoomID: 2, Price: pkg.Money{Amount: 700, Currency: "USD"}},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Add(ctx, &tt.plan)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.plan.ID != tt.want {
+				t.Error("wrong id received: ", tt.plan.ID)
+			}
+		})
+	}
+}
+
+func TestRatePlansMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	rooms := NewRoomMemory(s)
+	for _, room := range []pkg.Room{
+		{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}},
+		{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}},
+		{Description: "Euro", Price: pkg.Money{Amount: 900, Currency: "EUR"}},
+	} {
+		room := room
+		if err := rooms.Add(ctx, &room); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewRatePlansMemory(s)
+
+	plans := []pkg.RatePlan{
+		{RoomID: 2, Name: "Summer", Start: "2018-06-01", End: "2018-09-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+		{RoomID: 1, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 600, Currency: "USD"}, Priority: 1},
+		{RoomID: 2, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 1200, Currency: "USD"}},
+		{RoomID: 3, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 1000, Currency: "EUR"}},
+	}
+	for i := range plans {
+		if err := r.Add(ctx, &plans[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.Get(ctx, []int64{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []pkg.RatePlan{plans[1], plans[0], plans[2]}; !reflect.DeepEqual(got, want) {
+		t.Error("wrong plans received: ", got)
+	}
+
+	update := pkg.RatePlan{Name: "High season", Start: "2018-07-01", Price: pkg.Money{Amount: 1800, Currency: "USD"}, Priority: 2}
+	if err := r.Update(ctx, 1, &update); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := r.GetByID(ctx, 1)
+	update.ID, update.RoomID = 1, 2
+	if err != nil || !reflect.DeepEqual(*plan, update) {
+		t.Error("wrong plan saved: ", plan, err)
+	}
+
+	if err := r.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 3); err != pkg.ErrIDNotFound {
+		t.Error("plan is not deleted: ", err)
+	}
+
+	if err := r.Update(ctx, 9, &update); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Delete(ctx, 9); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if got, err := r.Get(ctx, nil); err != nil || len(got) != 0 {
+		t.Error("wrong plans received: ", got, err)
+	}
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomMemory struct {
+	s *Store
+}
+
+func NewRoomMemory(s *Store) *RoomMemory {
+	return &RoomMemory{s: s}
+}
+
+// Uses fields: PropertyID, Description, Price, MaxAdults, MaxChildren, TypeID.
+// The room belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the room id.
+// Returns pkg.ErrNoRoomType if the type does not exist.
+func (r *RoomMemory) Add(ctx context.Context, room *pkg.Room) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.types[room.TypeID]; room.TypeID != 0 && (!ok || !r.s.owns(ctx, typesTable, room.TypeID)) {
+		return pkg.ErrNoRoomType
+	}
+
+	r.s.roomID++
+	room.ID = r.s.roomID
+	room.Date = r.s.today()
+	r.s.rooms[room.ID] = &storedRoom{Room: *room}
+	r.s.own(ctx, roomsTable, room.ID)
+	return nil
+}
+
+// Archives the room, its bookings stay in the history.
+// A room with bookings that have not ended yet is archived
+// only with force, their pending and confirmed bookings are cancelled.
+// Returns pkg.ErrRoomHasGuests while a guest is checked in, even with force,
+// such a room is archived only after the check-out.
+// Bookings that end after today, a YYYY-MM-DD date, have not ended.
+func (r *RoomMemory) Delete(ctx context.Context, id int64, force bool, today string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	room, ok := r.s.activeRoom(ctx, id)
+	if !ok {
+		return pkg.ErrIDNotFound
+	}
+
+	for _, b := range r.s.bookings {
+		if b.RoomID == id && b.Status == pkg.StatusCheckedIn {
+			return pkg.ErrRoomHasGuests
+		}
+	}
+
+	future := make([]*pkg.Booking, 0)
+	for _, b := range r.s.bookings {
+		if b.RoomID == id && b.End > today && b.Status.Active() {
+			future = append(future, b)
+		}
+	}
+	if len(future) > 0 && !force {
+		return pkg.ErrRoomHasBookings
+	}
+
+	for _, b := range future {
+		if b.Status == pkg.StatusPending || b.Status == pkg.StatusConfirmed {
+			b.Status = pkg.StatusCancelled
+		}
+	}
+	room.deleted = true
+	return nil
+}
+
+// Returns the archived room to the lists,
+// restoring a room that is not archived does nothing.
+func (r *RoomMemory) Restore(ctx context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	room, ok := r.s.room(ctx, id)
+	if !ok {
+		return pkg.ErrIDNotFound
+	}
+
+	room.deleted = false
+	return nil
+}
+
+// Changes only the non-nil fields of the update.
+func (r *RoomMemory) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	if update.Description == nil && update.Price == nil &&
+		update.MaxAdults == nil && update.MaxChildren == nil {
+		return pkg.ErrNothingToUpdate
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	room, ok := r.s.activeRoom(ctx, id)
+	if !ok {
+		return pkg.ErrIDNotFound
+	}
+
+	if update.Description != nil {
+		room.Description = *update.Description
+	}
+	if update.Price != nil {
+		room.Price = *update.Price
+	}
+	if update.MaxAdults != nil {
+		room.MaxAdults = *update.MaxAdults
+	}
+	if update.MaxChildren != nil {
+		room.MaxChildren = *update.MaxChildren
+	}
+	return nil
+}
+
+// returns the property of the room,
+// archived rooms are also found
+func (r *RoomMemory) GetProperty(ctx context.Context, id int64) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	room, ok := r.s.room(ctx, id)
+	if !ok {
+		return 0, pkg.ErrIDNotFound
+	}
+
+	return room.PropertyID, nil
+}
+
+// returns a copy of the room if it is not archived
+func (r *RoomMemory) GetByID(ctx context.Context, id int64) (*pkg.Room, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	room, ok := r.s.activeRoom(ctx, id)
+	if !ok {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	found := room.Room
+	return &found, nil
+}
+
+// returns the rooms of the tenant that are not archived
+// and match the filter, the caller holds the lock
+func (r *RoomMemory) filter(ctx context.Context, filter *pkg.RoomFilter) []pkg.Room {
+	rooms := make([]pkg.Room, 0, len(r.s.rooms))
+	for _, room := range r.s.rooms {
+		if room.deleted || !r.s.owns(ctx, roomsTable, room.ID) {
+			continue
+		}
+		if filter.PropertyID != 0 && room.PropertyID != filter.PropertyID {
+			continue
+		}
+		if filter.Currency != "" && room.Price.Currency != filter.Currency {
+			continue
+		}
+		if filter.MinPrice != nil && room.Price.Amount < *filter.MinPrice {
+			continue
+		}
+		if filter.MaxPrice != nil && room.Price.Amount > *filter.MaxPrice {
+			continue
+		}
+		if !room.Fits(filter.Adults, filter.Children) {
+			continue
+		}
+		if filter.TypeID != 0 && room.TypeID != filter.TypeID {
+			continue
+		}
+		rooms = append(rooms, room.Room)
+	}
+	return rooms
+}
+
+// returns one page of the filtered rooms, rooms with
+// the same sort value are ordered by id
+func (r *RoomMemory) List(ctx context.Context, query pkg.RoomQuery) ([]pkg.Room, error) {
+	if query.Sort != pkg.SortByDate && query.Sort != pkg.SortByPrice {
+		return nil, pkg.ErrSortNotValid
+	}
+
+	r.s.mu.RLock()
+	rooms := r.filter(ctx, &query.RoomFilter)
+	r.s.mu.RUnlock()
+
+	sortRooms(rooms, query.Sort, query.Desc)
+
+	if query.Offset >= len(rooms) {
+		return []pkg.Room{}, nil
+	}
+	rooms = rooms[query.Offset:]
+	if len(rooms) > query.Limit {
+		rooms = rooms[:query.Limit]
+	}
+	return rooms, nil
+}
+
+// returns the number of rooms matching the filter,
+// Limit and Offset are not used
+func (r *RoomMemory) Count(ctx context.Context, filter *pkg.RoomFilter) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return int64(len(r.filter(ctx, filter))), nil
+}
+
+// returns rooms of the tenant that have no bookings
+// overlapping with the range from start to end, the rooms
+// of a type are returned while the type has more such rooms
+// than bookings without a room on these dates,
+// the rooms are filtered and sorted like in List,
+// Limit and Offset are not used
+func (r *RoomMemory) GetAvailable(ctx context.Context, start, end string, query pkg.RoomQuery) ([]pkg.Room, error) {
+	if query.Sort != pkg.SortByDate && query.Sort != pkg.SortByPrice {
+		return nil, pkg.ErrSortNotValid
+	}
+
+	r.s.mu.RLock()
+	rooms := make([]pkg.Room, 0)
+	for _, room := range r.filter(ctx, &query.RoomFilter) {
+		if r.s.checkConflict(room.ID, start, end, 0) == nil && r.s.typeHasRoom(room.TypeID, start, end) {
+			rooms = append(rooms, room)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sortRooms(rooms, query.Sort, query.Desc)
+	return rooms, nil
+}
@@ -0,0 +1,342 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// returns a store whose today is 2018-02-01
+func newTestStore() *Store {
+	s := NewStore()
+	s.now = func() time.Time {
+		return time.Date(2018, 2, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return s
+}
+
+func TestRoomMemory_Add(t *testing.T) {
+	r := NewRoomMemory(newTestStore())
+
+	for i, price := range []int64{350, 1000} {
+		room := &pkg.Room{Description: "Good", Price: pkg.Money{Amount: price, Currency: "USD"}}
+		err := r.Add(context.Background(), room)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if room.ID != int64(i+1) || room.Date != "2018-02-01" {
+			t.Error("wrong room saved: ", room)
+		}
+	}
+}
+
+func TestRoomMemory_List(t *testing.T) {
+	s := newTestStore()
+	r := NewRoomMemory(s)
+	ctx := context.Background()
+
+	for _, room := range []pkg.Room{
+		{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-03"},
+		{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+		{Description: "Cheap", Price: pkg.Money{Amount: 300, Currency: "USD"}, Date: "2018-01-02"},
+		{Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+		{PropertyID: 2, Description: "Euro", Price: pkg.Money{Amount: 400, Currency: "EUR"}, Date: "2018-01-05"},
+	} {
+		room := room
+		r.Add(ctx, &room)
+		s.rooms[room.ID].Date = room.Date
+	}
+	r.Delete(ctx, 1, false, "2018-02-01")
+
+	var minPrice int64 = 400
+
+	tests := []struct {
+		name    string
+		query   pkg.RoomQuery
+		want    []int64
+		wantErr error
+	}{
+		{
+			name:  "OK date",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}},
+			want:  []int64{2, 3, 4, 5},
+		},
+		{
+			name:  "OK price desc grouped by currency",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true, RoomFilter: pkg.RoomFilter{Limit: 20}},
+			want:  []int64{5, 2, 4, 3},
+		},
+		{
+			name: "OK page",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByPrice,
+				RoomFilter: pkg.RoomFilter{Limit: 1, Offset: 1},
+			},
+			want: []int64{3},
+		},
+		{
+			name: "OK price filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				RoomFilter: pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, Limit: 20},
+			},
+			want: []int64{2, 4},
+		},
+		{
+			name:  "OK guests",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Adults: 1, Children: 2, Limit: 20}},
+			want:  []int64{4},
+		},
+		{
+			// the room has three places, but only one for an adult
+			name:  "OK too many adults",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Adults: 3, Limit: 20}},
+			want:  []int64{},
+		},
+		{
+			name:  "OK property",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{PropertyID: 2, Limit: 20}},
+			want:  []int64{5},
+		},
+		{
+			name:  "OK after the last page",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20, Offset: 4}},
+			want:  []int64{},
+		},
+		{
+			name:    "Sort not valid",
+			query:   pkg.RoomQuery{Sort: "description"},
+			wantErr: pkg.ErrSortNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rooms, err := r.List(ctx, tt.query)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if len(rooms) != len(tt.want) {
+				t.Fatal("wrong number of rooms received: ", rooms)
+			}
+			for i := range tt.want {
+				if rooms[i].ID != tt.want[i] {
+					t.Error("array sorted incorrectly: ", rooms)
+				}
+			}
+		})
+	}
+
+	total, _ := r.Count(ctx, &pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice})
+	if total != 2 {
+		t.Error("wrong total received: ", total)
+	}
+}
+
+func TestRoomMemory_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		end        string
+		status     pkg.BookingStatus
+		force      bool
+		wantErr    error
+		wantStatus pkg.BookingStatus
+	}{
+		{
+			name:       "OK ended booking",
+			end:        "2018-01-20",
+			wantStatus: pkg.StatusPending,
+		},
+		{
+			name:       "Has bookings",
+			end:        "2018-02-10",
+			wantErr:    pkg.ErrRoomHasBookings,
+			wantStatus: pkg.StatusPending,
+		},
+		{
+			name:       "OK force",
+			end:        "2018-02-10",
+			force:      true,
+			wantStatus: pkg.StatusCancelled,
+		},
+		{
+			name:       "Has guests",
+			end:        "2018-02-10",
+			status:     pkg.StatusCheckedIn,
+			force:      true,
+			wantErr:    pkg.ErrRoomHasGuests,
+			wantStatus: pkg.StatusCheckedIn,
+		},
+		{
+			name:       "Has guests leaving today",
+			end:        "2018-02-01",
+			status:     pkg.StatusCheckedIn,
+			wantErr:    pkg.ErrRoomHasGuests,
+			wantStatus: pkg.StatusCheckedIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore()
+			r := NewRoomMemory(s)
+			b := NewBookingsMemory(s)
+
+			r.Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+			status := pkg.StatusPending
+			if tt.status != "" {
+				status = tt.status
+			}
+			booking := &pkg.Booking{Start: "2018-01-15", End: tt.end, Status: status}
+			b.Add(ctx, 1, booking)
+
+			err := r.Delete(ctx, 1, tt.force, "2018-02-01")
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+
+			bookings, err := b.Get(ctx, 1, nil)
+			if err != nil || bookings[0].Status != tt.wantStatus {
+				t.Error("wrong booking status: ", bookings, err)
+			}
+
+			total, _ := r.Count(ctx, &pkg.RoomFilter{})
+			if archived := total == 0; archived != (tt.wantErr == nil) {
+				t.Error("room archived incorrectly")
+			}
+		})
+	}
+
+	r := NewRoomMemory(newTestStore())
+	if err := r.Delete(ctx, 5, false, "2018-02-01"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomMemory_Restore(t *testing.T) {
+	ctx := context.Background()
+	r := NewRoomMemory(newTestStore())
+	r.Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+
+	r.Delete(ctx, 1, false, "2018-02-01")
+	price := pkg.Money{Amount: 700, Currency: "USD"}
+	if err := r.Update(ctx, 1, &pkg.RoomUpdate{Price: &price}); err != pkg.ErrIDNotFound {
+		t.Error("archived room updated: ", err)
+	}
+
+	if err := r.Restore(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(ctx, 1, &pkg.RoomUpdate{Price: &price}); err != nil {
+		t.Error(err)
+	}
+	if err := r.Restore(ctx, 2); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomMemory_GetProperty(t *testing.T) {
+	ctx := context.Background()
+	r := NewRoomMemory(newTestStore())
+	r.Add(ctx, &pkg.Room{PropertyID: 2, Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r.Delete(ctx, 1, false, "2018-02-01")
+
+	property, err := r.GetProperty(ctx, 1)
+	if err != nil || property != 2 {
+		t.Error("wrong property received: ", property, err)
+	}
+	if _, err := r.GetProperty(ctx, 2); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomMemory_GetByID(t *testing.T) {
+	ctx := context.Background()
+	r := NewRoomMemory(newTestStore())
+	r.Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}})
+	r.Add(ctx, &pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "EUR"}})
+	r.Delete(ctx, 1, false, "2018-02-01")
+
+	room, err := r.GetByID(ctx, 2)
+	want := pkg.Room{ID: 2, Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "EUR"}, Date: "2018-02-01"}
+	if err != nil || *room != want {
+		t.Error("wrong room received: ", room, err)
+	}
+
+	for _, id := range []int64{1, 3} {
+		if _, err := r.GetByID(ctx, id); err != pkg.ErrIDNotFound {
+			t.Error("incorrect error received: ", err)
+		}
+	}
+}
+
+func TestRoomMemory_Tenants(t *testing.T) {
+	ctx := context.Background()
+	other := pkg.WithTenant(ctx, 2)
+	s := newTestStore()
+	r := NewRoomMemory(s)
+
+	seaside := pkg.Property{Name: "Seaside", Timezone: "UTC", Currency: "USD"}
+	if err := NewPropertiesMemory(s).Add(other, &seaside); err != nil {
+		t.Fatal(err)
+	}
+	room := pkg.Room{PropertyID: seaside.ID, Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}}
+	if err := r.Add(other, &room); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.GetByID(other, room.ID); err != nil {
+		t.Error("the room is not found in its tenant: ", err)
+	}
+
+	// the rows of the second tenant are not seen by the first one
+	if _, err := NewPropertiesMemory(s).GetByID(ctx, seaside.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if _, err := r.GetByID(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if _, err := r.GetProperty(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	description := "VIP"
+	if err := r.Update(ctx, room.ID, &pkg.RoomUpdate{Description: &description}); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Delete(ctx, room.ID, true, "2018-02-01"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Restore(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	rooms, err := r.List(ctx, pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}})
+	if err != nil || len(rooms) != 0 {
+		t.Error("wrong rooms received: ", rooms, err)
+	}
+	total, err := r.Count(ctx, &pkg.RoomFilter{})
+	if err != nil || total != 0 {
+		t.Error("wrong total received: ", total, err)
+	}
+	rooms, err = r.GetAvailable(ctx, "2018-02-03", "2018-02-10", pkg.RoomQuery{Sort: pkg.SortByDate})
+	if err != nil || len(rooms) != 0 {
+		t.Error("wrong available rooms received: ", rooms, err)
+	}
+
+	b := pkg.Booking{Start: "2018-02-03", End: "2018-02-10"}
+	if err := NewBookingsMemory(s).Add(ctx, room.ID, &b); err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := NewBookingsMemory(s).Add(other, room.ID, &b); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewBookingsMemory(s).GetByID(ctx, b.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := NewRatePlansMemory(s).Add(ctx, &pkg.RatePlan{RoomID: room.ID}); err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomTypesMemory struct {
+	s *Store
+}
+
+func NewRoomTypesMemory(s *Store) *RoomTypesMemory {
+	return &RoomTypesMemory{s: s}
+}
+
+// The type belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the type id.
+func (r *RoomTypesMemory) Add(ctx context.Context, t *pkg.RoomType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.typeID++
+	t.ID = r.s.typeID
+	saved := *t
+	r.s.types[t.ID] = &saved
+	r.s.own(ctx, typesTable, t.ID)
+	return nil
+}
+
+// Replaces the type, the rooms of the type get its fields.
+func (r *RoomTypesMemory) Update(ctx context.Context, id int64, t *pkg.RoomType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.types[id]; !ok || !r.s.owns(ctx, typesTable, id) {
+		return pkg.ErrIDNotFound
+	}
+
+	saved := *t
+	saved.ID = id
+	r.s.types[id] = &saved
+
+	for roomID, room := range r.s.rooms {
+		if room.TypeID == id && r.s.owns(ctx, roomsTable, roomID) {
+			room.Description = t.Description
+			room.Price = t.Price
+			room.MaxAdults = t.MaxAdults
+			room.MaxChildren = t.MaxChildren
+		}
+	}
+	return nil
+}
+
+// returns a copy of the type
+func (r *RoomTypesMemory) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	t, ok := r.s.types[id]
+	if !ok || !r.s.owns(ctx, typesTable, id) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	found := *t
+	return &found, nil
+}
+
+// returns the types of the tenant sorted by id,
+// if property is not zero, only types of the property
+func (r *RoomTypesMemory) List(ctx context.Context, property int64) ([]pkg.RoomType, error) {
+	r.s.mu.RLock()
+	types := make([]pkg.RoomType, 0, len(r.s.types))
+	for _, t := range r.s.types {
+		if !r.s.owns(ctx, typesTable, t.ID) {
+			continue
+		}
+		if property == 0 || t.PropertyID == property {
+			types = append(types, *t)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(types, func(i, j int) bool { return types[i].ID < types[j].ID })
+	return types, nil
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestRoomTypesMemory(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore()
+	r := NewRoomTypesMemory(s)
+
+	single := pkg.RoomType{Name: "Single", Price: pkg.Money{Amount: 8000, Currency: "USD"}, MaxAdults: 1}
+	double := pkg.RoomType{Name: "Double", Description: "two beds", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2}
+	for _, rt := range []*pkg.RoomType{&single, &double} {
+		if err := r.Add(ctx, rt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if single.ID != 1 || double.ID != 2 {
+		t.Fatal("wrong ids received: ", single.ID, double.ID)
+	}
+
+	rooms := NewRoomMemory(s)
+	err := rooms.Add(ctx, &pkg.Room{Description: "two beds", Price: double.Price, MaxAdults: 2, TypeID: double.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rooms.Add(ctx, &pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, MaxAdults: 2})
+	err = rooms.Add(ctx, &pkg.Room{Description: "Good", TypeID: 7})
+	if err != pkg.ErrNoRoomType {
+		t.Error("incorrect error received: ", err)
+	}
+
+	double.Description = "two beds, sea view"
+	double.Price.Amount = 15000
+	double.MaxChildren = 1
+	err = r.Update(ctx, double.ID, &double)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	room, err := rooms.GetByID(ctx, 1)
+	want := pkg.Room{ID: 1, TypeID: double.ID, Date: "2018-02-01", Description: "two beds, sea view", Price: double.Price, MaxAdults: 2, MaxChildren: 1}
+	if err != nil || *room != want {
+		t.Error("wrong room of the type: ", room, err)
+	}
+	room, err = rooms.GetByID(ctx, 2)
+	if err != nil || room.Description != "Good" {
+		t.Error("room without a type changed: ", room, err)
+	}
+
+	err = r.Update(ctx, 7, &double)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	got, err := r.GetByID(ctx, double.ID)
+	if err != nil || *got != double {
+		t.Error("wrong type received: ", got, err)
+	}
+	_, err = r.GetByID(ctx, 7)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	list, err := r.List(ctx, 0)
+	if err != nil || !reflect.DeepEqual(list, []pkg.RoomType{single, double}) {
+		t.Error("wrong types received: ", list, err)
+	}
+
+	suite := pkg.RoomType{PropertyID: 2, Name: "Suite", Price: pkg.Money{Amount: 30000, Currency: "EUR"}, MaxAdults: 2}
+	r.Add(ctx, &suite)
+	list, err = r.List(ctx, 2)
+	if err != nil || !reflect.DeepEqual(list, []pkg.RoomType{suite}) {
+		t.Error("wrong types of the property received: ", list, err)
+	}
+}
+
+func TestRoomTypesMemory_Tenants(t *testing.T) {
+	ctx := context.Background()
+	other := pkg.WithTenant(ctx, 2)
+	s := newTestStore()
+	types := NewRoomTypesMemory(s)
+	rooms := NewRoomMemory(s)
+	bookings := NewBookingsMemory(s)
+
+	double := pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2}
+	if err := types.Add(ctx, &double); err != nil {
+		t.Fatal(err)
+	}
+	if err := rooms.Add(ctx, &pkg.Room{Description: "two beds", Price: double.Price, MaxAdults: 2, TypeID: double.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second tenant has rooms and a booking on the same type id
+	twin := pkg.RoomType{Name: "Twin", Price: pkg.Money{Amount: 9000, Currency: "USD"}, MaxAdults: 2}
+	if err := types.Add(other, &twin); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := rooms.Add(other, &pkg.Room{Description: "twin beds", Price: twin.Price, MaxAdults: 2, TypeID: twin.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stay := pkg.Booking{TypeID: twin.ID, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending}
+	if err := bookings.Add(other, 0, &stay); err != nil {
+		t.Fatal(err)
+	}
+	for _, room := range s.rooms {
+		if room.TypeID == twin.ID {
+			room.TypeID = double.ID
+		}
+	}
+	s.bookings[stay.ID].TypeID = double.ID
+
+	// the rooms of the second tenant do not free the type of the first one
+	first := pkg.Booking{TypeID: double.ID, Start: "2018-02-03", End: "2018-02-05", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &first); err != nil {
+		t.Fatal(err)
+	}
+	second := pkg.Booking{TypeID: double.ID, Start: "2018-02-03", End: "2018-02-05", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &second); err != pkg.ErrBookingConflict {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the booking of the second tenant does not take the room of the first one
+	third := pkg.Booking{TypeID: double.ID, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &third); err != nil {
+		t.Error(err)
+	}
+
+	// the update of the type does not rewrite the rooms of the second tenant
+	double.Description = "two beds, sea view"
+	if err := types.Update(ctx, double.ID, &double); err != nil {
+		t.Fatal(err)
+	}
+	for id := int64(2); id <= 3; id++ {
+		room, err := rooms.GetByID(other, id)
+		if err != nil || room.Description != "twin beds" || room.Price != twin.Price {
+			t.Error("room of the second tenant changed: ", room, err)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package mock_repository
 
 import (
+	context "context"
 	reflect "reflect"
 
 	pkg "github.com/Avepa/booking/pkg"
@@ -35,91 +36,134 @@ func (m *MockRoom) EXPECT() *MockRoomMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockRoom) Add(room *pkg.Room) error {
+func (m *MockRoom) Add(ctx context.Context, room *pkg.Room) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", room)
+	ret := m.ctrl.Call(m, "Add", ctx, room)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockRoomMockRecorder) Add(room interface{}) *gomock.Call {
+func (mr *MockRoomMockRecorder) Add(ctx, room interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoom)(nil).Add), room)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoom)(nil).Add), ctx, room)
+}
+
+// Count mocks base method.
+func (m *MockRoom) Count(ctx context.Context, filter *pkg.RoomFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockRoomMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRoom)(nil).Count), ctx, filter)
 }
 
 // Delete mocks base method.
-func (m *MockRoom) Delete(id int64) error {
+func (m *MockRoom) Delete(ctx context.Context, id int64, force bool, today string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, force, today)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRoomMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockRoomMockRecorder) Delete(ctx, id, force, today interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoom)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoom)(nil).Delete), ctx, id, force, today)
 }
 
-// GetByDate mocks base method.
-func (m *MockRoom) GetByDate() ([]pkg.Room, error) {
+// GetAvailable mocks base method.
+func (m *MockRoom) GetAvailable(ctx context.Context, start, end string, query pkg.RoomQuery) ([]pkg.Room, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDate")
+	ret := m.ctrl.Call(m, "GetAvailable", ctx, start, end, query)
 	ret0, _ := ret[0].([]pkg.Room)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByDate indicates an expected call of GetByDate.
-func (mr *MockRoomMockRecorder) GetByDate() *gomock.Call {
+// GetAvailable indicates an expected call of GetAvailable.
+func (mr *MockRoomMockRecorder) GetAvailable(ctx, start, end, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDate", reflect.TypeOf((*MockRoom)(nil).GetByDate))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailable", reflect.TypeOf((*MockRoom)(nil).GetAvailable), ctx, start, end, query)
 }
 
-// GetByDateDESC mocks base method.
-func (m *MockRoom) GetByDateDESC() ([]pkg.Room, error) {
+// GetByID mocks base method.
+func (m *MockRoom) GetByID(ctx context.Context, id int64) (*pkg.Room, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDateDESC")
-	ret0, _ := ret[0].([]pkg.Room)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Room)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByDateDESC indicates an expected call of GetByDateDESC.
-func (mr *MockRoomMockRecorder) GetByDateDESC() *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRoomMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDateDESC", reflect.TypeOf((*MockRoom)(nil).GetByDateDESC))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRoom)(nil).GetByID), ctx, id)
 }
 
-// GetByPrice mocks base method.
-func (m *MockRoom) GetByPrice() ([]pkg.Room, error) {
+// GetProperty mocks base method.
+func (m *MockRoom) GetProperty(ctx context.Context, id int64) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByPrice")
-	ret0, _ := ret[0].([]pkg.Room)
+	ret := m.ctrl.Call(m, "GetProperty", ctx, id)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByPrice indicates an expected call of GetByPrice.
-func (mr *MockRoomMockRecorder) GetByPrice() *gomock.Call {
+// GetProperty indicates an expected call of GetProperty.
+func (mr *MockRoomMockRecorder) GetProperty(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrice", reflect.TypeOf((*MockRoom)(nil).GetByPrice))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProperty", reflect.TypeOf((*MockRoom)(nil).GetProperty), ctx, id)
 }
 
-// GetByPriceDESC mocks base method.
-func (m *MockRoom) GetByPriceDESC() ([]pkg.Room, error) {
+// List mocks base method.
+func (m *MockRoom) List(ctx context.Context, query pkg.RoomQuery) ([]pkg.Room, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByPriceDESC")
+	ret := m.ctrl.Call(m, "List", ctx, query)
 	ret0, _ := ret[0].([]pkg.Room)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByPriceDESC indicates an expected call of GetByPriceDESC.
-func (mr *MockRoomMockRecorder) GetByPriceDESC() *gomock.Call {
+// List indicates an expected call of List.
+func (mr *MockRoomMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoom)(nil).List), ctx, query)
+}
+
+// Restore mocks base method.
+func (m *MockRoom) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRoomMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRoom)(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRoom) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomMockRecorder) Update(ctx, id, update interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPriceDESC", reflect.TypeOf((*MockRoom)(nil).GetByPriceDESC))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoom)(nil).Update), ctx, id, update)
 }
 
 // MockBookings is a mock of Bookings interface.
@@ -146,44 +190,464 @@ func (m *MockBookings) EXPECT() *MockBookingsMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockBookings) Add(room int64, bookings *pkg.Booking) error {
+func (m *MockBookings) Add(ctx context.Context, room int64, bookings *pkg.Booking) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", room, bookings)
+	ret := m.ctrl.Call(m, "Add", ctx, room, bookings)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockBookingsMockRecorder) Add(room, bookings interface{}) *gomock.Call {
+func (mr *MockBookingsMockRecorder) Add(ctx, room, bookings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBookings)(nil).Add), ctx, room, bookings)
+}
+
+// Get mocks base method.
+func (m *MockBookings) Get(ctx context.Context, id int64, status []pkg.BookingStatus) ([]pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id, status)
+	ret0, _ := ret[0].([]pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBookingsMockRecorder) Get(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBookings)(nil).Get), ctx, id, status)
+}
+
+// GetByGuest mocks base method.
+func (m *MockBookings) GetByGuest(ctx context.Context, guest int64) ([]pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByGuest", ctx, guest)
+	ret0, _ := ret[0].([]pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByGuest indicates an expected call of GetByGuest.
+func (mr *MockBookingsMockRecorder) GetByGuest(ctx, guest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByGuest", reflect.TypeOf((*MockBookings)(nil).GetByGuest), ctx, guest)
+}
+
+// GetByID mocks base method.
+func (m *MockBookings) GetByID(ctx context.Context, id int64) (*pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBookingsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBookings)(nil).Add), room, bookings)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBookings)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockBookings) Update(ctx context.Context, id int64, change func(*pkg.Booking) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBookingsMockRecorder) Update(ctx, id, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBookings)(nil).Update), ctx, id, change)
+}
+
+// MockRoomTypes is a mock of RoomTypes interface.
+type MockRoomTypes struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomTypesMockRecorder
+}
+
+// MockRoomTypesMockRecorder is the mock recorder for MockRoomTypes.
+type MockRoomTypesMockRecorder struct {
+	mock *MockRoomTypes
+}
+
+// NewMockRoomTypes creates a new mock instance.
+func NewMockRoomTypes(ctrl *gomock.Controller) *MockRoomTypes {
+	mock := &MockRoomTypes{ctrl: ctrl}
+	mock.recorder = &MockRoomTypesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomTypes) EXPECT() *MockRoomTypesMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRoomTypes) Add(ctx context.Context, t *pkg.RoomType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRoomTypesMockRecorder) Add(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoomTypes)(nil).Add), ctx, t)
+}
+
+// GetByID mocks base method.
+func (m *MockRoomTypes) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.RoomType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRoomTypesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRoomTypes)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockRoomTypes) List(ctx context.Context, property int64) ([]pkg.RoomType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, property)
+	ret0, _ := ret[0].([]pkg.RoomType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoomTypesMockRecorder) List(ctx, property interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoomTypes)(nil).List), ctx, property)
+}
+
+// Update mocks base method.
+func (m *MockRoomTypes) Update(ctx context.Context, id int64, t *pkg.RoomType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomTypesMockRecorder) Update(ctx, id, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoomTypes)(nil).Update), ctx, id, t)
+}
+
+// MockProperties is a mock of Properties interface.
+type MockProperties struct {
+	ctrl     *gomock.Controller
+	recorder *MockPropertiesMockRecorder
+}
+
+// MockPropertiesMockRecorder is the mock recorder for MockProperties.
+type MockPropertiesMockRecorder struct {
+	mock *MockProperties
+}
+
+// NewMockProperties creates a new mock instance.
+func NewMockProperties(ctrl *gomock.Controller) *MockProperties {
+	mock := &MockProperties{ctrl: ctrl}
+	mock.recorder = &MockPropertiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProperties) EXPECT() *MockPropertiesMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockProperties) Add(ctx context.Context, p *pkg.Property) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockPropertiesMockRecorder) Add(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockProperties)(nil).Add), ctx, p)
+}
+
+// GetByID mocks base method.
+func (m *MockProperties) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPropertiesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProperties)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockProperties) List(ctx context.Context) ([]pkg.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]pkg.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPropertiesMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProperties)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockProperties) Update(ctx context.Context, id int64, p *pkg.Property) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, p)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPropertiesMockRecorder) Update(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProperties)(nil).Update), ctx, id, p)
+}
+
+// MockRatePlans is a mock of RatePlans interface.
+type MockRatePlans struct {
+	ctrl     *gomock.Controller
+	recorder *MockRatePlansMockRecorder
+}
+
+// MockRatePlansMockRecorder is the mock recorder for MockRatePlans.
+type MockRatePlansMockRecorder struct {
+	mock *MockRatePlans
+}
+
+// NewMockRatePlans creates a new mock instance.
+func NewMockRatePlans(ctrl *gomock.Controller) *MockRatePlans {
+	mock := &MockRatePlans{ctrl: ctrl}
+	mock.recorder = &MockRatePlansMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRatePlans) EXPECT() *MockRatePlansMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRatePlans) Add(ctx context.Context, plan *pkg.RatePlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRatePlansMockRecorder) Add(ctx, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRatePlans)(nil).Add), ctx, plan)
 }
 
 // Delete mocks base method.
-func (m *MockBookings) Delete(id int64) error {
+func (m *MockRatePlans) Delete(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBookingsMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockRatePlansMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBookings)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRatePlans)(nil).Delete), ctx, id)
 }
 
 // Get mocks base method.
-func (m *MockBookings) Get(id int64) ([]pkg.Booking, error) {
+func (m *MockRatePlans) Get(ctx context.Context, rooms []int64) ([]pkg.RatePlan, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", id)
-	ret0, _ := ret[0].([]pkg.Booking)
+	ret := m.ctrl.Call(m, "Get", ctx, rooms)
+	ret0, _ := ret[0].([]pkg.RatePlan)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockBookingsMockRecorder) Get(id interface{}) *gomock.Call {
+func (mr *MockRatePlansMockRecorder) Get(ctx, rooms interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRatePlans)(nil).Get), ctx, rooms)
+}
+
+// GetByID mocks base method.
+func (m *MockRatePlans) GetByID(ctx context.Context, id int64) (*pkg.RatePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.RatePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRatePlansMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRatePlans)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRatePlans) Update(ctx context.Context, id int64, plan *pkg.RatePlan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, plan)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRatePlansMockRecorder) Update(ctx, id, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRatePlans)(nil).Update), ctx, id, plan)
+}
+
+// MockGuests is a mock of Guests interface.
+type MockGuests struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestsMockRecorder
+}
+
+// MockGuestsMockRecorder is the mock recorder for MockGuests.
+type MockGuestsMockRecorder struct {
+	mock *MockGuests
+}
+
+// NewMockGuests creates a new mock instance.
+func NewMockGuests(ctrl *gomock.Controller) *MockGuests {
+	mock := &MockGuests{ctrl: ctrl}
+	mock.recorder = &MockGuestsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuests) EXPECT() *MockGuestsMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockGuests) Add(ctx context.Context, guest *pkg.Guest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, guest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockGuestsMockRecorder) Add(ctx, guest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockGuests)(nil).Add), ctx, guest)
+}
+
+// GetByEmail mocks base method.
+func (m *MockGuests) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret0, _ := ret[0].(*pkg.Guest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockGuestsMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockGuests)(nil).GetByEmail), ctx, email)
+}
+
+// GetByID mocks base method.
+func (m *MockGuests) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Guest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockGuestsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockGuests)(nil).GetByID), ctx, id)
+}
+
+// MockAPIKeys is a mock of APIKeys interface.
+type MockAPIKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysMockRecorder
+}
+
+// MockAPIKeysMockRecorder is the mock recorder for MockAPIKeys.
+type MockAPIKeysMockRecorder struct {
+	mock *MockAPIKeys
+}
+
+// NewMockAPIKeys creates a new mock instance.
+func NewMockAPIKeys(ctrl *gomock.Controller) *MockAPIKeys {
+	mock := &MockAPIKeys{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeys) EXPECT() *MockAPIKeysMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAPIKeys) Add(ctx context.Context, key *pkg.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAPIKeysMockRecorder) Add(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAPIKeys)(nil).Add), ctx, key)
+}
+
+// GetByHash mocks base method.
+func (m *MockAPIKeys) GetByHash(ctx context.Context, hash string) (*pkg.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, hash)
+	ret0, _ := ret[0].(*pkg.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAPIKeysMockRecorder) GetByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAPIKeys)(nil).GetByHash), ctx, hash)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeys) Revoke(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeysMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBookings)(nil).Get), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeys)(nil).Revoke), ctx, id)
 }
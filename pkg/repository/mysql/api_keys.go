@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type APIKeysMySQL struct {
+	db *sql.DB
+}
+
+func NewAPIKeysMySQL(db *sql.DB) *APIKeysMySQL {
+	return &APIKeysMySQL{db: db}
+}
+
+// The key belongs to the tenant of its tenant_id field,
+// the keys are not scoped by the tenant of the context,
+// they name it. On successful creation,
+// in the id field records the key id.
+func (r *APIKeysMySQL) Add(ctx context.Context, key *pkg.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO `api_keys` (`tenant_id`, `role`, `key_hash`) VALUES (?, ?, ?)",
+		key.TenantID,
+		key.Role,
+		key.Hash,
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	key.ID, err = res.LastInsertId()
+	return err
+}
+
+// returns pkg.ErrIDNotFound if the key
+// does not exist or is already revoked
+func (r *APIKeysMySQL) Revoke(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE `api_keys` SET `revoked_at` = NOW() WHERE `id` = ? AND `revoked_at` IS NULL",
+		id,
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// returns the key that is not revoked by its hash,
+// pkg.ErrIDNotFound if there is none
+func (r *APIKeysMySQL) GetByHash(ctx context.Context, hash string) (*pkg.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	key := pkg.APIKey{}
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT `id`, `tenant_id`, `role`, `key_hash` FROM `api_keys` WHERE `key_hash` = ? AND `revoked_at` IS NULL",
+		hash,
+	).Scan(&key.ID, &key.TenantID, &key.Role, &key.Hash)
+	if err == sql.ErrNoRows {
+		return nil, pkg.ErrIDNotFound
+	} else if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return &key, nil
+}
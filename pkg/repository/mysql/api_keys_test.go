@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestAPIKeysMySQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewAPIKeysMySQL(db)
+	ctx := context.Background()
+	columns := []string{"id", "tenant_id", "role", "key_hash"}
+
+	key := pkg.APIKey{TenantID: 2, Role: pkg.RoleStaff, Hash: "ab12"}
+	mock.ExpectExec("INSERT INTO `api_keys` (.+) VALUES (.+)").
+		WithArgs(2, pkg.RoleStaff, "ab12").
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	err = r.Add(ctx, &key)
+	if err != nil || key.ID != 3 {
+		t.Error("wrong key saved: ", key, err)
+	}
+
+	mock.ExpectExec("INSERT INTO `api_keys`").WillReturnError(sql.ErrConnDone)
+	err = r.Add(ctx, &pkg.APIKey{TenantID: 1, Role: pkg.RoleAdmin, Hash: "cd34"})
+	if !errors.Is(err, pkg.ErrFailedSave) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `api_keys` WHERE `key_hash` = (.+) AND `revoked_at` IS NULL").
+		WithArgs("ab12").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 2, "staff", "ab12"))
+	got, err := r.GetByHash(ctx, "ab12")
+	if err != nil || *got != key {
+		t.Error("wrong key received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `api_keys`").
+		WithArgs("ef56").
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByHash(ctx, "ef56")
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectExec("UPDATE `api_keys` SET `revoked_at` = NOW\\(\\) WHERE `id` = (.+) AND `revoked_at` IS NULL").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := r.Revoke(ctx, 3); err != nil {
+		t.Error(err)
+	}
+
+	// the key is already revoked
+	mock.ExpectExec("UPDATE `api_keys`").
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if err := r.Revoke(ctx, 3); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
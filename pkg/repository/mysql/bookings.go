@@ -1,7 +1,12 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
 
 	"github.com/Avepa/booking/pkg"
 )
@@ -14,85 +19,428 @@ func NewBookingsMySQL(db *sql.DB) *BookingsMySQL {
 	return &BookingsMySQL{db: db}
 }
 
-func (r *BookingsMySQL) Add(room int64, bookings *pkg.Booking) error {
-	res, err := r.db.Exec(
-		"INSERT INTO bookings (room_id, date_start, date_end) VALUES (?, ?, ?)",
-		room,
+// The room row, and the type row of a room of a type, are locked
+// for the duration of the transaction, so concurrent bookings
+// of the same room or type are checked one by one.
+// The booking gets the type of the room, with a zero room
+// the type of the booking is booked without a room.
+// Returns pkg.ErrBookingConflict if the dates overlap
+// with an existing booking of the room or the type
+// has no free room on one of the nights.
+// The room and the type must belong to the tenant of the context.
+func (r *BookingsMySQL) Add(ctx context.Context, room int64, bookings *pkg.Booking) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	if room == 0 && bookings.TypeID == 0 {
+		return pkg.ErrNoForeignKey
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if room != 0 {
+		bookings.TypeID, err = lockRoom(ctx, tx, room)
+		if err != nil {
+			return err
+		}
+
+		err = checkConflict(ctx, tx, room, bookings.Start, bookings.End, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	if bookings.TypeID != 0 {
+		err = checkType(ctx, tx, bookings.TypeID, room, bookings.Start, bookings.End, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	amount, currency := totalArgs(bookings.Total)
+	res, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO bookings (tenant_id, room_id, type_id, guest_id, adults, children, date_start, date_end, status,"+
+			" total_amount, total_currency) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		nullID(room),
+		nullID(bookings.TypeID),
+		nullID(bookings.GuestID),
+		bookings.Adults,
+		bookings.Children,
 		bookings.Start,
 		bookings.End,
+		bookings.Status,
+		amount,
+		currency,
 	)
 	if err != nil {
-		n := len(pkg.ErrNoForeignKey.Error())
-		if len(err.Error()) >= n {
-			if err.Error()[:n] == pkg.ErrNoForeignKey.Error() {
-				err = pkg.ErrNoForeignKey
-			}
+		var myErr *driver.MySQLError
+		if errors.As(err, &myErr) && myErr.Number == errNoReferencedRow {
+			err = pkg.ErrNoForeignKey
 		}
 		return err
 	}
 
+	bookings.RoomID = room
 	bookings.ID, err = res.LastInsertId()
-	return err
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// columns of the booking, the total columns are NULL for old bookings
+const bookingColumns = "SELECT `id`, `room_id`, `type_id`, `guest_id`, `adults`, `children`," +
+	" `date_start`, `date_end`, `status`, `total_amount`, `total_currency` FROM `bookings`"
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func (r *BookingsMySQL) Delete(id int64) error {
-	res, err := r.db.Exec(
-		"DELETE FROM bookings WHERE id = ?",
+// reads the columns of bookingColumns, the total is NULL
+// for the bookings made before the prices were saved,
+// the guest is NULL for the bookings without a guest,
+// the room is NULL until the room of a type is assigned
+func scanBooking(row scanner, b *pkg.Booking) error {
+	var room, roomType, guest, amount sql.NullInt64
+	var currency sql.NullString
+	err := row.Scan(&b.ID, &room, &roomType, &guest, &b.Adults, &b.Children, &b.Start, &b.End, &b.Status, &amount, &currency)
+	if err != nil {
+		return err
+	}
+
+	b.RoomID = room.Int64
+	b.TypeID = roomType.Int64
+	b.GuestID = guest.Int64
+
+	if amount.Valid && currency.Valid {
+		b.Total = &pkg.Money{Amount: amount.Int64, Currency: currency.String}
+	}
+	return nil
+}
+
+// returns the total as the values of its two nullable columns
+func totalArgs(total *pkg.Money) (interface{}, interface{}) {
+	if total == nil {
+		return nil, nil
+	}
+	return total.Amount, total.Currency
+}
+
+// a zero id is kept as NULL
+func nullID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// Locks the booking and passes it to change,
+// the changed booking is saved in the same transaction
+// with the type of its room.
+// Returns pkg.ErrBookingConflict if the changed dates of an active
+// booking overlap with another active booking of the room
+// or the type has no free room on one of the nights.
+func (r *BookingsMySQL) Update(ctx context.Context, id int64, change func(booking *pkg.Booking) error) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	b := pkg.Booking{}
+	err = scanBooking(tx.QueryRowContext(ctx, bookingColumns+" WHERE `id` = ? AND `tenant_id` = ? FOR UPDATE", id, pkg.TenantFrom(ctx)), &b)
+	if err == sql.ErrNoRows {
+		return pkg.ErrIDNotFound
+	} else if err != nil {
+		return err
+	}
+
+	err = change(&b)
+	if err != nil {
+		return err
+	}
+
+	if b.Status.Active() {
+		if b.RoomID != 0 {
+			b.TypeID, err = lockRoom(ctx, tx, b.RoomID)
+			if err != nil {
+				return err
+			}
+
+			err = checkConflict(ctx, tx, b.RoomID, b.Start, b.End, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		if b.TypeID != 0 {
+			err = checkType(ctx, tx, b.TypeID, b.RoomID, b.Start, b.End, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	amount, currency := totalArgs(b.Total)
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE `bookings` SET `room_id` = ?, `type_id` = ?, `date_start` = ?, `date_end` = ?, `status` = ?,"+
+			" `total_amount` = ?, `total_currency` = ? WHERE `id` = ?",
+		nullID(b.RoomID),
+		nullID(b.TypeID),
+		b.Start,
+		b.End,
+		b.Status,
+		amount,
+		currency,
 		id,
 	)
 	if err != nil {
-		return pkg.ErrFailedDelete
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// locks the room row until the end of the transaction,
+// so that bookings of the room are checked one by one,
+// archived rooms and rooms of other tenants are not found,
+// returns the type of the room
+func lockRoom(ctx context.Context, tx *sql.Tx, room int64) (int64, error) {
+	var roomType sql.NullInt64
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT `type_id` FROM `room` WHERE `id` = ? AND `tenant_id` = ? AND `deleted_at` IS NULL FOR UPDATE",
+		room,
+		pkg.TenantFrom(ctx),
+	).Scan(&roomType)
+	if err == sql.ErrNoRows {
+		return 0, pkg.ErrNoForeignKey
 	}
 
-	n, err := res.RowsAffected()
+	return roomType.Int64, err
+}
+
+// Locks the type row until the end of the transaction, so that
+// bookings of the type are checked one by one, and checks that
+// the active bookings of the type, except the exclude one, and
+// the booking of the room from start to end can be given rooms
+// of the type, with the same room on all nights of a booking.
+// The type row is locked after the room row, like in every transaction.
+func checkType(ctx context.Context, tx *sql.Tx, roomType, room int64, start, end string, exclude int64) error {
+	var id int64
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT `id` FROM `room_types` WHERE `id` = ? AND `tenant_id` = ? FOR UPDATE",
+		roomType,
+		pkg.TenantFrom(ctx),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return pkg.ErrNoRoomType
+	} else if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		"SELECT `id` FROM `room` WHERE `type_id` = ? AND `tenant_id` = ? AND `deleted_at` IS NULL ORDER BY `id`",
+		roomType,
+		pkg.TenantFrom(ctx),
+	)
 	if err != nil {
-		return pkg.ErrFailedDelete
+		return err
 	}
-	if n == 0 {
-		return pkg.ErrIDNotFound
+	defer rows.Close()
+
+	rooms := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return err
+		}
+		rooms = append(rooms, id)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.QueryContext(
+		ctx,
+		"SELECT `room_id`, `date_start`, `date_end` FROM `bookings`"+
+			"	WHERE `type_id` = ? AND `tenant_id` = ? AND `id` <> ? AND `date_start` < ? AND `date_end` > ?"+
+			"	AND "+activeBookings,
+		roomType,
+		pkg.TenantFrom(ctx),
+		exclude,
+		end,
+		start,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	stays := make([]pkg.Booking, 0)
+	for rows.Next() {
+		var stayRoom sql.NullInt64
+		b := pkg.Booking{}
+		err = rows.Scan(&stayRoom, &b.Start, &b.End)
+		if err != nil {
+			return err
+		}
+		b.RoomID = stayRoom.Int64
+		stays = append(stays, b)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	stays = append(stays, pkg.Booking{RoomID: room, Start: start, End: end})
+	if !pkg.Assignable(rooms, stays, start, end) {
+		return pkg.ErrBookingConflict
+	}
+	return nil
+}
+
+// ER_NO_REFERENCED_ROW_2, the room of the booking does not exist
+const errNoReferencedRow = 1452
+
+// condition of the bookings that occupy the room
+const activeBookings = "`status` IN ('pending', 'confirmed', 'checked_in')"
+
+// checks that the room has no active booking, except the exclude one,
+// whose dates overlap with the range from start to end
+func checkConflict(ctx context.Context, tx *sql.Tx, room int64, start, end string, exclude int64) error {
+	conflict := false
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT `id` FROM `bookings`"+
+			"	WHERE `room_id` = ? AND `id` <> ? AND `date_start` < ? AND `date_end` > ?"+
+			"	AND "+activeBookings+")",
+		room,
+		exclude,
+		end,
+		start,
+	).Scan(&conflict)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return pkg.ErrBookingConflict
 	}
 
 	return nil
 }
 
+func (r *BookingsMySQL) GetByID(ctx context.Context, id int64) (*pkg.Booking, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	b := pkg.Booking{}
+	err := scanBooking(r.db.QueryRowContext(ctx, bookingColumns+" WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx)), &b)
+	if err == sql.ErrNoRows {
+		return nil, pkg.ErrIDNotFound
+	} else if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return &b, nil
+}
+
 // returns bookings by room id
-// sorted by start date
-func (r *BookingsMySQL) Get(id int64) ([]pkg.Booking, error) {
-	rows, err := r.db.Query(
-		"SELECT `id`, `date_start`, `date_end`"+
-			"	FROM `bookings` WHERE `room_id` = ?"+
-			"	ORDER BY `date_start`",
-		id,
-	)
+// sorted by start date,
+// if statuses are passed, only bookings in them
+func (r *BookingsMySQL) Get(ctx context.Context, id int64, status []pkg.BookingStatus) ([]pkg.Booking, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	query := bookingColumns + " WHERE `room_id` = ? AND `tenant_id` = ?"
+	args := []interface{}{id, pkg.TenantFrom(ctx)}
+	if len(status) > 0 {
+		query += " AND `status` IN (?" + strings.Repeat(", ?", len(status)-1) + ")"
+		for _, s := range status {
+			args = append(args, s)
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+"	ORDER BY `date_start`", args...)
 	if err != nil {
-		return nil, pkg.ErrFailedGet
+		return nil, pkg.ErrFailedGet.Wrap(err)
 	}
+	defer rows.Close()
 
 	bookings := make([]pkg.Booking, 0, 1)
 	for rows.Next() {
 		b := pkg.Booking{}
-		rows.Scan(
-			&b.ID,
-			&b.Start,
-			&b.End,
-		)
+		err = scanBooking(rows, &b)
+		if err != nil {
+			return nil, pkg.ErrFailedGet.Wrap(err)
+		}
 		bookings = append(bookings, b)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
 
 	if len(bookings) == 0 {
 		check := true
-		row := r.db.QueryRow(
-			"SELECT EXISTS (SELECT id FROM room WHERE id = ?)",
+		err = r.db.QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT id FROM room WHERE id = ? AND tenant_id = ?)",
 			id,
-		)
-
-		err = row.Scan(&check)
+			pkg.TenantFrom(ctx),
+		).Scan(&check)
 		if err != nil {
-			return nil, pkg.ErrFailedGet
+			return nil, pkg.ErrFailedGet.Wrap(err)
 		}
 		if !check {
 			return nil, pkg.ErrIDNotFound
 		}
 	}
-	return bookings, err
+
+	return bookings, nil
+}
+
+// returns the bookings of the guest sorted by start date
+func (r *BookingsMySQL) GetByGuest(ctx context.Context, guest int64) ([]pkg.Booking, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		bookingColumns+" WHERE `guest_id` = ? AND `tenant_id` = ? ORDER BY `date_start`, `id`",
+		guest,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	defer rows.Close()
+
+	bookings := make([]pkg.Booking, 0)
+	for rows.Next() {
+		b := pkg.Booking{}
+		err = scanBooking(rows, &b)
+		if err != nil {
+			return nil, pkg.ErrFailedGet.Wrap(err)
+		}
+		bookings = append(bookings, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return bookings, nil
 }
@@ -1,11 +1,15 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/Avepa/booking/pkg"
 	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
 )
 
 func TestBookingsMySQL_Add(t *testing.T) {
@@ -28,43 +32,228 @@ func TestBookingsMySQL_Add(t *testing.T) {
 		{
 			name: "OK",
 			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 				result := sqlmock.NewResult(1, 1)
 				mock.ExpectExec("INSERT INTO bookings").
-					WithArgs(3, "2018-02-03", "2018-02-10").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
 					WillReturnResult(result)
+				mock.ExpectCommit()
 			},
 			inputID: 3,
 			inputBookings: &pkg.Booking{
-				Start: "2018-02-03",
-				End:   "2018-02-10",
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+				Total:  &pkg.Money{Amount: 8750, Currency: "USD"},
 			},
 			want: 1,
 		},
 		{
 			name: "Conn Done",
 			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 				mock.ExpectExec("INSERT INTO bookings").
-					WithArgs(3, "2018-02-03", "2018-02-10").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, nil, nil).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			inputID: 3,
 			inputBookings: &pkg.Booking{
-				Start: "2018-02-03",
-				End:   "2018-02-10",
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
 			},
 			wantErr: sql.ErrConnDone,
 		},
 		{
 			name: "No Foreign Key",
 			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			inputID: 3,
+			inputBookings: &pkg.Booking{
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name: "Foreign Key Fails",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 				mock.ExpectExec("INSERT INTO bookings").
-					WithArgs(3, "2018-02-03", "2018-02-10").
-					WillReturnError(pkg.ErrNoForeignKey)
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, nil, nil).
+					WillReturnError(&driver.MySQLError{Number: errNoReferencedRow})
+				mock.ExpectRollback()
 			},
 			inputID: 3,
 			inputBookings: &pkg.Booking{
-				Start: "2018-02-03",
-				End:   "2018-02-10",
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name: "Booking Conflict",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			inputID: 3,
+			inputBookings: &pkg.Booking{
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name: "OK room of a type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(2))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("SELECT `id` FROM `room_types` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT `id` FROM `room` WHERE `type_id` = \\? AND `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `id`").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+				mock.ExpectQuery("SELECT `room_id`, `date_start`, `date_end` FROM `bookings`").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(nil, "2018-02-01", "2018-02-05"))
+				mock.ExpectExec("INSERT INTO bookings").
+					WithArgs(1, 3, 2, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, nil, nil).
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
+			},
+			inputID: 3,
+			inputBookings: &pkg.Booking{
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			want: 5,
+		},
+		{
+			name: "OK without a room",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id` FROM `room_types` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT `id` FROM `room` WHERE `type_id` = (.+) ORDER BY `id`").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("SELECT `room_id`, `date_start`, `date_end` FROM `bookings`").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}))
+				mock.ExpectExec("INSERT INTO bookings").
+					WithArgs(1, nil, 2, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, nil, nil).
+					WillReturnResult(sqlmock.NewResult(6, 1))
+				mock.ExpectCommit()
+			},
+			inputBookings: &pkg.Booking{
+				TypeID: 2,
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			want: 6,
+		},
+		{
+			name: "No free room of the type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id` FROM `room_types` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT `id` FROM `room` WHERE `type_id` = (.+) ORDER BY `id`").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+				mock.ExpectQuery("SELECT `room_id`, `date_start`, `date_end` FROM `bookings`").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(nil, "2018-02-01", "2018-02-05").
+						AddRow(nil, "2018-02-04", "2018-02-12"))
+				mock.ExpectRollback()
+			},
+			inputBookings: &pkg.Booking{
+				TypeID: 2,
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			// one of the rooms is free on every night, but none on all of them
+			name: "Free nights in different rooms of the type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id` FROM `room_types` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT `id` FROM `room` WHERE `type_id` = (.+) ORDER BY `id`").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+				mock.ExpectQuery("SELECT `room_id`, `date_start`, `date_end` FROM `bookings`").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(3, "2018-02-01", "2018-02-05").
+						AddRow(4, "2018-02-06", "2018-02-12"))
+				mock.ExpectRollback()
+			},
+			inputBookings: &pkg.Booking{
+				TypeID: 2,
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name: "No room and no type",
+			mock: func() {},
+			inputBookings: &pkg.Booking{
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
 			},
 			wantErr: pkg.ErrNoForeignKey,
 		},
@@ -73,7 +262,7 @@ func TestBookingsMySQL_Add(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err = r.Add(tt.inputID, tt.inputBookings)
+			err = r.Add(context.Background(), tt.inputID, tt.inputBookings)
 			if err != tt.wantErr {
 				t.Error(err)
 			} else if err == nil {
@@ -81,11 +270,14 @@ func TestBookingsMySQL_Add(t *testing.T) {
 					t.Error("wrong id received")
 				}
 			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
 		})
 	}
 }
 
-func TestBookingsMySQL_Delete(t *testing.T) {
+func TestBookingsMySQL_Get(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
@@ -95,63 +287,142 @@ func TestBookingsMySQL_Delete(t *testing.T) {
 	r := NewBookingsMySQL(db)
 
 	tests := []struct {
-		name    string
-		mock    func()
-		input   int64
-		wantErr error
+		name      string
+		input     int64
+		status    []pkg.BookingStatus
+		mock      func()
+		want      []pkg.Booking
+		wantErr   error
+		wantCause error
 	}{
 		{
-			name: "OK",
+			name:  "OK",
+			input: 1,
 			mock: func() {
-				result := sqlmock.NewResult(0, 1)
-				mock.ExpectExec("DELETE FROM bookings WHERE (.+)").
-					WithArgs(1).WillReturnResult(result)
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow(4, 1, nil, nil, 1, 0, "2018-03-06", "2018-03-08", "checked_out", 3998, "USD").
+					AddRow(10, 1, nil, nil, 1, 0, "2018-10-01", "2018-11-06", "cancelled", nil, nil).
+					AddRow(1, 1, nil, nil, 1, 0, "2019-02-20", "2019-03-06", "confirmed", nil, nil)
+
+				mock.ExpectQuery(
+					"SELECT `id`, `room_id`, `type_id`, `guest_id`, `adults`, `children`, `date_start`, `date_end`, `status`,"+
+						" `total_amount`, `total_currency` FROM `bookings` WHERE `room_id` = (.+)"+
+						"	ORDER BY `date_start`",
+				).WithArgs(1, 1).WillReturnRows(rows)
+			},
+			want: []pkg.Booking{
+				{
+					ID:     4,
+					RoomID: 1,
+					Adults: 1,
+					Start:  "2018-03-06",
+					End:    "2018-03-08",
+					Status: pkg.StatusCheckedOut,
+					Total:  &pkg.Money{Amount: 3998, Currency: "USD"},
+				},
+				{
+					ID:     10,
+					RoomID: 1,
+					Adults: 1,
+					Start:  "2018-10-01",
+					End:    "2018-11-06",
+					Status: pkg.StatusCancelled,
+				},
+				{
+					ID:     1,
+					RoomID: 1,
+					Adults: 1,
+					Start:  "2019-02-20",
+					End:    "2019-03-06",
+					Status: pkg.StatusConfirmed,
+				},
 			},
-			input: 1,
 		},
 		{
-			name: "Failed Delete 1",
+			name:   "OK status",
+			input:  1,
+			status: []pkg.BookingStatus{pkg.StatusPending, pkg.StatusConfirmed},
 			mock: func() {
-				mock.ExpectExec("DELETE FROM bookings WHERE (.+)").
-					WithArgs(2).WillReturnError(sql.ErrConnDone)
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow(1, 1, nil, nil, 1, 0, "2019-02-20", "2019-03-06", "confirmed", nil, nil)
+
+				mock.ExpectQuery(
+					"SELECT (.+) FROM `bookings` WHERE `room_id` = (.+)"+
+						" AND `status` IN \\(\\?, \\?\\)	ORDER BY `date_start`",
+				).WithArgs(1, 1, pkg.StatusPending, pkg.StatusConfirmed).WillReturnRows(rows)
+			},
+			want: []pkg.Booking{
+				{
+					ID:     1,
+					RoomID: 1,
+					Adults: 1,
+					Start:  "2019-02-20",
+					End:    "2019-03-06",
+					Status: pkg.StatusConfirmed,
+				},
 			},
-			input:   2,
-			wantErr: pkg.ErrFailedDelete,
 		},
 		{
-			name: "Failed Delete 2",
+			name:  "Failed",
+			input: 2,
 			mock: func() {
-				result := sqlmock.NewErrorResult(sql.ErrConnDone)
-				mock.ExpectExec("DELETE FROM bookings WHERE (.+)").
-					WithArgs(3).WillReturnResult(result)
+				mock.ExpectQuery(
+					"SELECT `id`, `room_id`, `type_id`, `guest_id`, `adults`, `children`, `date_start`, `date_end`, `status`,"+
+						" `total_amount`, `total_currency` FROM `bookings` WHERE `room_id` = (.+)"+
+						"	ORDER BY `date_start`",
+				).WithArgs(2, 1).WillReturnError(sql.ErrConnDone)
 			},
-			input:   3,
-			wantErr: pkg.ErrFailedDelete,
+			wantErr:   pkg.ErrFailedGet,
+			wantCause: sql.ErrConnDone,
 		},
 		{
-			name: "Not Found",
+			name:  "Bad row",
+			input: 1,
 			mock: func() {
-				result := sqlmock.NewResult(0, 0)
-				mock.ExpectExec("DELETE FROM bookings WHERE (.+)").
-					WithArgs(4).WillReturnResult(result)
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow("four", 1, nil, nil, 1, 0, "2018-03-06", "2018-03-08", "checked_out", nil, nil)
+
+				mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `room_id` = (.+)").
+					WithArgs(1, 1).WillReturnRows(rows)
 			},
-			input:   4,
-			wantErr: pkg.ErrIDNotFound,
+			wantErr: pkg.ErrFailedGet,
+		},
+		{
+			name:  "Rows error",
+			input: 1,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow(4, 1, nil, nil, 1, 0, "2018-03-06", "2018-03-08", "checked_out", nil, nil).
+					RowError(0, sql.ErrConnDone)
+
+				mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `room_id` = (.+)").
+					WithArgs(1, 1).WillReturnRows(rows)
+			},
+			wantErr:   pkg.ErrFailedGet,
+			wantCause: sql.ErrConnDone,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err = r.Delete(tt.input)
-			if err != tt.wantErr {
+			booking, err := r.Get(context.Background(), tt.input, tt.status)
+			if !errors.Is(err, tt.wantErr) {
 				t.Error(err)
+			} else if tt.wantCause != nil && errors.Unwrap(err) != tt.wantCause {
+				t.Error("wrong cause of the error: ", err)
+			} else if err == nil {
+				for i := range tt.want {
+					if !reflect.DeepEqual(tt.want[i], booking[i]) {
+						t.Error("array sorted incorrectly")
+					}
+				}
 			}
 		})
 	}
 }
 
-func TestBookingsMySQL_Get(t *testing.T) {
+func TestBookingsMySQL_Update(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
@@ -160,73 +431,205 @@ func TestBookingsMySQL_Get(t *testing.T) {
 
 	r := NewBookingsMySQL(db)
 
+	selectBooking := func() {
+		rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+			AddRow(5, 3, nil, nil, 1, 0, "2018-02-03", "2018-02-10", "confirmed", nil, nil)
+		mock.ExpectQuery("SELECT `id`, `room_id`, `type_id`, `guest_id`, `adults`, `children`, `date_start`, `date_end`, `status`,"+
+			" `total_amount`, `total_currency` FROM `bookings` WHERE `id` = (.+) FOR UPDATE").
+			WithArgs(5, 1).WillReturnRows(rows)
+	}
+	move := func(b *pkg.Booking) error {
+		b.RoomID = 4
+		b.End = "2018-02-12"
+		b.Total = &pkg.Money{Amount: 9000, Currency: "USD"}
+		return nil
+	}
+
 	tests := []struct {
 		name    string
-		input   int64
+		change  func(b *pkg.Booking) error
 		mock    func()
-		want    []pkg.Booking
 		wantErr error
 	}{
 		{
-			name:  "OK",
-			input: 1,
+			name:   "OK",
+			change: move,
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "date_start", "date_end"}).
-					AddRow(4, "2018-03-06", "2018-03-08").
-					AddRow(10, "2018-10-01", "2018-11-06").
-					AddRow(1, "2019-02-20", "2019-03-06")
-
-				mock.ExpectQuery(
-					"SELECT `id`, `date_start`, `date_end`" +
-						"	FROM `bookings` WHERE `room_id` = (.+)" +
-						"	ORDER BY `date_start`",
-				).WithArgs(1).WillReturnRows(rows)
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(4, 5, "2018-02-12", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec("UPDATE `bookings` SET").
+					WithArgs(4, nil, "2018-02-03", "2018-02-12", pkg.StatusConfirmed, 9000, "USD", 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
-			want: []pkg.Booking{
-				{
-					ID:    4,
-					Start: "2018-03-06",
-					End:   "2018-03-08",
-				},
-				{
-					ID:    10,
-					Start: "2018-10-01",
-					End:   "2018-11-06",
-				},
-				{
-					ID:    1,
-					Start: "2019-02-20",
-					End:   "2019-03-06",
-				},
+		},
+		{
+			name: "OK cancel",
+			change: func(b *pkg.Booking) error {
+				b.Status = pkg.StatusCancelled
+				return nil
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectExec("UPDATE `bookings` SET").
+					WithArgs(3, nil, "2018-02-03", "2018-02-10", pkg.StatusCancelled, nil, nil, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
 		},
 		{
-			name:  "Failed",
-			input: 2,
+			name:   "Not Found",
+			change: move,
 			mock: func() {
-				mock.ExpectQuery(
-					"SELECT `id`, `date_start`, `date_end`" +
-						"	FROM `bookings` WHERE `room_id` = (.+)" +
-						"	ORDER BY `date_start`",
-				).WithArgs(2).WillReturnError(sql.ErrConnDone)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `id`, `room_id`, `type_id`, `guest_id`, `adults`, `children`, `date_start`, `date_end`").
+					WithArgs(5, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name: "Change Rejected",
+			change: func(b *pkg.Booking) error {
+				return pkg.ErrDateIsIncorrect
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:   "No Foreign Key",
+			change: move,
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(4, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name:   "Booking Conflict",
+			change: move,
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(4, 5, "2018-02-12", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name: "OK assign a room of the type",
+			change: func(b *pkg.Booking) error {
+				b.RoomID = 4
+				return nil
+			},
+			mock: func() {
+				mock.ExpectBegin()
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow(5, nil, 2, nil, 1, 0, "2018-02-03", "2018-02-10", "confirmed", nil, nil)
+				mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(5, 1).WillReturnRows(rows)
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(2))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(4, 5, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("SELECT `id` FROM `room_types` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT `id` FROM `room` WHERE `type_id` = (.+) ORDER BY `id`").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+				mock.ExpectQuery("SELECT `room_id`, `date_start`, `date_end` FROM `bookings`").
+					WithArgs(2, 1, 5, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}))
+				mock.ExpectExec("UPDATE `bookings` SET").
+					WithArgs(4, 2, "2018-02-03", "2018-02-10", pkg.StatusConfirmed, nil, nil, 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 			},
-			wantErr: pkg.ErrFailedGet,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			booking, err := r.Get(tt.input)
+			err = r.Update(context.Background(), 5, tt.change)
 			if err != tt.wantErr {
 				t.Error(err)
-			} else if err == nil {
-				for i := range tt.want {
-					if tt.want[i] != booking[i] {
-						t.Error("array sorted incorrectly")
-					}
-				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
 			}
 		})
 	}
 }
+
+func TestBookingsMySQL_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsMySQL(db)
+	columns := []string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}
+
+	mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `id` = (.+)").
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, nil, 2, nil, 2, 0, "2018-02-03", "2018-02-10", "confirmed", 8750, "USD"))
+	got, err := r.GetByID(context.Background(), 5)
+	want := pkg.Booking{
+		ID:     5,
+		TypeID: 2,
+		Adults: 2,
+		Start:  "2018-02-03",
+		End:    "2018-02-10",
+		Status: pkg.StatusConfirmed,
+		Total:  &pkg.Money{Amount: 8750, Currency: "USD"},
+	}
+	if err != nil || !reflect.DeepEqual(*got, want) {
+		t.Error("wrong booking received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `id` = (.+)").
+		WithArgs(6, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(context.Background(), 6)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the booking of another tenant is not found
+	mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `id` = (.+)").
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(pkg.WithTenant(context.Background(), 2), 5)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
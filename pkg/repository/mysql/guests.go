@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type GuestsMySQL struct {
+	db *sql.DB
+}
+
+func NewGuestsMySQL(db *sql.DB) *GuestsMySQL {
+	return &GuestsMySQL{db: db}
+}
+
+// The guest belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the guest id.
+// Returns pkg.ErrGuestExists if the email is taken in the tenant.
+func (r *GuestsMySQL) Add(ctx context.Context, guest *pkg.Guest) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO `guests` (`tenant_id`, `name`, `email`, `phone`, `document_number`) VALUES (?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		guest.Name,
+		guest.Email,
+		guest.Phone,
+		guest.Document,
+	)
+	if err != nil {
+		var myErr *driver.MySQLError
+		if errors.As(err, &myErr) && myErr.Number == errDuplicateEntry {
+			err = pkg.ErrGuestExists
+		}
+		return err
+	}
+
+	guest.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *GuestsMySQL) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx))
+}
+
+func (r *GuestsMySQL) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE `email` = ? AND `tenant_id` = ?", email, pkg.TenantFrom(ctx))
+}
+
+// ER_DUP_ENTRY, the email of the guest is taken
+const errDuplicateEntry = 1062
+
+const guestColumns = "SELECT `id`, `name`, `email`, `phone`, `document_number` FROM `guests`"
+
+// reads one guest, returns pkg.ErrIDNotFound if there is none
+func (r *GuestsMySQL) get(ctx context.Context, query string, args ...interface{}) (*pkg.Guest, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	g := pkg.Guest{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&g.ID, &g.Name, &g.Email, &g.Phone, &g.Document)
+	if err == sql.ErrNoRows {
+		return nil, pkg.ErrIDNotFound
+	} else if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return &g, nil
+}
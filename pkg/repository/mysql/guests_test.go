@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
+)
+
+func TestGuestsMySQL_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewGuestsMySQL(db)
+
+	tests := []struct {
+		name    string
+		input   *pkg.Guest
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "+15550100", Document: "AB123"},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `guests` (.+) VALUES (.+)").
+					WithArgs(1, "Anna", "anna@example.com", "+15550100", "AB123").
+					WillReturnResult(sqlmock.NewResult(2, 1))
+			},
+			want: 2,
+		},
+		{
+			name:  "Guest Exists",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `guests`").
+					WithArgs(1, "Anna", "anna@example.com", "", "").
+					WillReturnError(&driver.MySQLError{Number: errDuplicateEntry})
+			},
+			wantErr: pkg.ErrGuestExists,
+		},
+		{
+			name:  "Conn Done",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `guests`").
+					WithArgs(1, "Anna", "anna@example.com", "", "").
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Add(context.Background(), tt.input)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.input.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGuestsMySQL_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewGuestsMySQL(db)
+	columns := []string{"id", "name", "email", "phone", "document_number"}
+	guest := &pkg.Guest{ID: 2, Name: "Anna", Email: "anna@example.com", Phone: "+15550100"}
+
+	mock.ExpectQuery("SELECT `id`, `name`, `email`, `phone`, `document_number` FROM `guests` WHERE `id` = (.+)").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Anna", "anna@example.com", "+15550100", ""))
+	got, err := r.GetByID(context.Background(), 2)
+	if err != nil || !reflect.DeepEqual(got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `guests` WHERE `email` = (.+)").
+		WithArgs("anna@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Anna", "anna@example.com", "+15550100", ""))
+	got, err = r.GetByEmail(context.Background(), "anna@example.com")
+	if err != nil || !reflect.DeepEqual(got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `guests` WHERE `email` = (.+)").
+		WithArgs("bob@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	if _, err := r.GetByEmail(context.Background(), "bob@example.com"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the guests of another tenant are not found
+	mock.ExpectQuery("SELECT (.+) FROM `guests` WHERE `email` = (.+)").
+		WithArgs("anna@example.com", 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	if _, err := r.GetByEmail(pkg.WithTenant(context.Background(), 2), "anna@example.com"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `guests` WHERE `id` = (.+)").
+		WithArgs(3, 1).
+		WillReturnError(sql.ErrConnDone)
+	if _, err := r.GetByID(context.Background(), 3); !errors.Is(err, pkg.ErrFailedGet) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookingsMySQL_GetByGuest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsMySQL(db)
+
+	rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+		AddRow(4, 1, nil, 2, 1, 0, "2018-03-06", "2018-03-08", "confirmed", 3998, "USD").
+		AddRow(6, 3, nil, 2, 1, 0, "2018-04-01", "2018-04-02", "pending", nil, nil)
+	mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `guest_id` = (.+) ORDER BY `date_start`, `id`").
+		WithArgs(2, 1).WillReturnRows(rows)
+
+	got, err := r.GetByGuest(context.Background(), 2)
+	want := []pkg.Booking{
+		{ID: 4, RoomID: 1, GuestID: 2, Adults: 1, Start: "2018-03-06", End: "2018-03-08", Status: "confirmed", Total: &pkg.Money{Amount: 3998, Currency: "USD"}},
+		{ID: 6, RoomID: 3, GuestID: 2, Adults: 1, Start: "2018-04-01", End: "2018-04-02", Status: "pending"},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Error("wrong bookings received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `bookings` WHERE `guest_id` = (.+)").
+		WithArgs(3, 1).WillReturnError(sql.ErrConnDone)
+	if _, err := r.GetByGuest(context.Background(), 3); !errors.Is(err, pkg.ErrFailedGet) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
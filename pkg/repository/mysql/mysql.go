@@ -3,6 +3,7 @@ package mysql
 import (
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -13,11 +14,19 @@ type Config struct {
 	Username string
 	Password string
 	DBName   string
+	// lets one query hold several statements,
+	// only the migrations need it
+	MultiStatements bool
 }
 
 func NewMySqlDB(cfg *Config) (*sql.DB, error) {
-	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName))
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+		cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+	if cfg.MultiStatements {
+		dsn += "?multiStatements=true"
+	}
+
+	db, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -29,3 +38,10 @@ func NewMySqlDB(cfg *Config) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// deadlines of single operations, the request
+// context can only make them shorter
+const (
+	readTimeout  = 3 * time.Second
+	writeTimeout = 5 * time.Second
+)
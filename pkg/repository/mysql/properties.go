@@ -0,0 +1,128 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type PropertiesMySQL struct {
+	db *sql.DB
+}
+
+func NewPropertiesMySQL(db *sql.DB) *PropertiesMySQL {
+	return &PropertiesMySQL{db: db}
+}
+
+// The property belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the property id.
+func (r *PropertiesMySQL) Add(ctx context.Context, p *pkg.Property) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO `properties` (`tenant_id`, `name`, `address`, `timezone`, `currency`) VALUES (?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		p.Name,
+		p.Address,
+		p.Timezone,
+		p.Currency,
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	p.ID, err = res.LastInsertId()
+	return err
+}
+
+// Replaces the property, its rooms and types are not changed.
+func (r *PropertiesMySQL) Update(ctx context.Context, id int64, p *pkg.Property) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE `properties` SET `name` = ?, `address` = ?, `timezone` = ?, `currency` = ? WHERE `id` = ? AND `tenant_id` = ?",
+		p.Name,
+		p.Address,
+		p.Timezone,
+		p.Currency,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	// MySQL does not count rows whose values have not changed
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		_, err = r.GetByID(ctx, id)
+		return err
+	}
+
+	return nil
+}
+
+func (r *PropertiesMySQL) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	properties, err := r.get(ctx, propertyColumns+" WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(properties) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &properties[0], nil
+}
+
+// returns the properties of the tenant sorted by id
+func (r *PropertiesMySQL) List(ctx context.Context) ([]pkg.Property, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	properties, err := r.get(ctx, propertyColumns+" WHERE `tenant_id` = ? ORDER BY `id`", pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return properties, nil
+}
+
+const propertyColumns = "SELECT `id`, `name`, `address`, `timezone`, `currency` FROM `properties`"
+
+// reads the rows of propertyColumns
+func (r *PropertiesMySQL) get(ctx context.Context, query string, args ...interface{}) ([]pkg.Property, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]pkg.Property, 0)
+	for rows.Next() {
+		p := pkg.Property{}
+		err = rows.Scan(&p.ID, &p.Name, &p.Address, &p.Timezone, &p.Currency)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
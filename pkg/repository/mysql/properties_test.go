@@ -0,0 +1,161 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPropertiesMySQL_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesMySQL(db)
+	seaside := pkg.Property{
+		Name:     "Seaside",
+		Address:  "1 Beach Road",
+		Timezone: "Europe/Lisbon",
+		Currency: "EUR",
+	}
+
+	mock.ExpectExec("INSERT INTO `properties` (.+) VALUES (.+)").
+		WithArgs(1, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR").
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	err = r.Add(context.Background(), &seaside)
+	if err != nil || seaside.ID != 2 {
+		t.Error("wrong property saved: ", seaside, err)
+	}
+
+	mock.ExpectExec("INSERT INTO `properties`").WillReturnError(sql.ErrConnDone)
+	err = r.Add(context.Background(), &pkg.Property{Name: "Downtown"})
+	if !errors.Is(err, pkg.ErrFailedSave) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertiesMySQL_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesMySQL(db)
+	seaside := &pkg.Property{Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "EUR"}
+	columns := []string{"id", "name", "address", "timezone", "currency"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE `properties` SET (.+) WHERE `id` = (.+)").
+					WithArgs("Seaside", "", "Europe/Lisbon", "EUR", 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "OK nothing changed",
+			mock: func() {
+				mock.ExpectExec("UPDATE `properties`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM `properties` WHERE `id` = (.+)").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Seaside", "", "Europe/Lisbon", "EUR"))
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE `properties`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM `properties` WHERE `id` = (.+)").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 2, seaside)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestPropertiesMySQL_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesMySQL(db)
+	columns := []string{"id", "name", "address", "timezone", "currency"}
+	properties := []pkg.Property{
+		{ID: 1, Name: "Default", Timezone: "UTC", Currency: "USD"},
+		{ID: 2, Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"},
+	}
+
+	mock.ExpectQuery("SELECT `id`, `name`, `address`, `timezone`, `currency` FROM `properties` WHERE `id` = (.+)").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR"))
+	got, err := r.GetByID(context.Background(), 2)
+	if err != nil || *got != properties[1] {
+		t.Error("wrong property received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `properties` WHERE `id` = (.+)").
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(context.Background(), 3)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the property of another tenant is not found
+	mock.ExpectQuery("SELECT (.+) FROM `properties` WHERE `id` = (.+)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(pkg.WithTenant(context.Background(), 2), 1)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `properties` WHERE `tenant_id` = (.+) ORDER BY `id`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Default", "", "UTC", "USD").
+			AddRow(2, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR"))
+	list, err := r.List(context.Background())
+	if err != nil || !reflect.DeepEqual(list, properties) {
+		t.Error("wrong properties received: ", list, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
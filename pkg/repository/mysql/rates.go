@@ -0,0 +1,206 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RatePlansMySQL struct {
+	db *sql.DB
+}
+
+func NewRatePlansMySQL(db *sql.DB) *RatePlansMySQL {
+	return &RatePlansMySQL{db: db}
+}
+
+// The plan belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the plan id.
+// Returns pkg.ErrNoForeignKey if the room does not exist.
+func (r *RatePlansMySQL) Add(ctx context.Context, plan *pkg.RatePlan) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO `rate_plans` (`tenant_id`, `room_id`, `name`, `date_start`, `date_end`, `weekdays`,"+
+			" `price_amount`, `price_currency`, `priority`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		plan.RoomID,
+		plan.Name,
+		nullDate(plan.Start),
+		nullDate(plan.End),
+		plan.Weekdays,
+		plan.Price.Amount,
+		plan.Price.Currency,
+		plan.Priority,
+	)
+	if err != nil {
+		var myErr *driver.MySQLError
+		if errors.As(err, &myErr) && myErr.Number == errNoReferencedRow {
+			err = pkg.ErrNoForeignKey
+		}
+		return err
+	}
+
+	plan.ID, err = res.LastInsertId()
+	return err
+}
+
+// Replaces the plan, its room is not changed.
+func (r *RatePlansMySQL) Update(ctx context.Context, id int64, plan *pkg.RatePlan) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE `rate_plans` SET `name` = ?, `date_start` = ?, `date_end` = ?, `weekdays` = ?,"+
+			" `price_amount` = ?, `price_currency` = ?, `priority` = ? WHERE `id` = ? AND `tenant_id` = ?",
+		plan.Name,
+		nullDate(plan.Start),
+		nullDate(plan.End),
+		plan.Weekdays,
+		plan.Price.Amount,
+		plan.Price.Currency,
+		plan.Priority,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return err
+	}
+
+	// MySQL counts changed rows, so the plan is
+	// looked up when nothing was changed
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		_, err = r.GetByID(ctx, id)
+		return err
+	}
+
+	return nil
+}
+
+// Deletes the plan, the totals of the bookings
+// priced with it do not change.
+func (r *RatePlansMySQL) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM `rate_plans` WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+func (r *RatePlansMySQL) GetByID(ctx context.Context, id int64) (*pkg.RatePlan, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	plans, err := r.get(ctx, rateColumns+" WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(plans) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &plans[0], nil
+}
+
+// returns the plans of the rooms of the tenant sorted by room and id
+func (r *RatePlansMySQL) Get(ctx context.Context, rooms []int64) ([]pkg.RatePlan, error) {
+	if len(rooms) == 0 {
+		return []pkg.RatePlan{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	args := make([]interface{}, len(rooms), len(rooms)+1)
+	for i, id := range rooms {
+		args[i] = id
+	}
+	args = append(args, pkg.TenantFrom(ctx))
+
+	plans, err := r.get(
+		ctx,
+		rateColumns+" WHERE `room_id` IN (?"+strings.Repeat(", ?", len(rooms)-1)+") AND `tenant_id` = ?"+
+			" ORDER BY `room_id`, `id`",
+		args...,
+	)
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return plans, nil
+}
+
+const rateColumns = "SELECT `id`, `room_id`, `name`, `date_start`, `date_end`, `weekdays`," +
+	" `price_amount`, `price_currency`, `priority` FROM `rate_plans`"
+
+// reads the rows of rateColumns, the dates of a plan
+// without a range are NULL
+func (r *RatePlansMySQL) get(ctx context.Context, query string, args ...interface{}) ([]pkg.RatePlan, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]pkg.RatePlan, 0)
+	for rows.Next() {
+		p := pkg.RatePlan{}
+		var start, end sql.NullString
+		err = rows.Scan(
+			&p.ID,
+			&p.RoomID,
+			&p.Name,
+			&start,
+			&end,
+			&p.Weekdays,
+			&p.Price.Amount,
+			&p.Price.Currency,
+			&p.Priority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		p.Start, p.End = start.String, end.String
+		plans = append(plans, p)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// an empty date is kept as NULL
+func nullDate(date string) interface{} {
+	if date == "" {
+		return nil
+	}
+	return date
+}
@@ -0,0 +1,269 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// columns of rateColumns
+var rateColumnNames = []string{"id", "room_id", "name", "date_start", "date_end", "weekdays",
+	"price_amount", "price_currency", "priority"}
+
+func TestRatePlansMySQL_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansMySQL(db)
+
+	tests := []struct {
+		name    string
+		input   *pkg.RatePlan
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			input: &pkg.RatePlan{
+				RoomID:   1,
+				Name:     "Weekend",
+				Weekdays: 65,
+				Price:    pkg.Money{Amount: 1500, Currency: "USD"},
+			},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `rate_plans` (.+) VALUES (.+)").
+					WithArgs(1, 1, "Weekend", nil, nil, 65, 1500, "USD", 0).
+					WillReturnResult(sqlmock.NewResult(4, 1))
+			},
+			want: 4,
+		},
+		{
+			name: "No Foreign Key",
+			input: &pkg.RatePlan{
+				RoomID: 7,
+				Name:   "Summer",
+				Start:  "2018-06-01",
+				End:    "2018-09-01",
+				Price:  pkg.Money{Amount: 1500, Currency: "USD"},
+			},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `rate_plans`").
+					WithArgs(1, 7, "Summer", "2018-06-01", "2018-09-01", 0, 1500, "USD", 0).
+					WillReturnError(&driver.MySQLError{Number: errNoReferencedRow})
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name:  "Conn Done",
+			input: &pkg.RatePlan{RoomID: 1, Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			mock: func() {
+				mock.ExpectExec("INSERT INTO `rate_plans`").
+					WithArgs(1, 1, "", nil, nil, 0, 1500, "USD", 0).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Add(context.Background(), tt.input)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.input.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansMySQL_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansMySQL(db)
+	plan := &pkg.RatePlan{Name: "Summer", Start: "2018-06-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}, Priority: 2}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE `rate_plans` SET (.+) WHERE `id` = (.+)").
+					WithArgs("Summer", "2018-06-01", nil, 0, 1500, "USD", 2, 4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "OK unchanged",
+			mock: func() {
+				mock.ExpectExec("UPDATE `rate_plans`").
+					WithArgs("Summer", "2018-06-01", nil, 0, 1500, "USD", 2, 4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM `rate_plans` WHERE `id` = (.+)").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(rateColumnNames).
+						AddRow(4, 1, "Summer", "2018-06-01", nil, 0, 1500, "USD", 2))
+			},
+		},
+		{
+			name: "ID Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE `rate_plans`").
+					WithArgs("Summer", "2018-06-01", nil, 0, 1500, "USD", 2, 4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT (.+) FROM `rate_plans`").
+					WithArgs(4, 1).
+					WillReturnRows(sqlmock.NewRows(rateColumnNames))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 4, plan)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansMySQL_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansMySQL(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM `rate_plans` WHERE `id` = (.+)").
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "ID Not Found",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM `rate_plans`").
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name: "Failed Delete",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM `rate_plans`").
+					WithArgs(4, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Delete(context.Background(), 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansMySQL_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansMySQL(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []pkg.RatePlan
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(rateColumnNames).
+					AddRow(2, 1, "Weekend", nil, nil, 65, 600, "USD", 1).
+					AddRow(1, 2, "Summer", "2018-06-01", "2018-09-01", 0, 1500, "USD", 0)
+				mock.ExpectQuery("SELECT (.+) FROM `rate_plans` WHERE `room_id` IN \\(\\?, \\?\\) AND `tenant_id` = \\? ORDER BY `room_id`, `id`").
+					WithArgs(2, 1, 1).
+					WillReturnRows(rows)
+			},
+			want: []pkg.RatePlan{
+				{ID: 2, RoomID: 1, Name: "Weekend", Weekdays: 65, Price: pkg.Money{Amount: 600, Currency: "USD"}, Priority: 1},
+				{ID: 1, RoomID: 2, Name: "Summer", Start: "2018-06-01", End: "2018-09-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			},
+		},
+		{
+			name: "Failed Get",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM `rate_plans`").
+					WithArgs(2, 1, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			plans, err := r.Get(context.Background(), []int64{2, 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(plans, tt.want) {
+				t.Error("wrong plans received: ", plans)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
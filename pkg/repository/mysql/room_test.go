@@ -1,8 +1,11 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/Avepa/booking/pkg"
 	"github.com/DATA-DOG/go-sqlmock"
@@ -27,38 +30,40 @@ func TestRoomMySQL_Add(t *testing.T) {
 		{
 			name: "OK_1",
 			input: &pkg.Room{
+				PropertyID:  1,
 				Description: "GOOD",
-				Price:       54.54,
+				Price:       pkg.Money{Amount: 5454, Currency: "USD"},
 			},
 			mock: func() {
 				result := sqlmock.NewResult(1, 1)
 				mock.ExpectExec("INSERT INTO room").
-					WithArgs("GOOD", 54.54).WillReturnResult(result)
+					WithArgs(1, 1, "GOOD", 5454, "USD", 0, 0, nil).WillReturnResult(result)
 			},
 			want: 1,
 		},
 		{
 			name: "OK_2",
 			input: &pkg.Room{
-				Price: 127,
+				PropertyID: 2,
+				Price:      pkg.Money{Amount: 12700, Currency: "USD"},
 			},
 			mock: func() {
 				result := sqlmock.NewResult(2, 1)
 				mock.ExpectExec("INSERT INTO room").
-					WithArgs("", 127.0).WillReturnResult(result)
+					WithArgs(1, 2, "", 12700, "USD", 0, 0, nil).WillReturnResult(result)
 			},
 			want: 2,
 		},
 		{
 			name: "Failed Save",
 			input: &pkg.Room{
+				PropertyID:  1,
 				Description: "",
-				Price:       240.4,
+				Price:       pkg.Money{Amount: 24040, Currency: "USD"},
 			},
 			mock: func() {
-				result := sqlmock.NewResult(3, 1)
 				mock.ExpectExec("INSERT INTO room").
-					WithArgs("", 240).WillReturnResult(result)
+					WithArgs(1, 1, "", 24040, "USD", 0, 0, nil).WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: pkg.ErrFailedSave,
 		},
@@ -67,8 +72,8 @@ func TestRoomMySQL_Add(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err = r.Add(tt.input)
-			if err != tt.wantErr {
+			err = r.Add(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
 				t.Error(err, tt.wantErr)
 			}
 		})
@@ -84,9 +89,24 @@ func TestRoomMySQL_Delete(t *testing.T) {
 
 	r := NewRoomMySQL(db)
 
+	lock := func(id int64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) AND `deleted_at` IS NULL FOR UPDATE").
+			WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+	}
+	future := func(id int64, exists bool) {
+		mock.ExpectQuery("SELECT EXISTS (.+) `date_end` > (.+)").
+			WithArgs(id, "2018-02-01").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+	}
+	checkedIn := func(id int64, exists bool) {
+		mock.ExpectQuery("SELECT EXISTS (.+) `status` = (.+)").
+			WithArgs(id, "checked_in").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+	}
+
 	tests := []struct {
 		name    string
 		input   int64
+		force   bool
 		mock    func()
 		wantErr error
 	}{
@@ -94,54 +114,172 @@ func TestRoomMySQL_Delete(t *testing.T) {
 			name:  "OK",
 			input: 1,
 			mock: func() {
-				result := sqlmock.NewResult(0, 1)
-				mock.ExpectExec("DELETE FROM `room` WHERE (.+)").
-					WithArgs(1).WillReturnResult(result)
+				lock(1)
+				checkedIn(1, false)
+				future(1, false)
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NOW\\(\\) WHERE `id` = (.+)").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "OK force",
+			input: 1,
+			force: true,
+			mock: func() {
+				lock(1)
+				checkedIn(1, false)
+				future(1, true)
+				mock.ExpectExec("UPDATE `bookings` SET `status` = (.+)").
+					WithArgs("cancelled", 1, "2018-02-01", "pending", "confirmed").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NOW\\(\\) WHERE `id` = (.+)").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Has guests",
+			input: 1,
+			force: true,
+			mock: func() {
+				lock(1)
+				checkedIn(1, true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrRoomHasGuests,
+		},
+		{
+			name:  "Has guests leaving today",
+			input: 1,
+			mock: func() {
+				lock(1)
+				checkedIn(1, true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrRoomHasGuests,
+		},
+		{
+			name:  "Has bookings",
+			input: 1,
+			mock: func() {
+				lock(1)
+				checkedIn(1, false)
+				future(1, true)
+				mock.ExpectRollback()
 			},
+			wantErr: pkg.ErrRoomHasBookings,
 		},
 		{
 			name:  "Not Found",
 			input: 2,
 			mock: func() {
-				result := sqlmock.NewResult(0, 0)
-				mock.ExpectExec("DELETE FROM `room` WHERE (.+)").
-					WithArgs(2).WillReturnResult(result)
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT `type_id` FROM `room` WHERE `id` = (.+) FOR UPDATE").
+					WithArgs(2, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
 			},
 			wantErr: pkg.ErrIDNotFound,
 		},
 		{
-			name:  "Failed Delete 1",
+			name:  "Failed Delete",
 			input: 3,
 			mock: func() {
-				mock.ExpectExec("DELETE FROM `room` WHERE (.+)").
+				lock(3)
+				checkedIn(3, false)
+				future(3, false)
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NOW\\(\\) WHERE `id` = (.+)").
 					WithArgs(3).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			wantErr: pkg.ErrFailedDelete,
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Delete(context.Background(), tt.input, tt.force, "2018-02-01")
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomMySQL_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	tests := []struct {
+		name    string
+		input   int64
+		mock    func()
+		wantErr error
+	}{
 		{
-			name:  "Failed Delete 2",
-			input: 4,
+			name:  "OK",
+			input: 1,
 			mock: func() {
-				result := sqlmock.NewErrorResult(sql.ErrConnDone)
-				mock.ExpectExec("DELETE FROM `room` WHERE (.+)").
-					WithArgs(4).WillReturnResult(result)
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NULL WHERE `id` = (.+)").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			wantErr: pkg.ErrFailedDelete,
+		},
+		{
+			name:  "OK not archived",
+			input: 1,
+			mock: func() {
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NULL WHERE `id` = (.+)").
+					WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT EXISTS").WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:  "Not Found",
+			input: 2,
+			mock: func() {
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NULL WHERE `id` = (.+)").
+					WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT EXISTS").WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Failed Save",
+			input: 3,
+			mock: func() {
+				mock.ExpectExec("UPDATE `room` SET `deleted_at` = NULL WHERE `id` = (.+)").
+					WithArgs(3, 1).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedSave,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err = r.Delete(tt.input)
-			if err != tt.wantErr {
+			err = r.Restore(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Error(err)
 			}
 		})
 	}
 }
 
-func TestRoomMySQL_Get(t *testing.T) {
+func TestRoomMySQL_List(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatal(err)
@@ -150,199 +288,590 @@ func TestRoomMySQL_Get(t *testing.T) {
 
 	r := NewRoomMySQL(db)
 
+	var minPrice, maxPrice int64 = 350, 600
+
 	tests := []struct {
 		name    string
-		sort    func() ([]pkg.Room, error)
+		query   pkg.RoomQuery
 		mock    func()
 		want    []pkg.Room
 		wantErr error
+		// rows.Scan errors are not sentinels
+		scanErr bool
 	}{
 		{
-			name: "OK Func GetByDate()",
-			sort: r.GetByDate,
+			name:  "OK date",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "date", "price", "description"}).
-					AddRow(1, "2018.01.03", 3.54, "Good room").
-					AddRow(2, "2018.03.06", 5.03, "VIP ROOM").
-					AddRow(3, "2019.10.03", 10, "")
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1).
+					AddRow(2, "2018.03.06", 503, "USD", "VIP ROOM", 2, 0, nil, 1).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1)
 
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY date").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `date`").
 					WillReturnRows(rows)
 			},
 			want: []pkg.Room{
 				{
 					ID:          1,
-					Price:       3.54,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
 					Date:        "2018.01.03",
 					Description: "Good room",
+					MaxAdults:   2,
 				},
 				{
 					ID:          2,
-					Price:       5.03,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
 					Date:        "2018.03.06",
 					Description: "VIP ROOM",
+					MaxAdults:   2,
 				},
 				{
 					ID:          3,
-					Price:       10.0,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2019.10.03",
 					Description: "",
+					MaxAdults:   2,
 				},
 			},
 		},
 		{
-			name: "Conn done Func GetByDate()",
-			sort: r.GetByDate,
+			name:  "Conn done date",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate},
 			mock: func() {
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY date").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `date`").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
 		},
 		{
-			name: "OK Func GetByDateDESC()",
-			sort: r.GetByDateDESC,
+			name:  "OK date desc",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "date", "price", "description"}).
-					AddRow(3, "2019.10.03", 10, "").
-					AddRow(2, "2018.03.06", 5.03, "VIP ROOM").
-					AddRow(1, "2018.01.03", 3.54, "Good room")
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1).
+					AddRow(2, "2018.03.06", 503, "USD", "VIP ROOM", 2, 0, nil, 1).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1)
 
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY date DESC").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `date` DESC").
 					WillReturnRows(rows)
 			},
 			want: []pkg.Room{
 				{
 					ID:          3,
-					Price:       10.0,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2019.10.03",
 					Description: "",
+					MaxAdults:   2,
 				},
 				{
 					ID:          2,
-					Price:       5.03,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
 					Date:        "2018.03.06",
 					Description: "VIP ROOM",
+					MaxAdults:   2,
 				},
 				{
 					ID:          1,
-					Price:       3.54,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
 					Date:        "2018.01.03",
 					Description: "Good room",
+					MaxAdults:   2,
 				},
 			},
 		},
 		{
-			name: "Conn done Func GetByDate()",
-			sort: r.GetByDateDESC,
+			name:  "Conn done date",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true},
 			mock: func() {
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY date DESC").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `date` DESC").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
 		},
 
 		{
-			name: "OK Func GetByPrice()",
-			sort: r.GetByPrice,
+			name:  "OK price",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "date", "price", "description"}).
-					AddRow(1, "2018.01.03", 3.54, "Good room").
-					AddRow(2, "2018.03.06", 5.03, "VIP ROOM").
-					AddRow(3, "2019.10.03", 10, "")
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1).
+					AddRow(2, "2018.03.06", 503, "USD", "VIP ROOM", 2, 0, nil, 1).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1)
 
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY price").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `price_currency`, `price_amount`").
 					WillReturnRows(rows)
 			},
 			want: []pkg.Room{
 				{
 					ID:          1,
-					Price:       3.54,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
 					Date:        "2018.01.03",
 					Description: "Good room",
+					MaxAdults:   2,
 				},
 				{
 					ID:          2,
-					Price:       5.03,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
 					Date:        "2018.03.06",
 					Description: "VIP ROOM",
+					MaxAdults:   2,
 				},
 				{
 					ID:          3,
-					Price:       10.0,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2019.10.03",
 					Description: "",
+					MaxAdults:   2,
 				},
 			},
 		},
 		{
-			name: "Conn done Func GetByPrice()",
-			sort: r.GetByPrice,
+			name:  "Conn done price",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice},
 			mock: func() {
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY price").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `price_currency`, `price_amount`").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
 		},
 		{
-			name: "OK Func GetByPriceDESC()",
-			sort: r.GetByPriceDESC,
+			name:  "OK price desc",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true},
 			mock: func() {
-				rows := sqlmock.NewRows([]string{"id", "date", "price", "description"}).
-					AddRow(3, "2019.10.03", 10, "").
-					AddRow(2, "2018.03.06", 5.03, "VIP ROOM").
-					AddRow(1, "2018.01.03", 3.54, "Good room")
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1).
+					AddRow(2, "2018.03.06", 503, "USD", "VIP ROOM", 2, 0, nil, 1).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1)
 
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY price DESC").
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `price_currency`, `price_amount` DESC").
 					WillReturnRows(rows)
 			},
 			want: []pkg.Room{
 				{
 					ID:          3,
-					Price:       10.0,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2019.10.03",
 					Description: "",
+					MaxAdults:   2,
 				},
 				{
 					ID:          2,
-					Price:       5.03,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
 					Date:        "2018.03.06",
 					Description: "VIP ROOM",
+					MaxAdults:   2,
 				},
 				{
 					ID:          1,
-					Price:       3.54,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
+					Date:        "2018.01.03",
+					Description: "Good room",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:  "Conn done price desc",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true},
+			mock: func() {
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room" +
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL ORDER BY `price_currency`, `price_amount` DESC").
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+		{
+			name: "OK price filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByPrice,
+				RoomFilter: pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, MaxPrice: &maxPrice, Limit: 2, Offset: 4},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1).
+					AddRow(2, "2018.03.06", 503, "USD", "VIP ROOM", 2, 0, nil, 1)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND `price_currency` = (.+)"+
+					" AND `price_amount` >= (.+) AND `price_amount` <= (.+)"+
+					" ORDER BY `price_currency`, `price_amount`, `id` LIMIT (.+) OFFSET (.+)").
+					WithArgs(1, "USD", 350, 600, 2, 4).
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          1,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
+					Date:        "2018.01.03",
+					Description: "Good room",
+					MaxAdults:   2,
+				},
+				{
+					ID:          2,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
+					Date:        "2018.03.06",
+					Description: "VIP ROOM",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:    "Sort not valid",
+			query:   pkg.RoomQuery{Sort: "description"},
+			mock:    func() {},
+			wantErr: pkg.ErrSortNotValid,
+		},
+		{
+			name:  "Scan error",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow("abc", "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room").
+					WillReturnRows(rows)
+			},
+			scanErr: true,
+		},
+		{
+			name:  "Rows error",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1).
+					RowError(0, sql.ErrConnDone)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room").
+					WillReturnRows(rows)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			if tt.query.Limit == 0 {
+				tt.query.Limit = 20
+			}
+			room, err := r.List(context.Background(), tt.query)
+			if tt.scanErr {
+				if err == nil {
+					t.Error("scan error not returned")
+				}
+				return
+			}
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil {
+				if len(room) != len(tt.want) {
+					t.Fatal("wrong number of rooms received")
+				}
+				for i := range tt.want {
+					if tt.want[i] != room[i] {
+						t.Error("array sorted incorrectly")
+					}
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomMySQL_ListCanceled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+		AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1)
+	mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room").
+		WillDelayFor(time.Second).
+		WillReturnRows(rows)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = r.List(ctx, pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}})
+	if err == nil {
+		t.Error("query was not stopped by the context")
+	}
+}
+
+func TestRoomMySQL_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	var minPrice int64 = 350
+
+	tests := []struct {
+		name    string
+		filter  pkg.RoomFilter
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM room WHERE `tenant_id` = \\? AND `deleted_at` IS NULL$").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+			},
+			want: 42,
+		},
+		{
+			name:   "OK price filter",
+			filter: pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, Limit: 20, Offset: 40},
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM room WHERE `tenant_id` = \\? AND `deleted_at` IS NULL"+
+					" AND `price_currency` = (.+) AND `price_amount` >= (.+)$").
+					WithArgs(1, "USD", 350).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+			},
+			want: 7,
+		},
+		{
+			name:   "OK property filter",
+			filter: pkg.RoomFilter{PropertyID: 2, Adults: 3},
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM room WHERE `tenant_id` = \\? AND `deleted_at` IS NULL"+
+					" AND `max_adults` >= (.+) AND `max_adults` \\+ `max_children` >= (.+) AND `property_id` = (.+)$").
+					WithArgs(1, 3, 3, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+			},
+			want: 4,
+		},
+		{
+			name: "Conn done",
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT").WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			total, err := r.Count(context.Background(), &tt.filter)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+			if total != tt.want {
+				t.Error("wrong total received: ", total)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomMySQL_GetAvailable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	tests := []struct {
+		name    string
+		query   pkg.RoomQuery
+		mock    func()
+		want    []pkg.Room
+		wantErr error
+	}{
+		{
+			name:  "OK price",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(1, "2018.01.03", 354, "USD", "Good room", 2, 0, nil, 1).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND NOT EXISTS (.+) ORDER BY `price_currency`, `price_amount`, `id`$").
+					WithArgs(1, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          1,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
 					Date:        "2018.01.03",
 					Description: "Good room",
+					MaxAdults:   2,
+				},
+				{
+					ID:          3,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
+					Date:        "2019.10.03",
+					Description: "",
+					MaxAdults:   2,
 				},
 			},
 		},
 		{
-			name: "Conn done Func GetByPriceDESC()",
-			sort: r.GetByPriceDESC,
+			name:  "OK date desc",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true},
 			mock: func() {
-				mock.ExpectQuery("SELECT `id`, `date`, `price`, `description` FROM room" +
-					" ORDER BY price DESC").
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 0, nil, 1)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND NOT EXISTS (.+) ORDER BY `date` DESC, `id`$").
+					WithArgs(1, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          3,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
+					Date:        "2019.10.03",
+					Description: "",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:  "OK guests",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{Adults: 2, Children: 1}},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(3, "2019.10.03", 1000, "USD", "", 2, 1, nil, 1)
+
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND `max_adults` >= \\? AND `max_adults` \\+ `max_children` >= \\? AND NOT EXISTS (.+) ORDER BY `price_currency`, `price_amount`, `id`$").
+					WithArgs(1, 2, 3, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          3,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
+					Date:        "2019.10.03",
+					MaxAdults:   2,
+					MaxChildren: 1,
+				},
+			},
+		},
+		{
+			name:  "OK type",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{TypeID: 2}},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(3, "2019.10.03", 1000, "USD", "Double", 2, 0, 2, 1)
+
+				mock.ExpectQuery("SELECT (.+) FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND `type_id` = \\? AND NOT EXISTS (.+)"+
+					" AND \\(`room`.`type_id` IS NULL OR \\(SELECT COUNT\\(\\*\\) FROM `bookings` (.+) `bookings`.`room_id` IS NULL (.+)"+
+					" ORDER BY `price_currency`, `price_amount`, `id`$").
+					WithArgs(1, 2, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          3,
+					PropertyID:  1,
+					TypeID:      2,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
+					Date:        "2019.10.03",
+					Description: "Double",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:  "OK property",
+			query: pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{PropertyID: 2}},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(5, "2019.10.03", 9000, "EUR", "Sea view", 2, 0, nil, 2)
+
+				mock.ExpectQuery("SELECT (.+) FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND `property_id` = \\? AND NOT EXISTS (.+) ORDER BY `price_currency`, `price_amount`, `id`$").
+					WithArgs(1, 2, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          5,
+					PropertyID:  2,
+					Price:       pkg.Money{Amount: 9000, Currency: "EUR"},
+					Date:        "2019.10.03",
+					Description: "Sea view",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name:  "Conn done",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate},
+			mock: func() {
+				mock.ExpectQuery("SELECT `id`, `date`, `price_amount`, `price_currency`, `description`, `max_adults`, `max_children`, `type_id`, `property_id` FROM room"+
+					" WHERE `tenant_id` = \\? AND `deleted_at` IS NULL AND NOT EXISTS (.+) ORDER BY `date`, `id`$").
+					WithArgs(1, "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03", "2018-02-10", "2018-02-03").
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantErr: sql.ErrConnDone,
 		},
+		{
+			name:    "Sort not valid",
+			query:   pkg.RoomQuery{Sort: "abc"},
+			mock:    func() {},
+			wantErr: pkg.ErrSortNotValid,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			room, err := tt.sort()
+			room, err := r.GetAvailable(context.Background(), "2018-02-03", "2018-02-10", tt.query)
 			if err != tt.wantErr {
 				t.Error(err)
 			} else if err == nil {
+				if len(room) != len(tt.want) {
+					t.Fatal("wrong number of rooms received")
+				}
 				for i := range tt.want {
 					if tt.want[i] != room[i] {
 						t.Error("array sorted incorrectly")
@@ -352,3 +881,207 @@ func TestRoomMySQL_Get(t *testing.T) {
 		})
 	}
 }
+
+func TestRoomMySQL_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	description := "VIP ROOM"
+	price := pkg.Money{Amount: 1250, Currency: "USD"}
+
+	tests := []struct {
+		name    string
+		input   *pkg.RoomUpdate
+		mock    func()
+		wantErr error
+	}{
+		{
+			name:  "OK all fields",
+			input: &pkg.RoomUpdate{Description: &description, Price: &price},
+			mock: func() {
+				result := sqlmock.NewResult(0, 1)
+				mock.ExpectExec("UPDATE `room` SET `description` = (.+), `price_amount` = (.+), `price_currency` = (.+) WHERE `id` = (.+)").
+					WithArgs("VIP ROOM", 1250, "USD", 1, 1).WillReturnResult(result)
+			},
+		},
+		{
+			name:  "OK price",
+			input: &pkg.RoomUpdate{Price: &price},
+			mock: func() {
+				result := sqlmock.NewResult(0, 1)
+				mock.ExpectExec("UPDATE `room` SET `price_amount` = (.+), `price_currency` = (.+) WHERE `id` = (.+)").
+					WithArgs(1250, "USD", 1, 1).WillReturnResult(result)
+			},
+		},
+		{
+			name:  "OK not changed",
+			input: &pkg.RoomUpdate{Price: &price},
+			mock: func() {
+				result := sqlmock.NewResult(0, 0)
+				mock.ExpectExec("UPDATE `room` SET `price_amount` = (.+), `price_currency` = (.+) WHERE `id` = (.+)").
+					WithArgs(1250, "USD", 1, 1).WillReturnResult(result)
+				mock.ExpectQuery("SELECT EXISTS").WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:  "Not Found",
+			input: &pkg.RoomUpdate{Description: &description},
+			mock: func() {
+				result := sqlmock.NewResult(0, 0)
+				mock.ExpectExec("UPDATE `room` SET `description` = (.+) WHERE `id` = (.+)").
+					WithArgs("VIP ROOM", 1, 1).WillReturnResult(result)
+				mock.ExpectQuery("SELECT EXISTS").WithArgs(1, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name:    "Nothing to update",
+			input:   &pkg.RoomUpdate{},
+			mock:    func() {},
+			wantErr: pkg.ErrNothingToUpdate,
+		},
+		{
+			name:  "Failed Save",
+			input: &pkg.RoomUpdate{Price: &price},
+			mock: func() {
+				mock.ExpectExec("UPDATE `room` SET `price_amount` = (.+), `price_currency` = (.+) WHERE `id` = (.+)").
+					WithArgs(1250, "USD", 1, 1).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedSave,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 1, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomMySQL_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    *pkg.Room
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(4, "2018-01-03", 1250, "EUR", "VIP ROOM", 2, 0, nil, 1)
+				mock.ExpectQuery("SELECT (.+) FROM room WHERE `id` = (.+) AND `deleted_at` IS NULL").
+					WithArgs(4, 1).
+					WillReturnRows(rows)
+			},
+			want: &pkg.Room{
+				ID:          4,
+				PropertyID:  1,
+				Description: "VIP ROOM",
+				Price:       pkg.Money{Amount: 1250, Currency: "EUR"},
+				Date:        "2018-01-03",
+				MaxAdults:   2,
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"})
+				mock.ExpectQuery("SELECT (.+) FROM room").WithArgs(4, 1).WillReturnRows(rows)
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name: "Conn done",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM room").WithArgs(4, 1).WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			room, err := r.GetByID(context.Background(), 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if tt.want != nil && (room == nil || *room != *tt.want) {
+				t.Error("wrong room received: ", room)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomMySQL_GetProperty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomMySQL(db)
+
+	mock.ExpectQuery("SELECT `property_id` FROM `room` WHERE `id` = (.+)$").
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}).AddRow(2))
+	property, err := r.GetProperty(context.Background(), 4)
+	if err != nil || property != 2 {
+		t.Error("wrong property received: ", property, err)
+	}
+
+	mock.ExpectQuery("SELECT `property_id` FROM `room`").
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}))
+	_, err = r.GetProperty(context.Background(), 5)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the room of another tenant is not found
+	mock.ExpectQuery("SELECT `property_id` FROM `room`").
+		WithArgs(4, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}))
+	_, err = r.GetProperty(pkg.WithTenant(context.Background(), 2), 4)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT `property_id` FROM `room`").
+		WithArgs(6, 1).
+		WillReturnError(sql.ErrConnDone)
+	_, err = r.GetProperty(context.Background(), 6)
+	if !errors.Is(err, pkg.ErrFailedGet) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,191 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomTypesMySQL struct {
+	db *sql.DB
+}
+
+func NewRoomTypesMySQL(db *sql.DB) *RoomTypesMySQL {
+	return &RoomTypesMySQL{db: db}
+}
+
+// The type belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the type id.
+func (r *RoomTypesMySQL) Add(ctx context.Context, t *pkg.RoomType) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO `room_types` (`tenant_id`, `property_id`, `name`, `description`, `price_amount`, `price_currency`,"+
+			" `max_adults`, `max_children`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		t.PropertyID,
+		t.Name,
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	t.ID, err = res.LastInsertId()
+	return err
+}
+
+// Replaces the type, the rooms of the type
+// get its fields in the same transaction.
+// The property of the type is not changed.
+func (r *RoomTypesMySQL) Update(ctx context.Context, id int64, t *pkg.RoomType) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE `room_types` SET `name` = ?, `description` = ?, `price_amount` = ?,"+
+			" `price_currency` = ?, `max_adults` = ?, `max_children` = ? WHERE `id` = ? AND `tenant_id` = ?",
+		t.Name,
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	// MySQL does not count rows whose values have not changed
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		check := false
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT EXISTS (SELECT `id` FROM `room_types` WHERE `id` = ? AND `tenant_id` = ?)",
+			id,
+			pkg.TenantFrom(ctx),
+		).Scan(&check)
+		if err != nil {
+			return pkg.ErrFailedSave.Wrap(err)
+		}
+		if !check {
+			return pkg.ErrIDNotFound
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE `room` SET `description` = ?, `price_amount` = ?, `price_currency` = ?,"+
+			" `max_adults` = ?, `max_children` = ? WHERE `type_id` = ? AND `tenant_id` = ?",
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *RoomTypesMySQL) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	types, err := r.get(ctx, typeColumns+" WHERE `id` = ? AND `tenant_id` = ?", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(types) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &types[0], nil
+}
+
+// returns the types of the tenant sorted by id,
+// if property is not zero, only the types of the property
+func (r *RoomTypesMySQL) List(ctx context.Context, property int64) ([]pkg.RoomType, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	where, args := " WHERE `tenant_id` = ?", []interface{}{pkg.TenantFrom(ctx)}
+	if property != 0 {
+		where, args = where+" AND `property_id` = ?", append(args, property)
+	}
+
+	types, err := r.get(ctx, typeColumns+where+" ORDER BY `id`", args...)
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return types, nil
+}
+
+const typeColumns = "SELECT `id`, `property_id`, `name`, `description`, `price_amount`, `price_currency`," +
+	" `max_adults`, `max_children` FROM `room_types`"
+
+// reads the rows of typeColumns
+func (r *RoomTypesMySQL) get(ctx context.Context, query string, args ...interface{}) ([]pkg.RoomType, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make([]pkg.RoomType, 0)
+	for rows.Next() {
+		t := pkg.RoomType{}
+		err = rows.Scan(
+			&t.ID,
+			&t.PropertyID,
+			&t.Name,
+			&t.Description,
+			&t.Price.Amount,
+			&t.Price.Currency,
+			&t.MaxAdults,
+			&t.MaxChildren,
+		)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
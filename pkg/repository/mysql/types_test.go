@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRoomTypesMySQL_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomTypesMySQL(db)
+	double := pkg.RoomType{
+		PropertyID:  2,
+		Name:        "Double Deluxe",
+		Description: "two beds",
+		Price:       pkg.Money{Amount: 12000, Currency: "USD"},
+		MaxAdults:   2,
+		MaxChildren: 1,
+	}
+
+	mock.ExpectExec("INSERT INTO `room_types` (.+) VALUES (.+)").
+		WithArgs(1, 2, "Double Deluxe", "two beds", 12000, "USD", 2, 1).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	err = r.Add(context.Background(), &double)
+	if err != nil || double.ID != 3 {
+		t.Error("wrong type saved: ", double, err)
+	}
+
+	mock.ExpectExec("INSERT INTO `room_types`").WillReturnError(sql.ErrConnDone)
+	err = r.Add(context.Background(), &pkg.RoomType{Name: "Single"})
+	if !errors.Is(err, pkg.ErrFailedSave) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoomTypesMySQL_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomTypesMySQL(db)
+	double := &pkg.RoomType{
+		Name:        "Double Deluxe",
+		Description: "two beds",
+		Price:       pkg.Money{Amount: 12000, Currency: "USD"},
+		MaxAdults:   2,
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `room_types` SET (.+) WHERE `id` = (.+)").
+					WithArgs("Double Deluxe", "two beds", 12000, "USD", 2, 0, 3, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `room` SET (.+) WHERE `type_id` = \\? AND `tenant_id` = \\?").
+					WithArgs("two beds", 12000, "USD", 2, 0, 3, 1).
+					WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "OK nothing changed",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `room_types`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectExec("UPDATE `room` SET").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `room_types`").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 3, double)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomTypesMySQL_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomTypesMySQL(db)
+	columns := []string{"id", "property_id", "name", "description", "price_amount", "price_currency", "max_adults", "max_children"}
+	types := []pkg.RoomType{
+		{ID: 1, PropertyID: 1, Name: "Single", Price: pkg.Money{Amount: 8000, Currency: "USD"}, MaxAdults: 1},
+		{ID: 3, PropertyID: 2, Name: "Double Deluxe", Description: "two beds", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2, MaxChildren: 1},
+	}
+
+	mock.ExpectQuery("SELECT `id`, `property_id`, `name`, `description`, `price_amount`, `price_currency`,"+
+		" `max_adults`, `max_children` FROM `room_types` WHERE `id` = (.+)").
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 2, "Double Deluxe", "two beds", 12000, "USD", 2, 1))
+	got, err := r.GetByID(context.Background(), 3)
+	if err != nil || *got != types[1] {
+		t.Error("wrong type received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `room_types` WHERE `id` = (.+)").
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(context.Background(), 4)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `room_types` WHERE `tenant_id` = (.+) ORDER BY `id`").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, 1, "Single", "", 8000, "USD", 1, 0).
+			AddRow(3, 2, "Double Deluxe", "two beds", 12000, "USD", 2, 1))
+	list, err := r.List(context.Background(), 0)
+	if err != nil || !reflect.DeepEqual(list, types) {
+		t.Error("wrong types received: ", list, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM `room_types` WHERE `tenant_id` = (.+) AND `property_id` = (.+) ORDER BY `id`").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, 2, "Double Deluxe", "two beds", 12000, "USD", 2, 1))
+	list, err = r.List(context.Background(), 2)
+	if err != nil || !reflect.DeepEqual(list, types[1:]) {
+		t.Error("wrong types of the property received: ", list, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,430 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestBookingsPostgres_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsPostgres(db)
+
+	lock := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 AND tenant_id = \\$2 AND deleted_at IS NULL FOR UPDATE").
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+	}
+	conflict := func(exists bool) {
+		mock.ExpectQuery("SELECT EXISTS").
+			WithArgs(3, 0, "2018-02-10", "2018-02-03").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+	}
+	typeLock := func() {
+		mock.ExpectQuery("SELECT id FROM room_types WHERE id = \\$1 AND tenant_id = \\$2 FOR UPDATE").
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT id FROM room WHERE type_id = \\$1 AND tenant_id = \\$2 AND deleted_at IS NULL ORDER BY id").
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4))
+	}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				lock()
+				conflict(false)
+				mock.ExpectQuery("INSERT INTO bookings (.+) RETURNING id").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
+			},
+			want: 1,
+		},
+		{
+			name: "No Foreign Key",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name: "Foreign Key Fails",
+			mock: func() {
+				lock()
+				conflict(false)
+				mock.ExpectQuery("INSERT INTO bookings").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
+					WillReturnError(&pq.Error{Code: "23503"})
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name: "Dates Check Fails",
+			mock: func() {
+				lock()
+				conflict(false)
+				mock.ExpectQuery("INSERT INTO bookings").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "bookings_dates_check"})
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name: "Booking Conflict",
+			mock: func() {
+				lock()
+				conflict(true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name: "Conn Done",
+			mock: func() {
+				lock()
+				conflict(false)
+				mock.ExpectQuery("INSERT INTO bookings").
+					WithArgs(1, 3, nil, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			wantErr: sql.ErrConnDone,
+		},
+		{
+			name: "OK room of a type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(2))
+				conflict(false)
+				typeLock()
+				mock.ExpectQuery("SELECT room_id, date_start::text, date_end::text FROM bookings").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(nil, "2018-02-01", "2018-02-05"))
+				mock.ExpectQuery("INSERT INTO bookings").
+					WithArgs(1, 3, 2, nil, 0, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, 8750, "USD").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+				mock.ExpectCommit()
+			},
+			want: 5,
+		},
+		{
+			name: "No free room of the type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(2))
+				conflict(false)
+				typeLock()
+				mock.ExpectQuery("SELECT room_id, date_start::text, date_end::text FROM bookings").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(nil, "2018-02-01", "2018-02-05").
+						AddRow(nil, "2018-02-04", "2018-02-12"))
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			// the booking takes room 3, the stay without a room
+			// has room 4 or room 5 free on each night, but not on all of them
+			name: "Free nights in different rooms of the type",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(2))
+				conflict(false)
+				mock.ExpectQuery("SELECT id FROM room_types WHERE id = \\$1 AND tenant_id = \\$2 FOR UPDATE").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery("SELECT id FROM room WHERE type_id = \\$1 (.+) ORDER BY id").
+					WithArgs(2, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(4).AddRow(5))
+				mock.ExpectQuery("SELECT room_id, date_start::text, date_end::text FROM bookings").
+					WithArgs(2, 1, 0, "2018-02-10", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"room_id", "date_start", "date_end"}).
+						AddRow(4, "2018-02-03", "2018-02-06").
+						AddRow(5, "2018-02-06", "2018-02-10").
+						AddRow(nil, "2018-02-04", "2018-02-08"))
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			booking := &pkg.Booking{
+				Start:  "2018-02-03",
+				End:    "2018-02-10",
+				Status: pkg.StatusPending,
+				Total:  &pkg.Money{Amount: 8750, Currency: "USD"},
+			}
+			err = r.Add(context.Background(), 3, booking)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && booking.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBookingsPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsPostgres(db)
+
+	selectBooking := func() {
+		mock.ExpectQuery("SELECT id, room_id, type_id, guest_id, adults, children, date_start::text, date_end::text, status,"+
+			" total_amount, total_currency FROM bookings WHERE id = \\$1 AND tenant_id = \\$2 FOR UPDATE").
+			WithArgs(7, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+				AddRow(7, 3, nil, nil, 1, 0, "2018-02-03", "2018-02-10", pkg.StatusPending, nil, nil))
+	}
+	cancel := func(b *pkg.Booking) error {
+		b.Status = pkg.StatusCancelled
+		return nil
+	}
+	move := func(b *pkg.Booking) error {
+		b.End = "2018-02-12"
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		change  func(b *pkg.Booking) error
+		mock    func()
+		wantErr error
+	}{
+		{
+			name:   "OK cancel",
+			change: cancel,
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectExec("UPDATE bookings SET (.+) WHERE id = \\$8").
+					WithArgs(3, nil, "2018-02-03", "2018-02-10", pkg.StatusCancelled, nil, nil, 7).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "Booking Conflict",
+			change: move,
+			mock: func() {
+				mock.ExpectBegin()
+				selectBooking()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 7, "2018-02-12", "2018-02-03").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrBookingConflict,
+		},
+		{
+			name:   "ID Not Found",
+			change: cancel,
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FROM bookings WHERE id = \\$1 AND tenant_id = \\$2 FOR UPDATE").
+					WithArgs(7, 1).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 7, tt.change)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBookingsPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsPostgres(db)
+
+	tests := []struct {
+		name      string
+		status    []pkg.BookingStatus
+		mock      func()
+		want      []pkg.Booking
+		wantErr   error
+		wantCause error
+	}{
+		{
+			name:   "OK status",
+			status: []pkg.BookingStatus{pkg.StatusPending, pkg.StatusConfirmed},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+					AddRow(1, 3, nil, nil, 1, 0, "2018-02-03", "2018-02-10", pkg.StatusConfirmed, 1050, "EUR")
+				mock.ExpectQuery("SELECT (.+) FROM bookings WHERE room_id = \\$1 AND tenant_id = \\$2"+
+					" AND status IN \\(\\$3, \\$4\\) ORDER BY date_start").
+					WithArgs(3, 1, pkg.StatusPending, pkg.StatusConfirmed).
+					WillReturnRows(rows)
+			},
+			want: []pkg.Booking{
+				{
+					ID:     1,
+					RoomID: 3,
+					Adults: 1,
+					Start:  "2018-02-03",
+					End:    "2018-02-10",
+					Status: pkg.StatusConfirmed,
+					Total:  &pkg.Money{Amount: 1050, Currency: "EUR"},
+				},
+			},
+		},
+		{
+			name: "ID Not Found",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM bookings WHERE room_id = \\$1 AND tenant_id = \\$2 ORDER BY date_start").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(3, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name: "Conn Done",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM bookings").
+					WithArgs(3, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr:   pkg.ErrFailedGet,
+			wantCause: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			bookings, err := r.Get(context.Background(), 3, tt.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			} else if tt.wantCause != nil && errors.Unwrap(err) != tt.wantCause {
+				t.Error("wrong cause of the error: ", err)
+			} else if err == nil {
+				if len(bookings) != len(tt.want) {
+					t.Fatal("wrong number of bookings received")
+				}
+				for i := range tt.want {
+					if !reflect.DeepEqual(bookings[i], tt.want[i]) {
+						t.Error("wrong booking received: ", bookings[i])
+					}
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBookingsPostgres_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsPostgres(db)
+	columns := []string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}
+
+	mock.ExpectQuery("SELECT (.+) FROM bookings WHERE id = \\$1").
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, nil, 2, nil, 2, 0, "2018-02-03", "2018-02-10", "confirmed", 8750, "USD"))
+	got, err := r.GetByID(context.Background(), 5)
+	want := pkg.Booking{
+		ID:     5,
+		TypeID: 2,
+		Adults: 2,
+		Start:  "2018-02-03",
+		End:    "2018-02-10",
+		Status: pkg.StatusConfirmed,
+		Total:  &pkg.Money{Amount: 8750, Currency: "USD"},
+	}
+	if err != nil || !reflect.DeepEqual(*got, want) {
+		t.Error("wrong booking received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM bookings WHERE id = \\$1").
+		WithArgs(6, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(context.Background(), 6)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the booking of another tenant is not found
+	mock.ExpectQuery("SELECT (.+) FROM bookings WHERE id = \\$1 AND tenant_id = \\$2").
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(pkg.WithTenant(context.Background(), 2), 5)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type GuestsPostgres struct {
+	db *sql.DB
+}
+
+func NewGuestsPostgres(db *sql.DB) *GuestsPostgres {
+	return &GuestsPostgres{db: db}
+}
+
+// The guest belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the guest id.
+// Returns pkg.ErrGuestExists if the email is taken in the tenant.
+func (r *GuestsPostgres) Add(ctx context.Context, guest *pkg.Guest) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO guests (tenant_id, name, email, phone, document_number) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		pkg.TenantFrom(ctx),
+		guest.Name,
+		guest.Email,
+		guest.Phone,
+		guest.Document,
+	).Scan(&guest.ID)
+	if err != nil {
+		return pgError(err)
+	}
+
+	return nil
+}
+
+func (r *GuestsPostgres) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE id = $1 AND tenant_id = $2", id, pkg.TenantFrom(ctx))
+}
+
+func (r *GuestsPostgres) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE email = $1 AND tenant_id = $2", email, pkg.TenantFrom(ctx))
+}
+
+const guestColumns = "SELECT id, name, email, phone, document_number FROM guests"
+
+// reads one guest, returns pkg.ErrIDNotFound if there is none
+func (r *GuestsPostgres) get(ctx context.Context, query string, args ...interface{}) (*pkg.Guest, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	g := pkg.Guest{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&g.ID, &g.Name, &g.Email, &g.Phone, &g.Document)
+	if err == sql.ErrNoRows {
+		return nil, pkg.ErrIDNotFound
+	} else if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return &g, nil
+}
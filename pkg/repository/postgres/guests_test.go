@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestGuestsPostgres_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewGuestsPostgres(db)
+
+	tests := []struct {
+		name    string
+		input   *pkg.Guest
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "+15550100", Document: "AB123"},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO guests (.+) RETURNING id").
+					WithArgs(1, "Anna", "anna@example.com", "+15550100", "AB123").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+			},
+			want: 2,
+		},
+		{
+			name:  "Guest Exists",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO guests").
+					WithArgs(1, "Anna", "anna@example.com", "", "").
+					WillReturnError(&pq.Error{Code: "23505", Constraint: "guests_email"})
+			},
+			wantErr: pkg.ErrGuestExists,
+		},
+		{
+			name:  "Conn Done",
+			input: &pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO guests").
+					WithArgs(1, "Anna", "anna@example.com", "", "").
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Add(context.Background(), tt.input)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.input.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGuestsPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewGuestsPostgres(db)
+	columns := []string{"id", "name", "email", "phone", "document_number"}
+	guest := &pkg.Guest{ID: 2, Name: "Anna", Email: "anna@example.com", Phone: "+15550100"}
+
+	mock.ExpectQuery("SELECT id, name, email, phone, document_number FROM guests WHERE id = \\$1").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Anna", "anna@example.com", "+15550100", ""))
+	got, err := r.GetByID(context.Background(), 2)
+	if err != nil || !reflect.DeepEqual(got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM guests WHERE email = \\$1").
+		WithArgs("anna@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Anna", "anna@example.com", "+15550100", ""))
+	got, err = r.GetByEmail(context.Background(), "anna@example.com")
+	if err != nil || !reflect.DeepEqual(got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM guests WHERE email = \\$1").
+		WithArgs("bob@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	if _, err := r.GetByEmail(context.Background(), "bob@example.com"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM guests WHERE id = \\$1").
+		WithArgs(3, 1).
+		WillReturnError(sql.ErrConnDone)
+	if _, err := r.GetByID(context.Background(), 3); !errors.Is(err, pkg.ErrFailedGet) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookingsPostgres_GetByGuest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewBookingsPostgres(db)
+
+	rows := sqlmock.NewRows([]string{"id", "room_id", "type_id", "guest_id", "adults", "children", "date_start", "date_end", "status", "total_amount", "total_currency"}).
+		AddRow(4, 1, nil, 2, 1, 0, "2018-03-06", "2018-03-08", "confirmed", 3998, "USD").
+		AddRow(6, 3, nil, 2, 1, 0, "2018-04-01", "2018-04-02", "pending", nil, nil)
+	mock.ExpectQuery("SELECT (.+) FROM bookings WHERE guest_id = \\$1 AND tenant_id = \\$2 ORDER BY date_start, id").
+		WithArgs(2, 1).WillReturnRows(rows)
+
+	got, err := r.GetByGuest(context.Background(), 2)
+	want := []pkg.Booking{
+		{ID: 4, RoomID: 1, GuestID: 2, Adults: 1, Start: "2018-03-06", End: "2018-03-08", Status: "confirmed", Total: &pkg.Money{Amount: 3998, Currency: "USD"}},
+		{ID: 6, RoomID: 3, GuestID: 2, Adults: 1, Start: "2018-04-01", End: "2018-04-02", Status: "pending"},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Error("wrong bookings received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM bookings WHERE guest_id = \\$1").
+		WithArgs(3, 1).WillReturnError(sql.ErrConnDone)
+	if _, err := r.GetByGuest(context.Background(), 3); !errors.Is(err, pkg.ErrFailedGet) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+// Connects to the database, the tables of a database
+// created by an older version are upgraded.
+func NewPostgresDB(cfg *Config) (*sql.DB, error) {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	db, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.DBName, sslMode))
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	err = migrate(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// deadlines of single operations, the request
+// context can only make them shorter
+const (
+	readTimeout  = 3 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// converts PostgreSQL errors to the pkg errors by their SQLSTATE code,
+// other errors are returned as is
+func pgError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "foreign_key_violation":
+		return pkg.ErrNoForeignKey
+	case "unique_violation":
+		if pqErr.Constraint == "guests_email" {
+			return pkg.ErrGuestExists
+		}
+	case "check_violation":
+		switch pqErr.Constraint {
+		case "room_price_check", "room_types_price_check", "rate_plans_price_check":
+			return pkg.ErrPriceNotValid
+		case "bookings_dates_check", "rate_plans_dates_check":
+			return pkg.ErrDateIsIncorrect
+		}
+	}
+
+	return err
+}
+
+// appends the argument and returns its placeholder
+func arg(args *[]interface{}, v interface{}) string {
+	*args = append(*args, v)
+	return "$" + strconv.Itoa(len(*args))
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type PropertiesPostgres struct {
+	db *sql.DB
+}
+
+func NewPropertiesPostgres(db *sql.DB) *PropertiesPostgres {
+	return &PropertiesPostgres{db: db}
+}
+
+// The property belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the property id.
+func (r *PropertiesPostgres) Add(ctx context.Context, p *pkg.Property) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO properties (tenant_id, name, address, timezone, currency) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+		pkg.TenantFrom(ctx),
+		p.Name,
+		p.Address,
+		p.Timezone,
+		p.Currency,
+	).Scan(&p.ID)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+// Replaces the property, its rooms and types are not changed.
+func (r *PropertiesPostgres) Update(ctx context.Context, id int64, p *pkg.Property) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE properties SET name = $1, address = $2, timezone = $3, currency = $4 WHERE id = $5 AND tenant_id = $6",
+		p.Name,
+		p.Address,
+		p.Timezone,
+		p.Currency,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+func (r *PropertiesPostgres) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	properties, err := r.get(ctx, propertyColumns+" WHERE id = $1 AND tenant_id = $2", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(properties) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &properties[0], nil
+}
+
+// returns the properties of the tenant sorted by id
+func (r *PropertiesPostgres) List(ctx context.Context) ([]pkg.Property, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	properties, err := r.get(ctx, propertyColumns+" WHERE tenant_id = $1 ORDER BY id", pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return properties, nil
+}
+
+const propertyColumns = "SELECT id, name, address, timezone, currency FROM properties"
+
+// reads the rows of propertyColumns
+func (r *PropertiesPostgres) get(ctx context.Context, query string, args ...interface{}) ([]pkg.Property, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	properties := make([]pkg.Property, 0)
+	for rows.Next() {
+		p := pkg.Property{}
+		err = rows.Scan(&p.ID, &p.Name, &p.Address, &p.Timezone, &p.Currency)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, p)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
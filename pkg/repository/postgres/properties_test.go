@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPropertiesPostgres_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesPostgres(db)
+	seaside := pkg.Property{
+		Name:     "Seaside",
+		Address:  "1 Beach Road",
+		Timezone: "Europe/Lisbon",
+		Currency: "EUR",
+	}
+
+	mock.ExpectQuery("INSERT INTO properties (.+) RETURNING id").
+		WithArgs(1, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	err = r.Add(context.Background(), &seaside)
+	if err != nil || seaside.ID != 2 {
+		t.Error("wrong property saved: ", seaside, err)
+	}
+
+	mock.ExpectQuery("INSERT INTO properties").WillReturnError(sql.ErrConnDone)
+	err = r.Add(context.Background(), &pkg.Property{Name: "Downtown"})
+	if !errors.Is(err, pkg.ErrFailedSave) {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertiesPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesPostgres(db)
+	seaside := &pkg.Property{Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "EUR"}
+
+	mock.ExpectExec("UPDATE properties SET (.+) WHERE id = \\$5 AND tenant_id = \\$6").
+		WithArgs("Seaside", "", "Europe/Lisbon", "EUR", 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = r.Update(context.Background(), 2, seaside)
+	if err != nil {
+		t.Error(err)
+	}
+
+	mock.ExpectExec("UPDATE properties").
+		WithArgs("Seaside", "", "Europe/Lisbon", "EUR", 3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = r.Update(context.Background(), 3, seaside)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertiesPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewPropertiesPostgres(db)
+	columns := []string{"id", "name", "address", "timezone", "currency"}
+	properties := []pkg.Property{
+		{ID: 1, Name: "Default", Timezone: "UTC", Currency: "USD"},
+		{ID: 2, Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"},
+	}
+
+	mock.ExpectQuery("SELECT id, name, address, timezone, currency FROM properties WHERE id = \\$1").
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR"))
+	got, err := r.GetByID(context.Background(), 2)
+	if err != nil || *got != properties[1] {
+		t.Error("wrong property received: ", got, err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM properties WHERE id = \\$1").
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = r.GetByID(context.Background(), 3)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	mock.ExpectQuery("SELECT (.+) FROM properties WHERE tenant_id = \\$1 ORDER BY id").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Default", "", "UTC", "USD").
+			AddRow(2, "Seaside", "1 Beach Road", "Europe/Lisbon", "EUR"))
+	list, err := r.List(context.Background())
+	if err != nil || !reflect.DeepEqual(list, properties) {
+		t.Error("wrong properties received: ", list, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RatePlansPostgres struct {
+	db *sql.DB
+}
+
+func NewRatePlansPostgres(db *sql.DB) *RatePlansPostgres {
+	return &RatePlansPostgres{db: db}
+}
+
+// The plan belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the plan id.
+// Returns pkg.ErrNoForeignKey if the room does not exist.
+func (r *RatePlansPostgres) Add(ctx context.Context, plan *pkg.RatePlan) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO rate_plans (tenant_id, room_id, name, date_start, date_end, weekdays,"+
+			" price_amount, price_currency, priority) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)"+
+			" RETURNING id",
+		pkg.TenantFrom(ctx),
+		plan.RoomID,
+		plan.Name,
+		nullDate(plan.Start),
+		nullDate(plan.End),
+		plan.Weekdays,
+		plan.Price.Amount,
+		plan.Price.Currency,
+		plan.Priority,
+	).Scan(&plan.ID)
+	if err != nil {
+		return pgError(err)
+	}
+
+	return nil
+}
+
+// Replaces the plan, its room is not changed.
+func (r *RatePlansPostgres) Update(ctx context.Context, id int64, plan *pkg.RatePlan) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE rate_plans SET name = $1, date_start = $2, date_end = $3, weekdays = $4,"+
+			" price_amount = $5, price_currency = $6, priority = $7 WHERE id = $8 AND tenant_id = $9",
+		plan.Name,
+		nullDate(plan.Start),
+		nullDate(plan.End),
+		plan.Weekdays,
+		plan.Price.Amount,
+		plan.Price.Currency,
+		plan.Priority,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pgError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// Deletes the plan, the totals of the bookings
+// priced with it do not change.
+func (r *RatePlansPostgres) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM rate_plans WHERE id = $1 AND tenant_id = $2", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+func (r *RatePlansPostgres) GetByID(ctx context.Context, id int64) (*pkg.RatePlan, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	plans, err := r.get(ctx, rateColumns+" WHERE id = $1 AND tenant_id = $2", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(plans) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &plans[0], nil
+}
+
+// returns the plans of the rooms of the tenant sorted by room and id
+func (r *RatePlansPostgres) Get(ctx context.Context, rooms []int64) ([]pkg.RatePlan, error) {
+	if len(rooms) == 0 {
+		return []pkg.RatePlan{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	plans, err := r.get(
+		ctx,
+		rateColumns+" WHERE room_id = ANY($1) AND tenant_id = $2 ORDER BY room_id, id",
+		pq.Array(rooms),
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return plans, nil
+}
+
+// columns of the plan, dates are read as YYYY-MM-DD text
+const rateColumns = "SELECT id, room_id, name, date_start::text, date_end::text, weekdays," +
+	" price_amount, price_currency, priority FROM rate_plans"
+
+// reads the rows of rateColumns, the dates of a plan
+// without a range are NULL
+func (r *RatePlansPostgres) get(ctx context.Context, query string, args ...interface{}) ([]pkg.RatePlan, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]pkg.RatePlan, 0)
+	for rows.Next() {
+		p := pkg.RatePlan{}
+		var start, end sql.NullString
+		err = rows.Scan(
+			&p.ID,
+			&p.RoomID,
+			&p.Name,
+			&start,
+			&end,
+			&p.Weekdays,
+			&p.Price.Amount,
+			&p.Price.Currency,
+			&p.Priority,
+		)
+		if err != nil {
+			return nil, err
+		}
+		p.Start, p.End = start.String, end.String
+		plans = append(plans, p)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// an empty date is kept as NULL
+func nullDate(date string) interface{} {
+	if date == "" {
+		return nil
+	}
+	return date
+}
@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestRatePlansPostgres_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansPostgres(db)
+
+	tests := []struct {
+		name    string
+		input   *pkg.RatePlan
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			input: &pkg.RatePlan{
+				RoomID:   1,
+				Name:     "Weekend",
+				Weekdays: 65,
+				Price:    pkg.Money{Amount: 1500, Currency: "USD"},
+			},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_plans (.+) RETURNING id").
+					WithArgs(1, 1, "Weekend", nil, nil, 65, 1500, "USD", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+			},
+			want: 4,
+		},
+		{
+			name: "No Foreign Key",
+			input: &pkg.RatePlan{
+				RoomID: 7,
+				Name:   "Summer",
+				Start:  "2018-06-01",
+				End:    "2018-09-01",
+				Price:  pkg.Money{Amount: 1500, Currency: "USD"},
+			},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_plans").
+					WithArgs(1, 7, "Summer", "2018-06-01", "2018-09-01", 0, 1500, "USD", 0).
+					WillReturnError(&pq.Error{Code: "23503"})
+			},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name:  "Price Check Fails",
+			input: &pkg.RatePlan{RoomID: 1, Price: pkg.Money{Amount: -100, Currency: "USD"}},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_plans").
+					WithArgs(1, 1, "", nil, nil, 0, -100, "USD", 0).
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "rate_plans_price_check"})
+			},
+			wantErr: pkg.ErrPriceNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Add(context.Background(), tt.input)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.input.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansPostgres(db)
+	plan := &pkg.RatePlan{Name: "Summer", Start: "2018-06-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}, Priority: 2}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("UPDATE rate_plans SET (.+) WHERE id = \\$8 AND tenant_id = \\$9").
+					WithArgs("Summer", "2018-06-01", nil, 0, 1500, "USD", 2, 4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "ID Not Found",
+			mock: func() {
+				mock.ExpectExec("UPDATE rate_plans").
+					WithArgs("Summer", "2018-06-01", nil, 0, 1500, "USD", 2, 4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 4, plan)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansPostgres(db)
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM rate_plans WHERE id = \\$1").
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "ID Not Found",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM rate_plans").
+					WithArgs(4, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name: "Failed Delete",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM rate_plans").
+					WithArgs(4, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Delete(context.Background(), 4)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRatePlansPostgres_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRatePlansPostgres(db)
+	columns := []string{"id", "room_id", "name", "date_start", "date_end", "weekdays",
+		"price_amount", "price_currency", "priority"}
+
+	tests := []struct {
+		name    string
+		mock    func()
+		want    []pkg.RatePlan
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, 1, "Weekend", nil, nil, 65, 600, "USD", 1).
+					AddRow(1, 2, "Summer", "2018-06-01", "2018-09-01", 0, 1500, "USD", 0)
+				mock.ExpectQuery("SELECT (.+) FROM rate_plans WHERE room_id = ANY\\(\\$1\\) AND tenant_id = \\$2 ORDER BY room_id, id").
+					WithArgs("{2,1}", 1).
+					WillReturnRows(rows)
+			},
+			want: []pkg.RatePlan{
+				{ID: 2, RoomID: 1, Name: "Weekend", Weekdays: 65, Price: pkg.Money{Amount: 600, Currency: "USD"}, Priority: 1},
+				{ID: 1, RoomID: 2, Name: "Summer", Start: "2018-06-01", End: "2018-09-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			},
+		},
+		{
+			name: "Failed Get",
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM rate_plans").
+					WithArgs("{2,1}", 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			plans, err := r.Get(context.Background(), []int64{2, 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(plans, tt.want) {
+				t.Error("wrong plans received: ", plans)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomPostgres struct {
+	db *sql.DB
+}
+
+func NewRoomPostgres(db *sql.DB) *RoomPostgres {
+	return &RoomPostgres{db: db}
+}
+
+// Uses fields: PropertyID, Description, Price, MaxAdults, MaxChildren, TypeID.
+// The room belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the room id.
+func (r *RoomPostgres) Add(ctx context.Context, room *pkg.Room) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO room (tenant_id, property_id, description, price_amount, price_currency, max_adults, max_children, type_id, date)"+
+			" VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_DATE) RETURNING id",
+		pkg.TenantFrom(ctx),
+		room.PropertyID,
+		room.Description,
+		room.Price.Amount,
+		room.Price.Currency,
+		room.MaxAdults,
+		room.MaxChildren,
+		nullID(room.TypeID),
+	).Scan(&room.ID)
+	if err != nil {
+		if errors.Is(pgError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+// Archives the room, its bookings stay in the history.
+// A room with bookings that have not ended yet is archived
+// only with force, their pending and confirmed bookings are cancelled.
+// Returns pkg.ErrRoomHasGuests while a guest is checked in, even with force,
+// such a room is archived only after the check-out.
+// Bookings that end after today, a YYYY-MM-DD date, have not ended.
+func (r *RoomPostgres) Delete(ctx context.Context, id int64, force bool, today string) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	_, err = lockRoom(ctx, tx, id)
+	if errors.Is(err, pkg.ErrNoForeignKey) {
+		return pkg.ErrIDNotFound
+	} else if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	checkedIn := false
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT id FROM bookings WHERE room_id = $1 AND status = $2)",
+		id,
+		pkg.StatusCheckedIn,
+	).Scan(&checkedIn)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	if checkedIn {
+		return pkg.ErrRoomHasGuests
+	}
+
+	future := false
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT id FROM bookings"+
+			"	WHERE room_id = $1 AND date_end > $2 AND "+activeBookings+")",
+		id,
+		today,
+	).Scan(&future)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	if future {
+		if !force {
+			return pkg.ErrRoomHasBookings
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE bookings SET status = $1"+
+				"	WHERE room_id = $2 AND date_end > $3 AND status IN ($4, $5)",
+			pkg.StatusCancelled,
+			id,
+			today,
+			pkg.StatusPending,
+			pkg.StatusConfirmed,
+		)
+		if err != nil {
+			return pkg.ErrFailedDelete.Wrap(err)
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE room SET deleted_at = NOW() WHERE id = $1",
+		id,
+	)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	return nil
+}
+
+// Returns the archived room to the lists,
+// restoring a room that is not archived does nothing.
+func (r *RoomPostgres) Restore(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE room SET deleted_at = NULL WHERE id = $1 AND tenant_id = $2",
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// Changes only the non-nil fields of the update.
+func (r *RoomPostgres) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	set := make([]string, 0, 5)
+	args := make([]interface{}, 0, 7)
+	if update.Description != nil {
+		set = append(set, "description = "+arg(&args, *update.Description))
+	}
+	if update.Price != nil {
+		set = append(set,
+			"price_amount = "+arg(&args, update.Price.Amount),
+			"price_currency = "+arg(&args, update.Price.Currency))
+	}
+	if update.MaxAdults != nil {
+		set = append(set, "max_adults = "+arg(&args, *update.MaxAdults))
+	}
+	if update.MaxChildren != nil {
+		set = append(set, "max_children = "+arg(&args, *update.MaxChildren))
+	}
+	if len(set) == 0 {
+		return pkg.ErrNothingToUpdate
+	}
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE room SET "+strings.Join(set, ", ")+
+			" WHERE id = "+arg(&args, id)+" AND tenant_id = "+arg(&args, pkg.TenantFrom(ctx))+" AND deleted_at IS NULL",
+		args...,
+	)
+	if err != nil {
+		if errors.Is(pgError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// returns the property of the room,
+// archived rooms are also found
+func (r *RoomPostgres) GetProperty(ctx context.Context, id int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	var property int64
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT property_id FROM room WHERE id = $1 AND tenant_id = $2",
+		id,
+		pkg.TenantFrom(ctx),
+	).Scan(&property)
+	if err == sql.ErrNoRows {
+		return 0, pkg.ErrIDNotFound
+	} else if err != nil {
+		return 0, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return property, nil
+}
+
+// returns the room if it is not archived
+func (r *RoomPostgres) GetByID(ctx context.Context, id int64) (*pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rooms, err := r.get(ctx, 1, roomColumns+" WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(rooms) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &rooms[0], nil
+}
+
+// reads the rows one by one, size is the expected number of rooms
+func (r *RoomPostgres) get(ctx context.Context, size int, query string, args ...interface{}) ([]pkg.Room, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := make([]pkg.Room, 0, size)
+	for rows.Next() {
+		room := pkg.Room{}
+		var typeID sql.NullInt64
+		err = rows.Scan(
+			&room.ID,
+			&room.Date,
+			&room.Price.Amount,
+			&room.Price.Currency,
+			&room.Description,
+			&room.MaxAdults,
+			&room.MaxChildren,
+			&typeID,
+			&room.PropertyID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		room.TypeID = typeID.Int64
+		rooms = append(rooms, room)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// columns of the room, the date is read as YYYY-MM-DD text
+const roomColumns = "SELECT id, date::text, price_amount, price_currency, description," +
+	" max_adults, max_children, type_id, property_id FROM room"
+
+// returns the WHERE conditions of the filter and appends their arguments,
+// only the rooms of the tenant are selected, archived rooms are skipped
+func roomFilter(tenant int64, filter *pkg.RoomFilter, args *[]interface{}) string {
+	where := " WHERE tenant_id = " + arg(args, tenant) + " AND deleted_at IS NULL"
+	if filter.Currency != "" {
+		where += " AND price_currency = " + arg(args, filter.Currency)
+	}
+	if filter.MinPrice != nil {
+		where += " AND price_amount >= " + arg(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where += " AND price_amount <= " + arg(args, *filter.MaxPrice)
+	}
+	if filter.Adults > 0 || filter.Children > 0 {
+		where += " AND max_adults >= " + arg(args, filter.Adults)
+		where += " AND max_adults + max_children >= " + arg(args, filter.Adults+filter.Children)
+	}
+	if filter.TypeID != 0 {
+		where += " AND type_id = " + arg(args, filter.TypeID)
+	}
+	if filter.PropertyID != 0 {
+		where += " AND property_id = " + arg(args, filter.PropertyID)
+	}
+
+	return where
+}
+
+// columns the room list can be sorted by,
+// only these are put into the query,
+// prices are compared only within a currency
+var roomSortColumns = map[pkg.RoomSort]string{
+	pkg.SortByDate:  "date",
+	pkg.SortByPrice: "price_currency, price_amount",
+}
+
+// returns the ORDER BY clause of the query,
+// rooms with the same sort value are ordered by id
+func roomOrder(query *pkg.RoomQuery) (string, error) {
+	column, ok := roomSortColumns[query.Sort]
+	if !ok {
+		return "", pkg.ErrSortNotValid
+	}
+
+	order := " ORDER BY " + column
+	if query.Desc {
+		order += " DESC"
+	}
+	return order + ", id", nil
+}
+
+// returns one page of the filtered rooms, rooms with
+// the same sort value are ordered by id
+func (r *RoomPostgres) List(ctx context.Context, query pkg.RoomQuery) ([]pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	order, err := roomOrder(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, 6)
+	where := roomFilter(pkg.TenantFrom(ctx), &query.RoomFilter, &args)
+	page := " LIMIT " + arg(&args, query.Limit) + " OFFSET " + arg(&args, query.Offset)
+	return r.get(ctx, query.Limit, roomColumns+where+order+page, args...)
+}
+
+// returns the number of rooms matching the filter,
+// Limit and Offset are not used
+func (r *RoomPostgres) Count(ctx context.Context, filter *pkg.RoomFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	args := make([]interface{}, 0, 5)
+	where := roomFilter(pkg.TenantFrom(ctx), filter, &args)
+	var total int64
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM room"+where, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// returns rooms of the tenant that have no bookings
+// overlapping with the range from start to end, the rooms
+// of a type are returned while the type has more such rooms
+// than bookings without a room on these dates,
+// the rooms are filtered and sorted like in List,
+// Limit and Offset are not used
+func (r *RoomPostgres) GetAvailable(ctx context.Context, start, end string, query pkg.RoomQuery) ([]pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	order, err := roomOrder(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, 10)
+	where := roomFilter(pkg.TenantFrom(ctx), &query.RoomFilter, &args)
+	where += " AND NOT EXISTS (SELECT id FROM bookings" +
+		" WHERE bookings.room_id = room.id" +
+		" AND date_start < " + arg(&args, end) + " AND date_end > " + arg(&args, start) + " AND " + activeBookings + ")"
+	where += " AND " + typeHasRoom(&args, start, end)
+	return r.get(ctx, 0, roomColumns+where+order, args...)
+}
+
+// condition of the rooms without a type or whose type has more
+// rooms without bookings than bookings without a room
+func typeHasRoom(args *[]interface{}, start, end string) string {
+	unassigned := "SELECT COUNT(*) FROM bookings" +
+		" WHERE bookings.type_id = room.type_id AND bookings.room_id IS NULL" +
+		" AND date_start < " + arg(args, end) + " AND date_end > " + arg(args, start) + " AND " + activeBookings
+	free := "SELECT COUNT(*) FROM room AS free WHERE free.type_id = room.type_id AND free.deleted_at IS NULL" +
+		" AND NOT EXISTS (SELECT id FROM bookings WHERE bookings.room_id = free.id" +
+		" AND date_start < " + arg(args, end) + " AND date_end > " + arg(args, start) + " AND " + activeBookings + ")"
+	return "(room.type_id IS NULL OR (" + unassigned + ") < (" + free + "))"
+}
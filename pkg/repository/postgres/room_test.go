@@ -0,0 +1,438 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestRoomPostgres_Add(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomPostgres(db)
+
+	tests := []struct {
+		name    string
+		input   *pkg.Room
+		mock    func()
+		want    int64
+		wantErr error
+	}{
+		{
+			name:  "OK",
+			input: &pkg.Room{PropertyID: 2, Description: "VIP", Price: pkg.Money{Amount: 1250, Currency: "USD"}, MaxAdults: 2, MaxChildren: 1},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO room (.+) RETURNING id").
+					WithArgs(1, 2, "VIP", 1250, "USD", 2, 1, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+			},
+			want: 4,
+		},
+		{
+			name:  "Price Check Fails",
+			input: &pkg.Room{PropertyID: 1, Description: "VIP", Price: pkg.Money{Amount: -100, Currency: "USD"}},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO room").
+					WithArgs(1, 1, "VIP", -100, "USD", 0, 0, nil).
+					WillReturnError(&pq.Error{Code: "23514", Constraint: "room_price_check"})
+			},
+			wantErr: pkg.ErrPriceNotValid,
+		},
+		{
+			name:  "Failed Save",
+			input: &pkg.Room{PropertyID: 1, Description: "VIP", Price: pkg.Money{Amount: 1250, Currency: "USD"}},
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO room").
+					WithArgs(1, 1, "VIP", 1250, "USD", 0, 0, nil).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedSave,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Add(context.Background(), tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			} else if err == nil && tt.input.ID != tt.want {
+				t.Error("wrong id received")
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomPostgres_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomPostgres(db)
+
+	lock := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 AND tenant_id = \\$2 AND deleted_at IS NULL FOR UPDATE").
+			WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"type_id"}).AddRow(nil))
+	}
+	future := func(exists bool) {
+		mock.ExpectQuery("SELECT EXISTS (.+) date_end > \\$2").
+			WithArgs(1, "2018-02-01").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+	}
+	checkedIn := func(exists bool) {
+		mock.ExpectQuery("SELECT EXISTS (.+) status = \\$2").
+			WithArgs(1, "checked_in").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+	}
+
+	tests := []struct {
+		name    string
+		force   bool
+		mock    func()
+		wantErr error
+	}{
+		{
+			name: "OK",
+			mock: func() {
+				lock()
+				checkedIn(false)
+				future(false)
+				mock.ExpectExec("UPDATE room SET deleted_at = NOW\\(\\) WHERE id = \\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "OK force",
+			force: true,
+			mock: func() {
+				lock()
+				checkedIn(false)
+				future(true)
+				mock.ExpectExec("UPDATE bookings SET status = \\$1").
+					WithArgs("cancelled", 1, "2018-02-01", "pending", "confirmed").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("UPDATE room SET deleted_at = NOW\\(\\) WHERE id = \\$1").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:  "Has guests",
+			force: true,
+			mock: func() {
+				lock()
+				checkedIn(true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrRoomHasGuests,
+		},
+		{
+			name: "Has guests leaving today",
+			mock: func() {
+				lock()
+				checkedIn(true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrRoomHasGuests,
+		},
+		{
+			name: "Has bookings",
+			mock: func() {
+				lock()
+				checkedIn(false)
+				future(true)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrRoomHasBookings,
+		},
+		{
+			name: "Not Found",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT type_id FROM room WHERE id = \\$1 (.+) FOR UPDATE").
+					WithArgs(1, 1).WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Delete(context.Background(), 1, tt.force, "2018-02-01")
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomPostgres_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomPostgres(db)
+
+	description := "VIP ROOM"
+	price := pkg.Money{Amount: 1250, Currency: "USD"}
+
+	tests := []struct {
+		name    string
+		input   *pkg.RoomUpdate
+		mock    func()
+		wantErr error
+	}{
+		{
+			name:  "OK all fields",
+			input: &pkg.RoomUpdate{Description: &description, Price: &price},
+			mock: func() {
+				mock.ExpectExec("UPDATE room SET description = \\$1, price_amount = \\$2, price_currency = \\$3"+
+					" WHERE id = \\$4 AND tenant_id = \\$5 AND deleted_at IS NULL").
+					WithArgs("VIP ROOM", 1250, "USD", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:  "Not Found",
+			input: &pkg.RoomUpdate{Price: &price},
+			mock: func() {
+				mock.ExpectExec("UPDATE room SET price_amount = \\$1, price_currency = \\$2 WHERE id = \\$3").
+					WithArgs(1250, "USD", 1, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: pkg.ErrIDNotFound,
+		},
+		{
+			name:    "Nothing to update",
+			input:   &pkg.RoomUpdate{},
+			mock:    func() {},
+			wantErr: pkg.ErrNothingToUpdate,
+		},
+		{
+			name:  "Failed Save",
+			input: &pkg.RoomUpdate{Price: &price},
+			mock: func() {
+				mock.ExpectExec("UPDATE room SET price_amount = \\$1, price_currency = \\$2 WHERE id = \\$3").
+					WithArgs(1250, "USD", 1, 1).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: pkg.ErrFailedSave,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			err = r.Update(context.Background(), 1, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Error(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomPostgres_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomPostgres(db)
+
+	var minPrice int64 = 350
+
+	tests := []struct {
+		name    string
+		query   pkg.RoomQuery
+		mock    func()
+		want    []pkg.Room
+		wantErr error
+	}{
+		{
+			name: "OK price filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByPrice,
+				Desc:       true,
+				RoomFilter: pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, Limit: 2, Offset: 4},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(2, "2018-03-06", 503, "USD", "VIP ROOM", 2, 0, nil, 1).
+					AddRow(1, "2018-01-03", 354, "USD", "Good room", 2, 0, nil, 1)
+
+				mock.ExpectQuery("SELECT id, date::text, price_amount, price_currency, description, max_adults, max_children, type_id, property_id FROM room"+
+					" WHERE tenant_id = \\$1 AND deleted_at IS NULL AND price_currency = \\$2 AND price_amount >= \\$3"+
+					" ORDER BY price_currency, price_amount DESC, id LIMIT \\$4 OFFSET \\$5").
+					WithArgs(1, "USD", 350, 2, 4).
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          2,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 503, Currency: "USD"},
+					Date:        "2018-03-06",
+					Description: "VIP ROOM",
+					MaxAdults:   2,
+				},
+				{
+					ID:          1,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 354, Currency: "USD"},
+					Date:        "2018-01-03",
+					Description: "Good room",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name: "OK property filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				RoomFilter: pkg.RoomFilter{PropertyID: 2, TypeID: 3, Limit: 20},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(7, "2018-05-01", 9000, "EUR", "Sea view", 2, 0, 3, 2)
+
+				mock.ExpectQuery("SELECT (.+) FROM room"+
+					" WHERE tenant_id = \\$1 AND deleted_at IS NULL AND type_id = \\$2 AND property_id = \\$3"+
+					" ORDER BY date, id LIMIT \\$4 OFFSET \\$5").
+					WithArgs(1, 3, 2, 20, 0).
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          7,
+					PropertyID:  2,
+					TypeID:      3,
+					Price:       pkg.Money{Amount: 9000, Currency: "EUR"},
+					Date:        "2018-05-01",
+					Description: "Sea view",
+					MaxAdults:   2,
+				},
+			},
+		},
+		{
+			name: "OK guests filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				RoomFilter: pkg.RoomFilter{Adults: 1, Children: 2, Limit: 20},
+			},
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "date", "price_amount", "price_currency", "description", "max_adults", "max_children", "type_id", "property_id"}).
+					AddRow(8, "2018-05-02", 7000, "USD", "Family", 1, 2, nil, 1)
+
+				mock.ExpectQuery("SELECT (.+) FROM room"+
+					" WHERE tenant_id = \\$1 AND deleted_at IS NULL AND max_adults >= \\$2 AND max_adults \\+ max_children >= \\$3"+
+					" ORDER BY date, id LIMIT \\$4 OFFSET \\$5").
+					WithArgs(1, 1, 3, 20, 0).
+					WillReturnRows(rows)
+			},
+			want: []pkg.Room{
+				{
+					ID:          8,
+					PropertyID:  1,
+					Price:       pkg.Money{Amount: 7000, Currency: "USD"},
+					Date:        "2018-05-02",
+					Description: "Family",
+					MaxAdults:   1,
+					MaxChildren: 2,
+				},
+			},
+		},
+		{
+			name:    "Sort not valid",
+			query:   pkg.RoomQuery{Sort: "description"},
+			mock:    func() {},
+			wantErr: pkg.ErrSortNotValid,
+		},
+		{
+			name:  "Conn done",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}},
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM room WHERE tenant_id = \\$1 AND deleted_at IS NULL ORDER BY date, id").
+					WithArgs(1, 20, 0).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			rooms, err := r.List(context.Background(), tt.query)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil {
+				if len(rooms) != len(tt.want) {
+					t.Fatal("wrong number of rooms received")
+				}
+				for i := range tt.want {
+					if rooms[i] != tt.want[i] {
+						t.Error("array sorted incorrectly")
+					}
+				}
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomPostgres_GetProperty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := NewRoomPostgres(db)
+
+	mock.ExpectQuery("SELECT property_id FROM room WHERE id = \\$1 AND tenant_id = \\$2$").
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}).AddRow(2))
+	property, err := r.GetProperty(context.Background(), 4)
+	if err != nil || property != 2 {
+		t.Error("wrong property received: ", property, err)
+	}
+
+	mock.ExpectQuery("SELECT property_id FROM room").
+		WithArgs(5, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"property_id"}))
+	_, err = r.GetProperty(context.Background(), 5)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
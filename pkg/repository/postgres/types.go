@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomTypesPostgres struct {
+	db *sql.DB
+}
+
+func NewRoomTypesPostgres(db *sql.DB) *RoomTypesPostgres {
+	return &RoomTypesPostgres{db: db}
+}
+
+// The type belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the type id.
+func (r *RoomTypesPostgres) Add(ctx context.Context, t *pkg.RoomType) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		"INSERT INTO room_types (tenant_id, property_id, name, description, price_amount, price_currency,"+
+			" max_adults, max_children) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		pkg.TenantFrom(ctx),
+		t.PropertyID,
+		t.Name,
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+	).Scan(&t.ID)
+	if err != nil {
+		if errors.Is(pgError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+// Replaces the type, the rooms of the type
+// get its fields in the same transaction.
+// The property of the type is not changed.
+func (r *RoomTypesPostgres) Update(ctx context.Context, id int64, t *pkg.RoomType) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(
+		ctx,
+		"UPDATE room_types SET name = $1, description = $2, price_amount = $3,"+
+			" price_currency = $4, max_adults = $5, max_children = $6 WHERE id = $7 AND tenant_id = $8",
+		t.Name,
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		if errors.Is(pgError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE room SET description = $1, price_amount = $2, price_currency = $3,"+
+			" max_adults = $4, max_children = $5 WHERE type_id = $6 AND tenant_id = $7",
+		t.Description,
+		t.Price.Amount,
+		t.Price.Currency,
+		t.MaxAdults,
+		t.MaxChildren,
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+func (r *RoomTypesPostgres) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	types, err := r.get(ctx, typeColumns+" WHERE id = $1 AND tenant_id = $2", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(types) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &types[0], nil
+}
+
+// returns the types of the tenant sorted by id,
+// if property is not zero, only the types of the property
+func (r *RoomTypesPostgres) List(ctx context.Context, property int64) ([]pkg.RoomType, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	args := make([]interface{}, 0, 2)
+	where := " WHERE tenant_id = " + arg(&args, pkg.TenantFrom(ctx))
+	if property != 0 {
+		where += " AND property_id = " + arg(&args, property)
+	}
+
+	types, err := r.get(ctx, typeColumns+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return types, nil
+}
+
+const typeColumns = "SELECT id, property_id, name, description, price_amount, price_currency," +
+	" max_adults, max_children FROM room_types"
+
+// reads the rows of typeColumns
+func (r *RoomTypesPostgres) get(ctx context.Context, query string, args ...interface{}) ([]pkg.RoomType, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := make([]pkg.RoomType, 0)
+	for rows.Next() {
+		t := pkg.RoomType{}
+		err = rows.Scan(
+			&t.ID,
+			&t.PropertyID,
+			&t.Name,
+			&t.Description,
+			&t.Price.Amount,
+			&t.Price.Currency,
+			&t.MaxAdults,
+			&t.MaxChildren,
+		)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return types, nil
+}
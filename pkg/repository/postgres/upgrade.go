@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// a step of the schema, done is a query that returns a row
+// if the database already has the step
+type upgrade struct {
+	done string
+	up   string
+}
+
+func hasTable(table string) string {
+	return "SELECT 1 FROM information_schema.tables" +
+		" WHERE table_schema = current_schema() AND table_name = '" + table + "'"
+}
+
+func hasColumn(table, column string) string {
+	return "SELECT 1 FROM information_schema.columns" +
+		" WHERE table_schema = current_schema() AND table_name = '" + table + "' AND column_name = '" + column + "'"
+}
+
+// steps from the first schema in sql-init/postgres/init.sql to the current one,
+// the step i upgrades a database of the version i to the version i+1,
+// the version is kept in the schema_version table
+var upgrades = []upgrade{
+	// the old prices are converted to USD cents
+	{
+		done: hasColumn("room", "price_amount"),
+		up: `
+ALTER TABLE room ADD price_amount BIGINT NULL, ADD price_currency CHAR(3) NULL;
+
+UPDATE room SET price_amount = CAST(ROUND(price * 100) AS BIGINT), price_currency = 'USD';
+
+DROP INDEX room_price_date;
+
+ALTER TABLE room DROP CONSTRAINT room_price_check;
+ALTER TABLE room
+  DROP price,
+  ALTER price_amount SET NOT NULL,
+  ALTER price_currency SET NOT NULL,
+  ADD CONSTRAINT room_price_check CHECK (price_amount >= 0);
+
+CREATE INDEX room_price_date ON room (price_currency, price_amount, date);
+`,
+	},
+	{
+		done: hasColumn("bookings", "total_amount"),
+		up: `
+ALTER TABLE bookings ADD total_amount BIGINT NULL, ADD total_currency CHAR(3) NULL;
+`,
+	},
+	{
+		done: hasTable("rate_plans"),
+		up: `
+CREATE TABLE rate_plans (
+  id              BIGSERIAL PRIMARY KEY,
+  room_id         BIGINT NOT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  name            VARCHAR(255) NOT NULL,
+  date_start      DATE NULL,
+  date_end        DATE NULL,
+  weekdays        SMALLINT NOT NULL DEFAULT 0,
+  price_amount    BIGINT NOT NULL,
+  price_currency  CHAR(3) NOT NULL,
+  priority        INT NOT NULL DEFAULT 0,
+
+  CONSTRAINT rate_plans_price_check CHECK (price_amount >= 0),
+  CONSTRAINT rate_plans_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX rate_plans_room ON rate_plans (room_id);
+`,
+	},
+	{
+		done: hasTable("guests"),
+		up: `
+CREATE TABLE guests (
+  id              BIGSERIAL PRIMARY KEY,
+  name            VARCHAR(255) NOT NULL,
+  email           VARCHAR(255) NOT NULL,
+  phone           VARCHAR(32) NOT NULL DEFAULT '',
+  document_number VARCHAR(64) NOT NULL DEFAULT '',
+
+  CONSTRAINT guests_email UNIQUE (email)
+);
+
+ALTER TABLE bookings ADD guest_id BIGINT NULL REFERENCES guests (id) ON DELETE RESTRICT;
+
+CREATE INDEX bookings_guest_date ON bookings (guest_id, date_start);
+`,
+	},
+	{
+		done: hasColumn("room", "max_adults"),
+		up: `
+ALTER TABLE room ADD max_adults INT NOT NULL DEFAULT 2, ADD max_children INT NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD adults INT NOT NULL DEFAULT 1, ADD children INT NOT NULL DEFAULT 0;
+`,
+	},
+	// the room of a booking becomes optional
+	{
+		done: hasTable("room_types"),
+		up: `
+CREATE TABLE room_types (
+  id              BIGSERIAL PRIMARY KEY,
+  name            VARCHAR(255) NOT NULL,
+  description     VARCHAR(1024) NOT NULL,
+  price_amount    BIGINT NOT NULL,
+  price_currency  CHAR(3) NOT NULL,
+  max_adults      INT NOT NULL DEFAULT 2,
+  max_children    INT NOT NULL DEFAULT 0,
+
+  CONSTRAINT room_types_price_check CHECK (price_amount >= 0)
+);
+
+ALTER TABLE room ADD type_id BIGINT NULL REFERENCES room_types (id) ON DELETE RESTRICT;
+
+CREATE INDEX room_type ON room (type_id);
+
+ALTER TABLE bookings
+  ALTER room_id DROP NOT NULL,
+  ADD type_id BIGINT NULL REFERENCES room_types (id) ON DELETE RESTRICT;
+
+CREATE INDEX bookings_type_date ON bookings (type_id, date_start);
+`,
+	},
+	// the existing rooms and types belong to the default property
+	{
+		done: hasTable("properties"),
+		up: `
+CREATE TABLE properties (
+  id              BIGSERIAL PRIMARY KEY,
+  name            VARCHAR(255) NOT NULL,
+  address         VARCHAR(1024) NOT NULL DEFAULT '',
+  timezone        VARCHAR(64) NOT NULL,
+  currency        CHAR(3) NOT NULL
+);
+
+INSERT INTO properties (name, timezone, currency) VALUES ('Default', 'UTC', 'USD');
+
+ALTER TABLE room_types ADD property_id BIGINT NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT;
+ALTER TABLE room ADD property_id BIGINT NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT;
+
+CREATE INDEX room_property ON room (property_id, date);
+CREATE INDEX room_types_property ON room_types (property_id);
+`,
+	},
+	// the existing rows belong to the first tenant,
+	// the same email can be used by the guests of different tenants
+	{
+		done: hasColumn("room", "tenant_id"),
+		up: `
+ALTER TABLE properties ADD tenant_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE room_types ADD tenant_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE room ADD tenant_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE bookings ADD tenant_id BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE rate_plans ADD tenant_id BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE guests
+  ADD tenant_id BIGINT NOT NULL DEFAULT 1,
+  DROP CONSTRAINT guests_email,
+  ADD CONSTRAINT guests_email UNIQUE (tenant_id, email);
+
+CREATE INDEX room_tenant ON room (tenant_id, date);
+CREATE INDEX room_types_tenant ON room_types (tenant_id);
+CREATE INDEX properties_tenant ON properties (tenant_id);
+`,
+	},
+	{
+		done: hasTable("api_keys"),
+		up: `
+CREATE TABLE api_keys (
+  id              BIGSERIAL PRIMARY KEY,
+  tenant_id       BIGINT NOT NULL,
+  role            VARCHAR(16) NOT NULL,
+  key_hash        CHAR(64) NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+  revoked_at      TIMESTAMP NULL,
+
+  CONSTRAINT api_keys_hash UNIQUE (key_hash)
+);
+`,
+	},
+}
+
+// A new database gets its tables from sql-init/postgres/init.sql,
+// a database created by an older version gets the steps
+// it does not have, each one in a transaction.
+func migrate(ctx context.Context, db *sql.DB) error {
+	version, kept, err := schemaVersion(ctx, db)
+	if err != nil || version < 0 {
+		return err
+	}
+	if version > len(upgrades) {
+		return fmt.Errorf("database schema version %d is newer than this build", version)
+	}
+
+	if !kept {
+		// the version of a database found by its steps is kept
+		_, err = db.ExecContext(ctx, versionTable(version))
+		if err != nil {
+			return err
+		}
+	}
+
+	for ; version < len(upgrades); version++ {
+		err = step(ctx, db, version)
+		if err != nil {
+			return fmt.Errorf("schema upgrade to version %d: %w", version+1, err)
+		}
+	}
+
+	return nil
+}
+
+// returns the version of the schema and whether it is kept,
+// -1 for a database without tables. A database created
+// before the version was kept is found by the steps it has.
+func schemaVersion(ctx context.Context, db *sql.DB) (int, bool, error) {
+	var version, one int
+	err := db.QueryRowContext(ctx, hasTable("schema_version")).Scan(&one)
+	if err == nil {
+		err = db.QueryRowContext(ctx, "SELECT version FROM schema_version").Scan(&version)
+		return version, true, err
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, err
+	}
+
+	err = db.QueryRowContext(ctx, hasTable("room")).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	for version = len(upgrades); version > 0; version-- {
+		err = db.QueryRowContext(ctx, upgrades[version-1].done).Scan(&one)
+		if err == nil {
+			break
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, false, err
+		}
+	}
+
+	return version, false, nil
+}
+
+// runs the step of the version in a transaction
+func step(ctx context.Context, db *sql.DB, version int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, upgrades[version].up)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE schema_version SET version = $1", version+1)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// several statements are run only without query arguments
+func versionTable(version int) string {
+	return fmt.Sprintf("CREATE TABLE schema_version (version INT NOT NULL);"+
+		" INSERT INTO schema_version (version) VALUES (%d);", version)
+}
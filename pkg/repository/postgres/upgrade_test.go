@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMigrate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	last := len(upgrades)
+	one := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"?column?"}).AddRow(1) }
+	version := func(v int) *sqlmock.Rows { return sqlmock.NewRows([]string{"version"}).AddRow(v) }
+
+	tests := []struct {
+		name    string
+		mock    func()
+		wantErr bool
+	}{
+		{
+			name: "New database",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(sqlmock.NewRows(nil))
+				mock.ExpectQuery(hasTable("room")).WillReturnRows(sqlmock.NewRows(nil))
+			},
+		},
+		{
+			name: "Current version",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(one())
+				mock.ExpectQuery("SELECT version FROM schema_version").WillReturnRows(version(last))
+			},
+		},
+		{
+			name: "Upgrade",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(one())
+				mock.ExpectQuery("SELECT version FROM schema_version").WillReturnRows(version(last - 1))
+				mock.ExpectBegin()
+				mock.ExpectExec(upgrades[last-1].up).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE schema_version SET version = $1").
+					WithArgs(last).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			// a database created before the version was kept
+			name: "Version is not kept",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(sqlmock.NewRows(nil))
+				mock.ExpectQuery(hasTable("room")).WillReturnRows(one())
+				mock.ExpectQuery(upgrades[last-1].done).WillReturnRows(one())
+				mock.ExpectExec(versionTable(last)).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Newer version",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(one())
+				mock.ExpectQuery("SELECT version FROM schema_version").WillReturnRows(version(last + 1))
+			},
+			wantErr: true,
+		},
+		{
+			name: "Failed step",
+			mock: func() {
+				mock.ExpectQuery(hasTable("schema_version")).WillReturnRows(one())
+				mock.ExpectQuery("SELECT version FROM schema_version").WillReturnRows(version(last - 1))
+				mock.ExpectBegin()
+				mock.ExpectExec(upgrades[last-1].up).WillReturnError(errors.New("some error"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := migrate(context.Background(), db)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error %v, want error %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
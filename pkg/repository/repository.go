@@ -1,37 +1,130 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository/memory"
 	"github.com/Avepa/booking/pkg/repository/mysql"
+	"github.com/Avepa/booking/pkg/repository/postgres"
+	"github.com/Avepa/booking/pkg/repository/sqlite"
 )
 
 //go:generate mockgen -source=repository.go -destination=mocks/mock.go
 
 type Room interface {
-	Add(room *pkg.Room) error
-	Delete(id int64) error
-	GetByDate() ([]pkg.Room, error)
-	GetByPrice() ([]pkg.Room, error)
-	GetByDateDESC() ([]pkg.Room, error)
-	GetByPriceDESC() ([]pkg.Room, error)
+	Add(ctx context.Context, room *pkg.Room) error
+	Delete(ctx context.Context, id int64, force bool, today string) error
+	Restore(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error
+	GetByID(ctx context.Context, id int64) (*pkg.Room, error)
+	GetProperty(ctx context.Context, id int64) (int64, error)
+	List(ctx context.Context, query pkg.RoomQuery) ([]pkg.Room, error)
+	Count(ctx context.Context, filter *pkg.RoomFilter) (int64, error)
+	GetAvailable(ctx context.Context, start, end string, query pkg.RoomQuery) ([]pkg.Room, error)
 }
 
 type Bookings interface {
-	Add(room int64, bookings *pkg.Booking) error
-	Delete(id int64) error
-	Get(id int64) ([]pkg.Booking, error)
+	Add(ctx context.Context, room int64, bookings *pkg.Booking) error
+	Update(ctx context.Context, id int64, change func(booking *pkg.Booking) error) error
+	GetByID(ctx context.Context, id int64) (*pkg.Booking, error)
+	Get(ctx context.Context, id int64, status []pkg.BookingStatus) ([]pkg.Booking, error)
+	GetByGuest(ctx context.Context, guest int64) ([]pkg.Booking, error)
+}
+
+type RoomTypes interface {
+	Add(ctx context.Context, t *pkg.RoomType) error
+	Update(ctx context.Context, id int64, t *pkg.RoomType) error
+	GetByID(ctx context.Context, id int64) (*pkg.RoomType, error)
+	List(ctx context.Context, property int64) ([]pkg.RoomType, error)
+}
+
+type Properties interface {
+	Add(ctx context.Context, p *pkg.Property) error
+	Update(ctx context.Context, id int64, p *pkg.Property) error
+	GetByID(ctx context.Context, id int64) (*pkg.Property, error)
+	List(ctx context.Context) ([]pkg.Property, error)
+}
+
+type RatePlans interface {
+	Add(ctx context.Context, plan *pkg.RatePlan) error
+	Update(ctx context.Context, id int64, plan *pkg.RatePlan) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*pkg.RatePlan, error)
+	Get(ctx context.Context, rooms []int64) ([]pkg.RatePlan, error)
+}
+
+type Guests interface {
+	Add(ctx context.Context, guest *pkg.Guest) error
+	GetByID(ctx context.Context, id int64) (*pkg.Guest, error)
+	GetByEmail(ctx context.Context, email string) (*pkg.Guest, error)
+}
+
+// The keys are not scoped by the tenant of the context,
+// the key of a request names its tenant.
+type APIKeys interface {
+	Add(ctx context.Context, key *pkg.APIKey) error
+	Revoke(ctx context.Context, id int64) error
+	GetByHash(ctx context.Context, hash string) (*pkg.APIKey, error)
 }
 
 type Repository struct {
+	Properties
 	Room
+	RoomTypes
 	Bookings
+	RatePlans
+	Guests
+	APIKeys
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Room:     mysql.NewRoomMySQL(db),
-		Bookings: mysql.NewBookingsMySQL(db),
+		Properties: mysql.NewPropertiesMySQL(db),
+		Room:       mysql.NewRoomMySQL(db),
+		RoomTypes:  mysql.NewRoomTypesMySQL(db),
+		Bookings:   mysql.NewBookingsMySQL(db),
+		RatePlans:  mysql.NewRatePlansMySQL(db),
+		Guests:     mysql.NewGuestsMySQL(db),
+		APIKeys:    mysql.NewAPIKeysMySQL(db),
+	}
+}
+
+// NewMemoryRepository keeps the data in memory until the process exits.
+func NewMemoryRepository() *Repository {
+	store := memory.NewStore()
+	return &Repository{
+		Properties: memory.NewPropertiesMemory(store),
+		Room:       memory.NewRoomMemory(store),
+		RoomTypes:  memory.NewRoomTypesMemory(store),
+		Bookings:   memory.NewBookingsMemory(store),
+		RatePlans:  memory.NewRatePlansMemory(store),
+		Guests:     memory.NewGuestsMemory(store),
+		APIKeys:    memory.NewAPIKeysMemory(store),
+	}
+}
+
+func NewPostgresRepository(db *sql.DB) *Repository {
+	return &Repository{
+		Properties: postgres.NewPropertiesPostgres(db),
+		Room:       postgres.NewRoomPostgres(db),
+		RoomTypes:  postgres.NewRoomTypesPostgres(db),
+		Bookings:   postgres.NewBookingsPostgres(db),
+		RatePlans:  postgres.NewRatePlansPostgres(db),
+		Guests:     postgres.NewGuestsPostgres(db),
+		APIKeys:    postgres.NewAPIKeysPostgres(db),
+	}
+}
+
+func NewSQLiteRepository(db *sql.DB) *Repository {
+	return &Repository{
+		Properties: sqlite.NewPropertiesSQLite(db),
+		Room:       sqlite.NewRoomSQLite(db),
+		RoomTypes:  sqlite.NewRoomTypesSQLite(db),
+		Bookings:   sqlite.NewBookingsSQLite(db),
+		RatePlans:  sqlite.NewRatePlansSQLite(db),
+		Guests:     sqlite.NewGuestsSQLite(db),
+		APIKeys:    sqlite.NewAPIKeysSQLite(db),
 	}
 }
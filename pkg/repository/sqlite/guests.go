@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type GuestsSQLite struct {
+	db *sql.DB
+}
+
+func NewGuestsSQLite(db *sql.DB) *GuestsSQLite {
+	return &GuestsSQLite{db: db}
+}
+
+// The guest belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the guest id.
+// Returns pkg.ErrGuestExists if the email is taken in the tenant.
+func (r *GuestsSQLite) Add(ctx context.Context, guest *pkg.Guest) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO guests (tenant_id, name, email, phone, document_number) VALUES (?, ?, ?, ?, ?)",
+		pkg.TenantFrom(ctx),
+		guest.Name,
+		guest.Email,
+		guest.Phone,
+		guest.Document,
+	)
+	if err != nil {
+		return sqliteError(err)
+	}
+
+	guest.ID, err = res.LastInsertId()
+	return err
+}
+
+func (r *GuestsSQLite) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE id = ? AND tenant_id = ?", id, pkg.TenantFrom(ctx))
+}
+
+func (r *GuestsSQLite) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	return r.get(ctx, guestColumns+" WHERE email = ? AND tenant_id = ?", email, pkg.TenantFrom(ctx))
+}
+
+const guestColumns = "SELECT id, name, email, phone, document_number FROM guests"
+
+// reads one guest, returns pkg.ErrIDNotFound if there is none
+func (r *GuestsSQLite) get(ctx context.Context, query string, args ...interface{}) (*pkg.Guest, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	g := pkg.Guest{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&g.ID, &g.Name, &g.Email, &g.Phone, &g.Document)
+	if err == sql.ErrNoRows {
+		return nil, pkg.ErrIDNotFound
+	} else if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return &g, nil
+}
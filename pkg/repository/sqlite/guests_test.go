@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestGuestsSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewGuestsSQLite(db)
+
+	guest := pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "+15550100", Document: "AB123"}
+	if err := r.Add(ctx, &guest); err != nil {
+		t.Fatal(err)
+	}
+	if guest.ID != 1 {
+		t.Error("wrong id received: ", guest.ID)
+	}
+
+	if err := r.Add(ctx, &pkg.Guest{Name: "Other", Email: "anna@example.com"}); err != pkg.ErrGuestExists {
+		t.Error("incorrect error received: ", err)
+	}
+
+	got, err := r.GetByID(ctx, 1)
+	if err != nil || !reflect.DeepEqual(*got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+	got, err = r.GetByEmail(ctx, "anna@example.com")
+	if err != nil || !reflect.DeepEqual(*got, guest) {
+		t.Error("wrong guest received: ", got, err)
+	}
+
+	if _, err := r.GetByID(ctx, 2); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if _, err := r.GetByEmail(ctx, "bob@example.com"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the email is taken only in the tenant of the guest
+	other := pkg.WithTenant(ctx, 2)
+	if _, err := r.GetByEmail(other, "anna@example.com"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if _, err := r.GetByID(other, guest.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Add(other, &pkg.Guest{Name: "Anna", Email: "anna@example.com"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBookingsSQLite_GetByGuest(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	addRooms(t, db,
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+		pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-01"},
+	)
+	if err := NewGuestsSQLite(db).Add(ctx, &pkg.Guest{Name: "Anna", Email: "anna@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	r := NewBookingsSQLite(db)
+
+	bookings := []struct {
+		room    int64
+		booking pkg.Booking
+	}{
+		{2, pkg.Booking{GuestID: 1, Start: "2018-03-01", End: "2018-03-05", Status: pkg.StatusPending}},
+		{1, pkg.Booking{Start: "2018-02-01", End: "2018-02-05", Status: pkg.StatusPending}},
+		{1, pkg.Booking{GuestID: 1, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusConfirmed}},
+	}
+	for i := range bookings {
+		if err := r.Add(ctx, bookings[i].room, &bookings[i].booking); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.Add(ctx, 1, &pkg.Booking{GuestID: 2, Start: "2018-04-01", End: "2018-04-02", Status: pkg.StatusPending}); err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+
+	got, err := r.GetByGuest(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []pkg.Booking{bookings[2].booking, bookings[0].booking}; !reflect.DeepEqual(got, want) {
+		t.Error("wrong bookings received: ", got)
+	}
+
+	got, err = r.GetByGuest(ctx, 2)
+	if err != nil || len(got) != 0 {
+		t.Error("wrong bookings received: ", got, err)
+	}
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestPropertiesSQLite(t *testing.T) {
+	ctx := context.Background()
+	r := NewPropertiesSQLite(newTestDB(t))
+
+	// the schema creates the property of the rooms added without one
+	def, err := r.GetByID(ctx, pkg.DefaultPropertyID)
+	if err != nil || def.Name != "Default" || def.Currency != "USD" {
+		t.Fatal("wrong default property: ", def, err)
+	}
+
+	seaside := pkg.Property{Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"}
+	err = r.Add(ctx, &seaside)
+	if err != nil || seaside.ID != 2 {
+		t.Fatal("wrong property saved: ", seaside, err)
+	}
+
+	seaside.Address = "2 Beach Road"
+	err = r.Update(ctx, seaside.ID, &seaside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.GetByID(ctx, seaside.ID)
+	if err != nil || *got != seaside {
+		t.Error("wrong property received: ", got, err)
+	}
+
+	err = r.Update(ctx, 7, &seaside)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	_, err = r.GetByID(ctx, 7)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	list, err := r.List(ctx)
+	if err != nil || !reflect.DeepEqual(list, []pkg.Property{*def, seaside}) {
+		t.Error("wrong properties received: ", list, err)
+	}
+}
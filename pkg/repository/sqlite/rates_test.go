@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestRatePlansSQLite_Add(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	addRooms(t, db, pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"})
+	r := NewRatePlansSQLite(db)
+
+	tests := []struct {
+		name    string
+		plan    pkg.RatePlan
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			plan: pkg.RatePlan{
+				RoomID: 1,
+				Name:   "Summer",
+				Start:  "2018-06-01",
+				End:    "2018-09-01",
+				Price:  pkg.Money{Amount: 700, Currency: "USD"},
+			},
+			want: 1,
+		},
+		{
+			name: "OK without dates",
+			plan: pkg.RatePlan{
+				RoomID:   1,
+				Name:     "Weekend",
+				Weekdays: 1<<time.Saturday | 1<<time.Sunday,
+				Price:    pkg.Money{Amount: 600, Currency: "USD"},
+			},
+			want: 2,
+		},
+		{
+			name:    "No Foreign Key",
+			plan:    pkg.RatePlan{RoomID: 2, Price: pkg.Money{Amount: 700, Currency: "USD"}},
+			wantErr: pkg.ErrNoForeignKey,
+		},
+		{
+			name:    "Price Check Fails",
+			plan:    pkg.RatePlan{RoomID: 1, Price: pkg.Money{Amount: -100, Currency: "USD"}},
+			wantErr: pkg.ErrPriceNotValid,
+		},
+		{
+			name: "Date Is Incorrect",
+			plan: pkg.RatePlan{
+				RoomID: 1,
+				Start:  "2018-09-01",
+				End:    "2018-06-01",
+				Price:  pkg.Money{Amount: 700, Currency: "USD"},
+			},
+			wantErr: pkg.ErrDateIsIncorrect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Add(ctx, &tt.plan)
+			if err != tt.wantErr {
+				t.Error(err)
+			} else if err == nil && tt.plan.ID != tt.want {
+				t.Error("wrong id received: ", tt.plan.ID)
+			}
+		})
+	}
+}
+
+func TestRatePlansSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	addRooms(t, db,
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+		pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-01"},
+		pkg.Room{Description: "Euro", Price: pkg.Money{Amount: 900, Currency: "EUR"}, Date: "2018-01-01"},
+	)
+	r := NewRatePlansSQLite(db)
+
+	plans := []pkg.RatePlan{
+		{RoomID: 2, Name: "Summer", Start: "2018-06-01", End: "2018-09-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+		{RoomID: 1, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 600, Currency: "USD"}, Priority: 1},
+		{RoomID: 2, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 1200, Currency: "USD"}},
+		{RoomID: 3, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 1000, Currency: "EUR"}},
+	}
+	for i := range plans {
+		if err := r.Add(ctx, &plans[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := r.Get(ctx, []int64{2, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []pkg.RatePlan{plans[1], plans[0], plans[2]}; !reflect.DeepEqual(got, want) {
+		t.Error("wrong plans received: ", got)
+	}
+
+	update := pkg.RatePlan{Name: "High season", Start: "2018-07-01", Price: pkg.Money{Amount: 1800, Currency: "USD"}, Priority: 2}
+	if err := r.Update(ctx, 1, &update); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := r.GetByID(ctx, 1)
+	update.ID, update.RoomID = 1, 2
+	if err != nil || !reflect.DeepEqual(*plan, update) {
+		t.Error("wrong plan saved: ", plan, err)
+	}
+
+	if err := r.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetByID(ctx, 3); err != pkg.ErrIDNotFound {
+		t.Error("plan is not deleted: ", err)
+	}
+
+	if err := r.Update(ctx, 9, &update); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Delete(ctx, 9); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if got, err := r.Get(ctx, nil); err != nil || len(got) != 0 {
+		t.Error("wrong plans received: ", got, err)
+	}
+}
@@ -0,0 +1,431 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type RoomSQLite struct {
+	db *sql.DB
+}
+
+func NewRoomSQLite(db *sql.DB) *RoomSQLite {
+	return &RoomSQLite{db: db}
+}
+
+// Uses fields: PropertyID, Description, Price, MaxAdults, MaxChildren, TypeID.
+// The room belongs to the tenant of the context.
+// On successful creation,
+// in the id field records the room id.
+func (r *RoomSQLite) Add(ctx context.Context, room *pkg.Room) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"INSERT INTO room (tenant_id, property_id, description, price_amount, price_currency, max_adults, max_children, type_id, date)"+
+			" VALUES (?, ?, ?, ?, ?, ?, ?, ?, "+today+")",
+		pkg.TenantFrom(ctx),
+		room.PropertyID,
+		room.Description,
+		room.Price.Amount,
+		room.Price.Currency,
+		room.MaxAdults,
+		room.MaxChildren,
+		nullID(room.TypeID),
+	)
+	if err != nil {
+		if errors.Is(sqliteError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	room.ID, err = res.LastInsertId()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	return nil
+}
+
+// Archives the room, its bookings stay in the history.
+// A room with bookings that have not ended yet is archived
+// only with force, their pending and confirmed bookings are cancelled.
+// Returns pkg.ErrRoomHasGuests while a guest is checked in, even with force,
+// such a room is archived only after the check-out.
+// Bookings that end after today, a YYYY-MM-DD date, have not ended.
+func (r *RoomSQLite) Delete(ctx context.Context, id int64, force bool, today string) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	defer tx.Rollback()
+
+	_, err = findRoom(ctx, tx, id)
+	if errors.Is(err, pkg.ErrNoForeignKey) {
+		return pkg.ErrIDNotFound
+	} else if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	checkedIn := false
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT id FROM bookings WHERE room_id = ? AND status = ?)",
+		id,
+		pkg.StatusCheckedIn,
+	).Scan(&checkedIn)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+	if checkedIn {
+		return pkg.ErrRoomHasGuests
+	}
+
+	future := false
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT id FROM bookings"+
+			"	WHERE room_id = ? AND date_end > ? AND "+activeBookings+")",
+		id,
+		today,
+	).Scan(&future)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	if future {
+		if !force {
+			return pkg.ErrRoomHasBookings
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			"UPDATE bookings SET status = ?"+
+				"	WHERE room_id = ? AND date_end > ? AND status IN (?, ?)",
+			pkg.StatusCancelled,
+			id,
+			today,
+			pkg.StatusPending,
+			pkg.StatusConfirmed,
+		)
+		if err != nil {
+			return pkg.ErrFailedDelete.Wrap(err)
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE room SET deleted_at = datetime('now') WHERE id = ?",
+		id,
+	)
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return pkg.ErrFailedDelete.Wrap(err)
+	}
+
+	return nil
+}
+
+// Returns the archived room to the lists,
+// restoring a room that is not archived does nothing.
+func (r *RoomSQLite) Restore(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE room SET deleted_at = NULL WHERE id = ? AND tenant_id = ?",
+		id,
+		pkg.TenantFrom(ctx),
+	)
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// Changes only the non-nil fields of the update.
+func (r *RoomSQLite) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	set := make([]string, 0, 5)
+	args := make([]interface{}, 0, 6)
+	if update.Description != nil {
+		set = append(set, "description = ?")
+		args = append(args, *update.Description)
+	}
+	if update.Price != nil {
+		set = append(set, "price_amount = ?", "price_currency = ?")
+		args = append(args, update.Price.Amount, update.Price.Currency)
+	}
+	if update.MaxAdults != nil {
+		set = append(set, "max_adults = ?")
+		args = append(args, *update.MaxAdults)
+	}
+	if update.MaxChildren != nil {
+		set = append(set, "max_children = ?")
+		args = append(args, *update.MaxChildren)
+	}
+	if len(set) == 0 {
+		return pkg.ErrNothingToUpdate
+	}
+
+	res, err := r.db.ExecContext(
+		ctx,
+		"UPDATE room SET "+strings.Join(set, ", ")+" WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL",
+		append(args, id, pkg.TenantFrom(ctx))...,
+	)
+	if err != nil {
+		if errors.Is(sqliteError(err), pkg.ErrPriceNotValid) {
+			return pkg.ErrPriceNotValid
+		}
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+
+	// SQLite counts matched rows,
+	// so unchanged values still affect the row
+	n, err := res.RowsAffected()
+	if err != nil {
+		return pkg.ErrFailedSave.Wrap(err)
+	}
+	if n == 0 {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// returns the property of the room,
+// archived rooms are also found
+func (r *RoomSQLite) GetProperty(ctx context.Context, id int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	var property int64
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT property_id FROM room WHERE id = ? AND tenant_id = ?",
+		id,
+		pkg.TenantFrom(ctx),
+	).Scan(&property)
+	if err == sql.ErrNoRows {
+		return 0, pkg.ErrIDNotFound
+	} else if err != nil {
+		return 0, pkg.ErrFailedGet.Wrap(err)
+	}
+
+	return property, nil
+}
+
+// returns the room if it is not archived
+func (r *RoomSQLite) GetByID(ctx context.Context, id int64) (*pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rooms, err := r.get(ctx, 1, roomColumns+" WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL", id, pkg.TenantFrom(ctx))
+	if err != nil {
+		return nil, pkg.ErrFailedGet.Wrap(err)
+	}
+	if len(rooms) == 0 {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return &rooms[0], nil
+}
+
+// reads the rows one by one, size is the expected number of rooms
+func (r *RoomSQLite) get(ctx context.Context, size int, query string, args ...interface{}) ([]pkg.Room, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rooms := make([]pkg.Room, 0, size)
+	for rows.Next() {
+		room := pkg.Room{}
+		var typeID sql.NullInt64
+		err = rows.Scan(
+			&room.ID,
+			&room.Date,
+			&room.Price.Amount,
+			&room.Price.Currency,
+			&room.Description,
+			&room.MaxAdults,
+			&room.MaxChildren,
+			&typeID,
+			&room.PropertyID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		room.TypeID = typeID.Int64
+		rooms = append(rooms, room)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+const roomColumns = "SELECT id, date, price_amount, price_currency, description," +
+	" max_adults, max_children, type_id, property_id FROM room"
+
+// returns the WHERE conditions of the filter and their arguments,
+// only the rooms of the tenant are selected, archived rooms are skipped
+func roomFilter(tenant int64, filter *pkg.RoomFilter) (string, []interface{}) {
+	where := " WHERE tenant_id = ? AND deleted_at IS NULL"
+	args := []interface{}{tenant}
+	if filter.Currency != "" {
+		where += " AND price_currency = ?"
+		args = append(args, filter.Currency)
+	}
+	if filter.MinPrice != nil {
+		where += " AND price_amount >= ?"
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		where += " AND price_amount <= ?"
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.Adults > 0 || filter.Children > 0 {
+		where += " AND " + roomFits
+		args = append(args, filter.Adults, filter.Adults+filter.Children)
+	}
+	if filter.TypeID != 0 {
+		where += " AND type_id = ?"
+		args = append(args, filter.TypeID)
+	}
+	if filter.PropertyID != 0 {
+		where += " AND property_id = ?"
+		args = append(args, filter.PropertyID)
+	}
+
+	return where, args
+}
+
+// columns the room list can be sorted by,
+// only these are put into the query,
+// prices are compared only within a currency
+var roomSortColumns = map[pkg.RoomSort]string{
+	pkg.SortByDate:  "date",
+	pkg.SortByPrice: "price_currency, price_amount",
+}
+
+// returns the ORDER BY clause of the query,
+// rooms with the same sort value are ordered by id
+func roomOrder(query *pkg.RoomQuery) (string, error) {
+	column, ok := roomSortColumns[query.Sort]
+	if !ok {
+		return "", pkg.ErrSortNotValid
+	}
+
+	order := " ORDER BY " + column
+	if query.Desc {
+		order += " DESC"
+	}
+	return order + ", id", nil
+}
+
+// returns one page of the filtered rooms, rooms with
+// the same sort value are ordered by id
+func (r *RoomSQLite) List(ctx context.Context, query pkg.RoomQuery) ([]pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	order, err := roomOrder(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := roomFilter(pkg.TenantFrom(ctx), &query.RoomFilter)
+	return r.get(
+		ctx,
+		query.Limit,
+		roomColumns+where+order+" LIMIT ? OFFSET ?",
+		append(args, query.Limit, query.Offset)...,
+	)
+}
+
+// returns the number of rooms matching the filter,
+// Limit and Offset are not used
+func (r *RoomSQLite) Count(ctx context.Context, filter *pkg.RoomFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	where, args := roomFilter(pkg.TenantFrom(ctx), filter)
+	var total int64
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM room"+where, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// condition of the rooms with places for the adults
+// and for all the guests, like pkg.Room.Fits
+const roomFits = "max_adults >= ? AND max_adults + max_children >= ?"
+
+// returns rooms of the tenant that have no bookings
+// overlapping with the range from start to end, the rooms
+// of a type are returned while the type has more such rooms
+// than bookings without a room on these dates,
+// the rooms are filtered and sorted like in List,
+// Limit and Offset are not used
+func (r *RoomSQLite) GetAvailable(ctx context.Context, start, end string, query pkg.RoomQuery) ([]pkg.Room, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	order, err := roomOrder(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := roomFilter(pkg.TenantFrom(ctx), &query.RoomFilter)
+	return r.get(
+		ctx,
+		0,
+		roomColumns+where+" AND NOT EXISTS (SELECT id FROM bookings"+
+			" WHERE bookings.room_id = room.id"+
+			" AND date_start < ? AND date_end > ? AND "+activeBookings+")"+
+			" AND "+typeHasRoom+order,
+		append(args, end, start, end, start, end, start)...,
+	)
+}
+
+// condition of the rooms without a type or whose type has more
+// rooms without bookings than bookings without a room,
+// takes end and start of the dates twice
+const typeHasRoom = "(room.type_id IS NULL OR (SELECT COUNT(*) FROM bookings" +
+	" WHERE bookings.type_id = room.type_id AND bookings.room_id IS NULL" +
+	" AND date_start < ? AND date_end > ? AND " + activeBookings + ")" +
+	" < (SELECT COUNT(*) FROM room AS free WHERE free.type_id = room.type_id AND free.deleted_at IS NULL" +
+	" AND NOT EXISTS (SELECT id FROM bookings WHERE bookings.room_id = free.id" +
+	" AND date_start < ? AND date_end > ? AND " + activeBookings + ")))"
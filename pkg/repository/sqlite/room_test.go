@@ -0,0 +1,480 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// opens a new database in a temporary file
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := NewSQLiteDB(&Config{Path: filepath.Join(t.TempDir(), "test.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// adds rooms with the dates, prices, capacity and types,
+// rooms without a property are added to the default one
+func addRooms(t *testing.T, db *sql.DB, rooms ...pkg.Room) {
+	for _, room := range rooms {
+		if room.PropertyID == 0 {
+			room.PropertyID = pkg.DefaultPropertyID
+		}
+		_, err := db.Exec(
+			"INSERT INTO room (property_id, description, price_amount, price_currency, max_adults, max_children, type_id, date)"+
+				" VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			room.PropertyID, room.Description, room.Price.Amount, room.Price.Currency, room.MaxAdults, room.MaxChildren, nullID(room.TypeID), room.Date,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRoomSQLite_Add(t *testing.T) {
+	r := NewRoomSQLite(newTestDB(t))
+
+	tests := []struct {
+		name    string
+		room    pkg.Room
+		want    int64
+		wantErr error
+	}{
+		{
+			name: "OK",
+			room: pkg.Room{PropertyID: 1, Description: "Good", Price: pkg.Money{Amount: 350, Currency: "USD"}},
+			want: 1,
+		},
+		{
+			name:    "Price Not Valid",
+			room:    pkg.Room{PropertyID: 1, Description: "Good", Price: pkg.Money{Amount: -100, Currency: "USD"}},
+			wantErr: pkg.ErrPriceNotValid,
+		},
+		{
+			name: "OK second",
+			room: pkg.Room{PropertyID: 1, Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}},
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Add(context.Background(), &tt.room)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if err == nil && tt.room.ID != tt.want {
+				t.Error("wrong id received: ", tt.room.ID)
+			}
+		})
+	}
+}
+
+func TestRoomSQLite_List(t *testing.T) {
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+	ctx := context.Background()
+
+	seaside := pkg.Property{Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "EUR"}
+	if err := NewPropertiesSQLite(db).Add(ctx, &seaside); err != nil {
+		t.Fatal(err)
+	}
+	addRooms(t, db,
+		pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-03"},
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+		pkg.Room{Description: "Cheap", Price: pkg.Money{Amount: 300, Currency: "USD"}, Date: "2018-01-02"},
+		pkg.Room{Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+		pkg.Room{PropertyID: seaside.ID, Description: "Euro", Price: pkg.Money{Amount: 400, Currency: "EUR"}, Date: "2018-01-05"},
+	)
+	err := r.Delete(ctx, 1, false, "2018-02-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var minPrice int64 = 400
+
+	tests := []struct {
+		name    string
+		query   pkg.RoomQuery
+		want    []pkg.Room
+		wantErr error
+	}{
+		{
+			name:  "OK date",
+			query: pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}},
+			want: []pkg.Room{
+				{ID: 2, PropertyID: 1, Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+				{ID: 3, PropertyID: 1, Description: "Cheap", Price: pkg.Money{Amount: 300, Currency: "USD"}, Date: "2018-01-02"},
+				{ID: 4, PropertyID: 1, Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+				{ID: 5, PropertyID: seaside.ID, Description: "Euro", Price: pkg.Money{Amount: 400, Currency: "EUR"}, Date: "2018-01-05"},
+			},
+		},
+		{
+			name: "OK price desc page grouped by currency",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByPrice,
+				Desc:       true,
+				RoomFilter: pkg.RoomFilter{Limit: 2, Offset: 2},
+			},
+			want: []pkg.Room{
+				{ID: 4, PropertyID: 1, Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+				{ID: 3, PropertyID: 1, Description: "Cheap", Price: pkg.Money{Amount: 300, Currency: "USD"}, Date: "2018-01-02"},
+			},
+		},
+		{
+			name: "OK price filter",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				Desc:       true,
+				RoomFilter: pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, Limit: 20},
+			},
+			want: []pkg.Room{
+				{ID: 4, PropertyID: 1, Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+				{ID: 2, PropertyID: 1, Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+			},
+		},
+		{
+			name: "OK guests",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				RoomFilter: pkg.RoomFilter{Adults: 1, Children: 2, Limit: 20},
+			},
+			want: []pkg.Room{
+				{ID: 4, PropertyID: 1, Description: "Good too", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-04", MaxAdults: 1, MaxChildren: 2},
+			},
+		},
+		{
+			// the room has three places, but only one for an adult
+			name: "OK too many adults",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByDate,
+				RoomFilter: pkg.RoomFilter{Adults: 3, Limit: 20},
+			},
+			want: []pkg.Room{},
+		},
+		{
+			name: "OK property",
+			query: pkg.RoomQuery{
+				Sort:       pkg.SortByPrice,
+				RoomFilter: pkg.RoomFilter{PropertyID: seaside.ID, Limit: 20},
+			},
+			want: []pkg.Room{
+				{ID: 5, PropertyID: seaside.ID, Description: "Euro", Price: pkg.Money{Amount: 400, Currency: "EUR"}, Date: "2018-01-05"},
+			},
+		},
+		{
+			name:    "Sort not valid",
+			query:   pkg.RoomQuery{Sort: "description"},
+			wantErr: pkg.ErrSortNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rooms, err := r.List(ctx, tt.query)
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+			if len(rooms) != len(tt.want) {
+				t.Fatal("wrong number of rooms received: ", rooms)
+			}
+			for i := range tt.want {
+				if rooms[i] != tt.want[i] {
+					t.Error("wrong rooms received: ", rooms)
+				}
+			}
+		})
+	}
+
+	total, err := r.Count(ctx, &pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice})
+	if err != nil || total != 2 {
+		t.Error("wrong total received: ", total, err)
+	}
+}
+
+func TestRoomSQLite_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		end        string
+		status     pkg.BookingStatus
+		force      bool
+		wantErr    error
+		wantStatus pkg.BookingStatus
+	}{
+		{
+			name:       "OK ended booking",
+			end:        "2018-01-20",
+			wantStatus: pkg.StatusPending,
+		},
+		{
+			name:       "Has bookings",
+			end:        "2018-02-10",
+			wantErr:    pkg.ErrRoomHasBookings,
+			wantStatus: pkg.StatusPending,
+		},
+		{
+			name:       "OK force",
+			end:        "2018-02-10",
+			force:      true,
+			wantStatus: pkg.StatusCancelled,
+		},
+		{
+			name:       "Has guests",
+			end:        "2018-02-10",
+			status:     pkg.StatusCheckedIn,
+			force:      true,
+			wantErr:    pkg.ErrRoomHasGuests,
+			wantStatus: pkg.StatusCheckedIn,
+		},
+		{
+			name:       "Has guests leaving today",
+			end:        "2018-02-01",
+			status:     pkg.StatusCheckedIn,
+			wantErr:    pkg.ErrRoomHasGuests,
+			wantStatus: pkg.StatusCheckedIn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			r := NewRoomSQLite(db)
+			b := NewBookingsSQLite(db)
+
+			addRooms(t, db, pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"})
+			status := pkg.StatusPending
+			if tt.status != "" {
+				status = tt.status
+			}
+			err := b.Add(ctx, 1, &pkg.Booking{Start: "2018-01-15", End: tt.end, Status: status})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = r.Delete(ctx, 1, tt.force, "2018-02-01")
+			if err != tt.wantErr {
+				t.Fatal(err)
+			}
+
+			bookings, err := b.Get(ctx, 1, nil)
+			if err != nil || bookings[0].Status != tt.wantStatus {
+				t.Error("wrong booking status: ", bookings, err)
+			}
+
+			total, _ := r.Count(ctx, &pkg.RoomFilter{})
+			if archived := total == 0; archived != (tt.wantErr == nil) {
+				t.Error("room archived incorrectly")
+			}
+		})
+	}
+
+	r := NewRoomSQLite(newTestDB(t))
+	if err := r.Delete(ctx, 5, false, "2018-02-01"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomSQLite_Update(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+	addRooms(t, db, pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"})
+
+	price := pkg.Money{Amount: 700, Currency: "EUR"}
+	negative := pkg.Money{Amount: -100, Currency: "USD"}
+	description := "VIP"
+
+	tests := []struct {
+		name    string
+		id      int64
+		update  pkg.RoomUpdate
+		wantErr error
+	}{
+		{
+			name:   "OK",
+			id:     1,
+			update: pkg.RoomUpdate{Description: &description, Price: &price},
+		},
+		{
+			name:   "OK same values",
+			id:     1,
+			update: pkg.RoomUpdate{Price: &price},
+		},
+		{
+			name:    "Price Not Valid",
+			id:      1,
+			update:  pkg.RoomUpdate{Price: &negative},
+			wantErr: pkg.ErrPriceNotValid,
+		},
+		{
+			name:    "Nothing To Update",
+			id:      1,
+			wantErr: pkg.ErrNothingToUpdate,
+		},
+		{
+			name:    "ID Not Found",
+			id:      2,
+			update:  pkg.RoomUpdate{Price: &price},
+			wantErr: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Update(ctx, tt.id, &tt.update)
+			if err != tt.wantErr {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRoomSQLite_GetByID(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+	addRooms(t, db,
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"},
+		pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "EUR"}, Date: "2018-01-02"},
+	)
+	if err := r.Delete(ctx, 1, false, "2018-02-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	room, err := r.GetByID(ctx, 2)
+	want := pkg.Room{ID: 2, PropertyID: 1, Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "EUR"}, Date: "2018-01-02"}
+	if err != nil || *room != want {
+		t.Error("wrong room received: ", room, err)
+	}
+
+	for _, id := range []int64{1, 3} {
+		if _, err := r.GetByID(ctx, id); err != pkg.ErrIDNotFound {
+			t.Error("incorrect error received: ", err)
+		}
+	}
+}
+
+func TestRoomSQLite_GetAvailable(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+	addRooms(t, db,
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01", MaxAdults: 2, MaxChildren: 1},
+		pkg.Room{Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-02", MaxAdults: 2},
+	)
+	err := NewBookingsSQLite(db).Add(ctx, 1, &pkg.Booking{
+		Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusPending,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rooms, err := r.GetAvailable(ctx, "2018-02-05", "2018-02-07", pkg.RoomQuery{Sort: pkg.SortByPrice})
+	if err != nil || len(rooms) != 1 || rooms[0].ID != 2 {
+		t.Error("wrong rooms received: ", rooms, err)
+	}
+
+	rooms, err = r.GetAvailable(ctx, "2018-02-10", "2018-02-12", pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true})
+	if err != nil || len(rooms) != 2 || rooms[0].ID != 2 {
+		t.Error("wrong rooms received: ", rooms, err)
+	}
+
+	rooms, err = r.GetAvailable(ctx, "2018-02-10", "2018-02-12", pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{Adults: 2, Children: 1}})
+	if err != nil || len(rooms) != 1 || rooms[0].ID != 1 || rooms[0].MaxChildren != 1 {
+		t.Error("wrong rooms received: ", rooms, err)
+	}
+
+	rooms, err = r.GetAvailable(ctx, "2018-02-10", "2018-02-12", pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{PropertyID: 2}})
+	if err != nil || len(rooms) != 0 {
+		t.Error("rooms of another property received: ", rooms, err)
+	}
+}
+
+func TestRoomSQLite_GetProperty(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+	addRooms(t, db, pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01"})
+	if err := r.Delete(ctx, 1, false, "2018-02-01"); err != nil {
+		t.Fatal(err)
+	}
+
+	property, err := r.GetProperty(ctx, 1)
+	if err != nil || property != pkg.DefaultPropertyID {
+		t.Error("wrong property of the archived room: ", property, err)
+	}
+
+	_, err = r.GetProperty(ctx, 2)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomSQLite_Tenants(t *testing.T) {
+	ctx := context.Background()
+	other := pkg.WithTenant(ctx, 2)
+	db := newTestDB(t)
+	r := NewRoomSQLite(db)
+
+	seaside := pkg.Property{Name: "Seaside", Timezone: "UTC", Currency: "USD"}
+	if err := NewPropertiesSQLite(db).Add(other, &seaside); err != nil {
+		t.Fatal(err)
+	}
+	room := pkg.Room{PropertyID: seaside.ID, Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}}
+	if err := r.Add(other, &room); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.GetByID(other, room.ID); err != nil {
+		t.Error("the room is not found in its tenant: ", err)
+	}
+
+	// the room of the second tenant is not seen by the first one
+	if _, err := r.GetByID(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if _, err := r.GetProperty(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	description := "VIP"
+	if err := r.Update(ctx, room.ID, &pkg.RoomUpdate{Description: &description}); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Delete(ctx, room.ID, true, "2018-02-01"); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := r.Restore(ctx, room.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	rooms, err := r.List(ctx, pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{Limit: 20}})
+	if err != nil || len(rooms) != 0 {
+		t.Error("wrong rooms received: ", rooms, err)
+	}
+	total, err := r.Count(ctx, &pkg.RoomFilter{})
+	if err != nil || total != 0 {
+		t.Error("wrong total received: ", total, err)
+	}
+	rooms, err = r.GetAvailable(ctx, "2018-02-03", "2018-02-10", pkg.RoomQuery{Sort: pkg.SortByDate})
+	if err != nil || len(rooms) != 0 {
+		t.Error("wrong available rooms received: ", rooms, err)
+	}
+
+	b := pkg.Booking{Start: "2018-02-03", End: "2018-02-10"}
+	if err := NewBookingsSQLite(db).Add(ctx, room.ID, &b); err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := NewBookingsSQLite(db).Add(other, room.ID, &b); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewBookingsSQLite(db).GetByID(ctx, b.ID); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+}
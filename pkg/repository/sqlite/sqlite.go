@@ -0,0 +1,193 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+type Config struct {
+	// path to the database file, it is created if it does not exist
+	Path string
+}
+
+// schema of a new database, dates are kept as YYYY-MM-DD text,
+// so they are compared as strings like in the other repositories,
+// prices are kept in minor units of the currency,
+// every row belongs to a tenant, the first one by default
+const schema = `
+CREATE TABLE IF NOT EXISTS properties (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  name            TEXT NOT NULL,
+  address         TEXT NOT NULL DEFAULT '',
+  timezone        TEXT NOT NULL,
+  currency        TEXT NOT NULL
+);
+
+INSERT OR IGNORE INTO properties (id, name, timezone, currency) VALUES (1, 'Default', 'UTC', 'USD');
+
+CREATE TABLE IF NOT EXISTS room_types (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  property_id     INTEGER NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT,
+  name            TEXT NOT NULL,
+  description     TEXT NOT NULL,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  max_adults      INTEGER NOT NULL DEFAULT 2,
+  max_children    INTEGER NOT NULL DEFAULT 0,
+
+  CONSTRAINT room_types_price_check CHECK (price_amount >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS room (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  property_id     INTEGER NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT,
+  description     TEXT NOT NULL,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  date            TEXT NOT NULL,
+  max_adults      INTEGER NOT NULL DEFAULT 2,
+  max_children    INTEGER NOT NULL DEFAULT 0,
+  type_id         INTEGER NULL REFERENCES room_types (id) ON DELETE RESTRICT,
+  deleted_at      TEXT NULL,
+
+  CONSTRAINT room_price_check CHECK (price_amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS room_price_date ON room (price_currency, price_amount, date);
+CREATE INDEX IF NOT EXISTS room_type ON room (type_id);
+CREATE INDEX IF NOT EXISTS room_property ON room (property_id, date);
+CREATE INDEX IF NOT EXISTS room_types_property ON room_types (property_id);
+CREATE INDEX IF NOT EXISTS room_tenant ON room (tenant_id, date);
+CREATE INDEX IF NOT EXISTS room_types_tenant ON room_types (tenant_id);
+CREATE INDEX IF NOT EXISTS properties_tenant ON properties (tenant_id);
+
+CREATE TABLE IF NOT EXISTS guests (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  name            TEXT NOT NULL,
+  email           TEXT NOT NULL,
+  phone           TEXT NOT NULL DEFAULT '',
+  document_number TEXT NOT NULL DEFAULT '',
+
+  CONSTRAINT guests_email UNIQUE (tenant_id, email)
+);
+
+CREATE TABLE IF NOT EXISTS bookings (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  room_id         INTEGER NULL REFERENCES room (id) ON DELETE RESTRICT,
+  type_id         INTEGER NULL REFERENCES room_types (id) ON DELETE RESTRICT,
+  guest_id        INTEGER NULL REFERENCES guests (id) ON DELETE RESTRICT,
+  adults          INTEGER NOT NULL DEFAULT 1,
+  children        INTEGER NOT NULL DEFAULT 0,
+  date_start      TEXT NOT NULL,
+  date_end        TEXT NOT NULL,
+  status          TEXT NOT NULL DEFAULT 'pending',
+  total_amount    INTEGER NULL,
+  total_currency  TEXT NULL,
+
+  CONSTRAINT bookings_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX IF NOT EXISTS bookings_room_date ON bookings (room_id, date_start);
+CREATE INDEX IF NOT EXISTS bookings_guest_date ON bookings (guest_id, date_start);
+CREATE INDEX IF NOT EXISTS bookings_type_date ON bookings (type_id, date_start);
+
+CREATE TABLE IF NOT EXISTS rate_plans (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  room_id         INTEGER NOT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  name            TEXT NOT NULL,
+  date_start      TEXT NULL,
+  date_end        TEXT NULL,
+  weekdays        INTEGER NOT NULL DEFAULT 0,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  priority        INTEGER NOT NULL DEFAULT 0,
+
+  CONSTRAINT rate_plans_price_check CHECK (price_amount >= 0),
+  CONSTRAINT rate_plans_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX IF NOT EXISTS rate_plans_room ON rate_plans (room_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL,
+  role            TEXT NOT NULL,
+  key_hash        TEXT NOT NULL UNIQUE,
+  created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+  revoked_at      TEXT NULL
+);
+`
+
+// Opens the database file and creates the tables,
+// the tables of an older file are upgraded.
+// Foreign keys are enforced on every connection,
+// write transactions take the database lock when they begin,
+// so bookings of the same room are checked one by one.
+func NewSQLiteDB(cfg *Config) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+cfg.Path+
+		"?_foreign_keys=on&_txlock=immediate&_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+
+	err = migrate(context.Background(), db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// deadlines of single operations, the request
+// context can only make them shorter
+const (
+	readTimeout  = 3 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// current date of the server, like CURDATE() in MySQL
+const today = "date('now', 'localtime')"
+
+// converts SQLite constraint errors to the pkg errors,
+// other errors are returned as is
+func sqliteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintForeignKey:
+		return pkg.ErrNoForeignKey
+	case sqlite3.ErrConstraintUnique:
+		if strings.Contains(sqliteErr.Error(), "guests.email") {
+			return pkg.ErrGuestExists
+		}
+	case sqlite3.ErrConstraintCheck:
+		switch {
+		case strings.Contains(sqliteErr.Error(), "room_price_check"),
+			strings.Contains(sqliteErr.Error(), "room_types_price_check"),
+			strings.Contains(sqliteErr.Error(), "rate_plans_price_check"):
+			return pkg.ErrPriceNotValid
+		case strings.Contains(sqliteErr.Error(), "bookings_dates_check"),
+			strings.Contains(sqliteErr.Error(), "rate_plans_dates_check"):
+			return pkg.ErrDateIsIncorrect
+		}
+	}
+
+	return err
+}
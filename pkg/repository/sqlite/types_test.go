@@ -0,0 +1,152 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+func TestRoomTypesSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	r := NewRoomTypesSQLite(db)
+
+	seaside := pkg.Property{Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "USD"}
+	if err := NewPropertiesSQLite(db).Add(ctx, &seaside); err != nil {
+		t.Fatal(err)
+	}
+
+	single := pkg.RoomType{PropertyID: 1, Name: "Single", Price: pkg.Money{Amount: 8000, Currency: "USD"}, MaxAdults: 1}
+	double := pkg.RoomType{PropertyID: seaside.ID, Name: "Double", Description: "two beds", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2}
+	for _, rt := range []*pkg.RoomType{&single, &double} {
+		if err := r.Add(ctx, rt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if single.ID != 1 || double.ID != 2 {
+		t.Fatal("wrong ids received: ", single.ID, double.ID)
+	}
+
+	err := r.Add(ctx, &pkg.RoomType{PropertyID: 1, Name: "Free", Price: pkg.Money{Amount: -1, Currency: "USD"}})
+	if err != pkg.ErrPriceNotValid {
+		t.Error("incorrect error received: ", err)
+	}
+
+	addRooms(t, db,
+		pkg.Room{PropertyID: seaside.ID, Description: "two beds", Price: double.Price, Date: "2018-01-01", MaxAdults: 2, TypeID: double.ID},
+		pkg.Room{Description: "Good", Price: pkg.Money{Amount: 500, Currency: "USD"}, Date: "2018-01-01", MaxAdults: 2},
+	)
+
+	double.Description = "two beds, sea view"
+	double.Price.Amount = 15000
+	double.MaxChildren = 1
+	err = r.Update(ctx, double.ID, &double)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rooms := NewRoomSQLite(db)
+	room, err := rooms.GetByID(ctx, 1)
+	want := pkg.Room{ID: 1, PropertyID: seaside.ID, TypeID: double.ID, Date: "2018-01-01", Description: "two beds, sea view", Price: double.Price, MaxAdults: 2, MaxChildren: 1}
+	if err != nil || *room != want {
+		t.Error("wrong room of the type: ", room, err)
+	}
+	room, err = rooms.GetByID(ctx, 2)
+	if err != nil || room.Description != "Good" {
+		t.Error("room without a type changed: ", room, err)
+	}
+
+	err = r.Update(ctx, 7, &double)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	got, err := r.GetByID(ctx, double.ID)
+	if err != nil || *got != double {
+		t.Error("wrong type received: ", got, err)
+	}
+	_, err = r.GetByID(ctx, 7)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	list, err := r.List(ctx, 0)
+	if err != nil || !reflect.DeepEqual(list, []pkg.RoomType{single, double}) {
+		t.Error("wrong types received: ", list, err)
+	}
+	list, err = r.List(ctx, seaside.ID)
+	if err != nil || !reflect.DeepEqual(list, []pkg.RoomType{double}) {
+		t.Error("wrong types of the property received: ", list, err)
+	}
+}
+
+func TestRoomTypesSQLite_Tenants(t *testing.T) {
+	ctx := context.Background()
+	other := pkg.WithTenant(ctx, 2)
+	db := newTestDB(t)
+	types := NewRoomTypesSQLite(db)
+	rooms := NewRoomSQLite(db)
+	bookings := NewBookingsSQLite(db)
+
+	double := pkg.RoomType{PropertyID: pkg.DefaultPropertyID, Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2}
+	if err := types.Add(ctx, &double); err != nil {
+		t.Fatal(err)
+	}
+	addRooms(t, db, pkg.Room{Description: "two beds", Price: double.Price, Date: "2018-01-01", MaxAdults: 2, TypeID: double.ID})
+
+	// the second tenant has rooms and a booking on the same type id
+	seaside := pkg.Property{Name: "Seaside", Timezone: "UTC", Currency: "USD"}
+	if err := NewPropertiesSQLite(db).Add(other, &seaside); err != nil {
+		t.Fatal(err)
+	}
+	twin := pkg.RoomType{PropertyID: seaside.ID, Name: "Twin", Price: pkg.Money{Amount: 9000, Currency: "USD"}, MaxAdults: 2}
+	if err := types.Add(other, &twin); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		room := pkg.Room{PropertyID: seaside.ID, Description: "twin beds", Price: twin.Price, MaxAdults: 2, TypeID: twin.ID}
+		if err := rooms.Add(other, &room); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stay := pkg.Booking{TypeID: twin.ID, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending}
+	if err := bookings.Add(other, 0, &stay); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"room", "bookings"} {
+		_, err := db.Exec("UPDATE "+table+" SET type_id = ? WHERE tenant_id = 2", double.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the rooms of the second tenant do not free the type of the first one
+	first := pkg.Booking{TypeID: double.ID, Start: "2018-02-03", End: "2018-02-05", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &first); err != nil {
+		t.Fatal(err)
+	}
+	second := pkg.Booking{TypeID: double.ID, Start: "2018-02-03", End: "2018-02-05", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &second); err != pkg.ErrBookingConflict {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the booking of the second tenant does not take the room of the first one
+	third := pkg.Booking{TypeID: double.ID, Start: "2018-02-10", End: "2018-02-12", Status: pkg.StatusPending}
+	if err := bookings.Add(ctx, 0, &third); err != nil {
+		t.Error(err)
+	}
+
+	// the update of the type does not rewrite the rooms of the second tenant
+	double.Description = "two beds, sea view"
+	if err := types.Update(ctx, double.ID, &double); err != nil {
+		t.Fatal(err)
+	}
+	for id := int64(2); id <= 3; id++ {
+		room, err := rooms.GetByID(other, id)
+		if err != nil || room.Description != "twin beds" || room.Price != twin.Price {
+			t.Error("room of the second tenant changed: ", room, err)
+		}
+	}
+}
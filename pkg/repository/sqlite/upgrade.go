@@ -0,0 +1,336 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// a step of the schema, done is a query that returns a row
+// if the database already has the step
+type upgrade struct {
+	done string
+	up   string
+}
+
+func hasTable(table string) string {
+	return "SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = '" + table + "'"
+}
+
+func hasColumn(table, column string) string {
+	return "SELECT 1 FROM pragma_table_info('" + table + "') WHERE name = '" + column + "'"
+}
+
+// steps from the first schema of the SQLite backend to the current one,
+// the step i upgrades a database of the version i to the version i+1,
+// the version is kept in PRAGMA user_version.
+// SQLite can not change a column or a constraint, such tables
+// are copied to a new table that takes the name of the old one.
+var upgrades = []upgrade{
+	// the old prices are converted to USD cents
+	{
+		done: hasColumn("room", "price_amount"),
+		up: `
+CREATE TABLE room_new (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  description     TEXT NOT NULL,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  date            TEXT NOT NULL,
+  deleted_at      TEXT NULL,
+
+  CONSTRAINT room_price_check CHECK (price_amount >= 0)
+);
+
+INSERT INTO room_new (id, description, price_amount, price_currency, date, deleted_at)
+  SELECT id, description, CAST(ROUND(price * 100) AS INTEGER), 'USD', date, deleted_at FROM room;
+
+DROP TABLE room;
+ALTER TABLE room_new RENAME TO room;
+
+CREATE INDEX room_price_date ON room (price_currency, price_amount, date);
+`,
+	},
+	{
+		done: hasColumn("bookings", "total_amount"),
+		up: `
+ALTER TABLE bookings ADD COLUMN total_amount INTEGER NULL;
+ALTER TABLE bookings ADD COLUMN total_currency TEXT NULL;
+`,
+	},
+	{
+		done: hasTable("rate_plans"),
+		up: `
+CREATE TABLE rate_plans (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  room_id         INTEGER NOT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  name            TEXT NOT NULL,
+  date_start      TEXT NULL,
+  date_end        TEXT NULL,
+  weekdays        INTEGER NOT NULL DEFAULT 0,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  priority        INTEGER NOT NULL DEFAULT 0,
+
+  CONSTRAINT rate_plans_price_check CHECK (price_amount >= 0),
+  CONSTRAINT rate_plans_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX rate_plans_room ON rate_plans (room_id);
+`,
+	},
+	{
+		done: hasTable("guests"),
+		up: `
+CREATE TABLE guests (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  name            TEXT NOT NULL,
+  email           TEXT NOT NULL,
+  phone           TEXT NOT NULL DEFAULT '',
+  document_number TEXT NOT NULL DEFAULT '',
+
+  CONSTRAINT guests_email UNIQUE (email)
+);
+
+ALTER TABLE bookings ADD COLUMN guest_id INTEGER NULL REFERENCES guests (id) ON DELETE RESTRICT;
+
+CREATE INDEX bookings_guest_date ON bookings (guest_id, date_start);
+`,
+	},
+	{
+		done: hasColumn("room", "max_adults"),
+		up: `
+ALTER TABLE room ADD COLUMN max_adults INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE room ADD COLUMN max_children INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN adults INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE bookings ADD COLUMN children INTEGER NOT NULL DEFAULT 0;
+`,
+	},
+	// the room of a booking becomes optional
+	{
+		done: hasTable("room_types"),
+		up: `
+CREATE TABLE room_types (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  name            TEXT NOT NULL,
+  description     TEXT NOT NULL,
+  price_amount    INTEGER NOT NULL,
+  price_currency  TEXT NOT NULL,
+  max_adults      INTEGER NOT NULL DEFAULT 2,
+  max_children    INTEGER NOT NULL DEFAULT 0,
+
+  CONSTRAINT room_types_price_check CHECK (price_amount >= 0)
+);
+
+ALTER TABLE room ADD COLUMN type_id INTEGER NULL REFERENCES room_types (id) ON DELETE RESTRICT;
+
+CREATE INDEX room_type ON room (type_id);
+
+CREATE TABLE bookings_new (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  room_id         INTEGER NULL REFERENCES room (id) ON DELETE RESTRICT,
+  type_id         INTEGER NULL REFERENCES room_types (id) ON DELETE RESTRICT,
+  guest_id        INTEGER NULL REFERENCES guests (id) ON DELETE RESTRICT,
+  adults          INTEGER NOT NULL DEFAULT 1,
+  children        INTEGER NOT NULL DEFAULT 0,
+  date_start      TEXT NOT NULL,
+  date_end        TEXT NOT NULL,
+  status          TEXT NOT NULL DEFAULT 'pending',
+  total_amount    INTEGER NULL,
+  total_currency  TEXT NULL,
+
+  CONSTRAINT bookings_dates_check CHECK (date_end > date_start)
+);
+
+INSERT INTO bookings_new (id, room_id, guest_id, adults, children, date_start, date_end, status, total_amount, total_currency)
+  SELECT id, room_id, guest_id, adults, children, date_start, date_end, status, total_amount, total_currency FROM bookings;
+
+DROP TABLE bookings;
+ALTER TABLE bookings_new RENAME TO bookings;
+
+CREATE INDEX bookings_room_date ON bookings (room_id, date_start);
+CREATE INDEX bookings_guest_date ON bookings (guest_id, date_start);
+CREATE INDEX bookings_type_date ON bookings (type_id, date_start);
+`,
+	},
+	// the existing rooms and types belong to the default property
+	{
+		done: hasTable("properties"),
+		up: `
+CREATE TABLE properties (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  name            TEXT NOT NULL,
+  address         TEXT NOT NULL DEFAULT '',
+  timezone        TEXT NOT NULL,
+  currency        TEXT NOT NULL
+);
+
+INSERT INTO properties (id, name, timezone, currency) VALUES (1, 'Default', 'UTC', 'USD');
+
+ALTER TABLE room_types ADD COLUMN property_id INTEGER NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT;
+ALTER TABLE room ADD COLUMN property_id INTEGER NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT;
+
+CREATE INDEX room_property ON room (property_id, date);
+CREATE INDEX room_types_property ON room_types (property_id);
+`,
+	},
+	// the existing rows belong to the first tenant,
+	// the same email can be used by the guests of different tenants
+	{
+		done: hasColumn("room", "tenant_id"),
+		up: `
+ALTER TABLE properties ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE room_types ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE room ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE bookings ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE rate_plans ADD COLUMN tenant_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE guests_new (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL DEFAULT 1,
+  name            TEXT NOT NULL,
+  email           TEXT NOT NULL,
+  phone           TEXT NOT NULL DEFAULT '',
+  document_number TEXT NOT NULL DEFAULT '',
+
+  CONSTRAINT guests_email UNIQUE (tenant_id, email)
+);
+
+INSERT INTO guests_new (id, name, email, phone, document_number)
+  SELECT id, name, email, phone, document_number FROM guests;
+
+DROP TABLE guests;
+ALTER TABLE guests_new RENAME TO guests;
+
+CREATE INDEX room_tenant ON room (tenant_id, date);
+CREATE INDEX room_types_tenant ON room_types (tenant_id);
+CREATE INDEX properties_tenant ON properties (tenant_id);
+`,
+	},
+	{
+		done: hasTable("api_keys"),
+		up: `
+CREATE TABLE api_keys (
+  id              INTEGER PRIMARY KEY AUTOINCREMENT,
+  tenant_id       INTEGER NOT NULL,
+  role            TEXT NOT NULL,
+  key_hash        TEXT NOT NULL UNIQUE,
+  created_at      TEXT NOT NULL DEFAULT (datetime('now')),
+  revoked_at      TEXT NULL
+);
+`,
+	},
+}
+
+// Creates the schema in a new database, an older database
+// gets the steps it does not have, each one in a transaction.
+// Foreign keys are off while the tables are copied,
+// the step is committed only if all rows still match them.
+func migrate(ctx context.Context, db *sql.DB) error {
+	// the pragmas are set on one connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	version, err := schemaVersion(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if version < 0 {
+		_, err = conn.ExecContext(ctx, schema+versionPragma(len(upgrades)))
+		return err
+	}
+	if version > len(upgrades) {
+		return fmt.Errorf("database schema version %d is newer than this build", version)
+	}
+	if version == len(upgrades) {
+		// the version of a database found by its steps is kept
+		_, err = conn.ExecContext(ctx, versionPragma(version))
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		return err
+	}
+	// the connection goes back to the pool
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	for ; version < len(upgrades); version++ {
+		err = step(ctx, conn, version)
+		if err != nil {
+			return fmt.Errorf("schema upgrade to version %d: %w", version+1, err)
+		}
+	}
+
+	return nil
+}
+
+// returns the version of the schema, -1 for a new database.
+// A database created before the version was kept has
+// the version 0, it is found by the steps it already has.
+func schemaVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	var version int
+	err := conn.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+	if err != nil || version > 0 {
+		return version, err
+	}
+
+	var one int
+	err = conn.QueryRowContext(ctx, hasTable("room")).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	for version = len(upgrades); version > 0; version-- {
+		err = conn.QueryRowContext(ctx, upgrades[version-1].done).Scan(&one)
+		if err == nil {
+			break
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	return version, nil
+}
+
+// runs the step of the version in a transaction
+func step(ctx context.Context, conn *sql.Conn, version int) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, upgrades[version].up)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	broken := rows.Next()
+	rows.Close()
+	if broken {
+		return errors.New("rows do not match the foreign keys")
+	}
+
+	_, err = tx.ExecContext(ctx, versionPragma(version+1))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PRAGMA does not take query arguments
+func versionPragma(version int) string {
+	return fmt.Sprintf("PRAGMA user_version = %d;", version)
+}
@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// the schema of the first SQLite backend, before the version was kept
+const firstSchema = `
+CREATE TABLE room (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  description  TEXT NOT NULL,
+  price        REAL NOT NULL,
+  date         TEXT NOT NULL,
+  deleted_at   TEXT NULL,
+
+  CONSTRAINT room_price_check CHECK (price >= 0)
+);
+
+CREATE INDEX room_price_date ON room (price, date);
+
+CREATE TABLE bookings (
+  id           INTEGER PRIMARY KEY AUTOINCREMENT,
+  room_id      INTEGER NOT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  date_start   TEXT NOT NULL,
+  date_end     TEXT NOT NULL,
+  status       TEXT NOT NULL DEFAULT 'pending',
+
+  CONSTRAINT bookings_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX bookings_room_date ON bookings (room_id, date_start);
+
+INSERT INTO room (description, price, date) VALUES ('Good', 12.5, '2018-01-01');
+INSERT INTO bookings (room_id, date_start, date_end, status) VALUES (1, '2018-02-03', '2018-02-10', 'confirmed');
+`
+
+// creates a database file with the statements
+// like an older version of the backend
+func oldDB(t *testing.T, statements string) string {
+	path := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(statements)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func userVersion(t *testing.T, db *sql.DB) int {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+// columns and indexes of the database, the order
+// of the columns added by ALTER TABLE is not compared
+func tables(t *testing.T, db *sql.DB) []string {
+	rows, err := db.Query(`
+SELECT m.name || '.' || p.name || ' ' || p.type || ' ' || p."notnull" || ' ' || p.pk || ' ' || COALESCE(p.dflt_value, '')
+  FROM sqlite_master m, pragma_table_info(m.name) p WHERE m.type = 'table'
+UNION ALL
+SELECT tbl_name || ' ' || name FROM sqlite_master WHERE type = 'index' AND name NOT LIKE 'sqlite_%'
+ORDER BY 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		err = rows.Scan(&column)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns = append(columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return columns
+}
+
+func TestNewSQLiteDB_Upgrade(t *testing.T) {
+	db, err := NewSQLiteDB(&Config{Path: oldDB(t, firstSchema)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if v := userVersion(t, db); v != len(upgrades) {
+		t.Error("wrong schema version: ", v)
+	}
+
+	newDB, err := NewSQLiteDB(&Config{Path: filepath.Join(t.TempDir(), "new.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer newDB.Close()
+
+	if got, want := tables(t, db), tables(t, newDB); !reflect.DeepEqual(got, want) {
+		t.Errorf("upgraded schema %v, want %v", got, want)
+	}
+
+	var amount int64
+	var currency string
+	err = db.QueryRow("SELECT price_amount, price_currency FROM room WHERE id = 1").Scan(&amount, &currency)
+	if err != nil || amount != 1250 || currency != "USD" {
+		t.Error("wrong room price: ", amount, currency, err)
+	}
+
+	var room int64
+	var status string
+	err = db.QueryRow("SELECT room_id, status FROM bookings WHERE id = 1").Scan(&room, &status)
+	if err != nil || room != 1 || status != "confirmed" {
+		t.Error("wrong booking: ", room, status, err)
+	}
+
+	_, err = db.Exec("INSERT INTO bookings (room_id, date_start, date_end) VALUES (7, '2018-03-10', '2018-03-12')")
+	if err == nil {
+		t.Error("foreign keys are not enforced")
+	}
+}
+
+func TestNewSQLiteDB_Version(t *testing.T) {
+	// a database of the current schema created before the version was kept
+	db, err := NewSQLiteDB(&Config{Path: oldDB(t, schema)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if v := userVersion(t, db); v != len(upgrades) {
+		t.Error("wrong schema version: ", v)
+	}
+
+	_, err = db.Exec(versionPragma(len(upgrades) + 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = migrate(context.Background(), db)
+	if err == nil {
+		t.Error("schema of a newer build is accepted")
+	}
+}
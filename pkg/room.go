@@ -1,8 +1,84 @@
 package pkg
 
+// Room.StayPrice is the price of the stay asked for in the room list,
+// it is not saved with the room.
+// Children may also take the places of adults.
+// A room of a type takes the description, price and capacity
+// of the type, a zero TypeID is a room without a type.
+// The room and its type belong to the same property.
 type Room struct {
-	ID          int64   `json:"room_id"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Date        string  `json:"date"`
+	ID          int64  `json:"room_id"`
+	PropertyID  int64  `json:"property_id"`
+	TypeID      int64  `json:"type_id,omitempty"`
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+	Date        string `json:"date"`
+	MaxAdults   int    `json:"max_adults"`
+	MaxChildren int    `json:"max_children"`
+	StayPrice   *Money `json:"stay_price,omitempty"`
+}
+
+// DefaultMaxAdults is the capacity of the rooms added without it.
+const DefaultMaxAdults = 2
+
+// reports whether the room has places for the guests
+func (r *Room) Fits(adults, children int) bool {
+	return adults <= r.MaxAdults && adults+children <= r.MaxAdults+r.MaxChildren
+}
+
+// RoomUpdate contains the fields of the room to be changed,
+// nil fields are left unchanged.
+type RoomUpdate struct {
+	Description *string `json:"description"`
+	Price       *Money  `json:"price"`
+	MaxAdults   *int    `json:"max_adults"`
+	MaxChildren *int    `json:"max_children"`
+}
+
+// RoomFilter selects one page of the room list.
+// An empty currency selects rooms in all currencies,
+// prices are minor units of the currency, nil prices are not checked.
+// Adults and Children select the rooms that fit them like Room.Fits,
+// zero adults and children are not checked.
+// A zero TypeID selects rooms of all types and without a type,
+// a zero PropertyID selects rooms of all properties.
+// StayStart and StayEnd do not filter the rooms, the rooms of the page
+// are priced for the stay between them.
+type RoomFilter struct {
+	Currency   string
+	MinPrice   *int64
+	MaxPrice   *int64
+	Adults     int
+	Children   int
+	TypeID     int64
+	PropertyID int64
+	StayStart  string
+	StayEnd    string
+	Limit      int
+	Offset     int
+}
+
+// RoomPage is one page of the room list, NextCursor
+// is empty on the last page.
+type RoomPage struct {
+	Rooms      []Room `json:"rooms"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int64  `json:"total"`
+}
+
+// RoomSort is a field the room list can be sorted by,
+// rooms sorted by price are grouped by currency.
+type RoomSort string
+
+const (
+	SortByDate  RoomSort = "date"
+	SortByPrice RoomSort = "price"
+)
+
+// RoomQuery describes the room list: the sort field,
+// its direction, filters and the page.
+type RoomQuery struct {
+	Sort RoomSort
+	Desc bool
+	RoomFilter
 }
@@ -0,0 +1,26 @@
+package pkg
+
+import "testing"
+
+func TestRoom_Fits(t *testing.T) {
+	room := Room{MaxAdults: 2, MaxChildren: 1}
+
+	tests := []struct {
+		adults   int
+		children int
+		want     bool
+	}{
+		{1, 0, true},
+		{2, 1, true},
+		{1, 2, true},
+		{3, 0, false},
+		{2, 2, false},
+		{0, 4, false},
+	}
+
+	for _, tt := range tests {
+		if got := room.Fits(tt.adults, tt.children); got != tt.want {
+			t.Error("wrong result for ", tt.adults, " adults and ", tt.children, " children: ", got)
+		}
+	}
+}
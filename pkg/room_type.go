@@ -0,0 +1,84 @@
+package pkg
+
+import "sort"
+
+// RoomType is a category of rooms sold together, its rooms
+// share the description, price and capacity of the type.
+// The rooms of a type belong to the property of the type.
+type RoomType struct {
+	ID          int64  `json:"type_id"`
+	PropertyID  int64  `json:"property_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       Money  `json:"price"`
+	MaxAdults   int    `json:"max_adults"`
+	MaxChildren int    `json:"max_children"`
+}
+
+// reports whether the rooms of the type have places for the guests
+func (t *RoomType) Fits(adults, children int) bool {
+	return adults <= t.MaxAdults && adults+children <= t.MaxAdults+t.MaxChildren
+}
+
+// Assignable reports whether every stay without a room can get
+// one of the rooms for all its nights from start to end, end excluded.
+// The stays with a room keep it, the others are taken by their first
+// night, each one gets the free room that was taken the latest,
+// so the rooms free for longer are left for the next stays.
+// A booking of a type fits if it is assignable with the other
+// bookings of the type.
+func Assignable(rooms []int64, stays []Booking, start, end string) bool {
+	type stay struct {
+		from string
+		to   string
+	}
+
+	taken := make(map[int64][]stay, len(rooms))
+	free := make([]stay, 0, len(stays))
+	for _, b := range stays {
+		if b.Start >= end || b.End <= start {
+			continue
+		}
+		s := stay{b.Start, b.End}
+		if s.from < start {
+			s.from = start
+		}
+		if s.to > end {
+			s.to = end
+		}
+		if b.RoomID == 0 {
+			free = append(free, s)
+		} else {
+			taken[b.RoomID] = append(taken[b.RoomID], s)
+		}
+	}
+
+	sort.SliceStable(free, func(i, j int) bool {
+		return free[i].from < free[j].from
+	})
+
+	for _, s := range free {
+		best, found, last := int64(0), false, ""
+		for _, id := range rooms {
+			ok, left := true, ""
+			for _, t := range taken[id] {
+				if t.from < s.to && t.to > s.from {
+					ok = false
+					break
+				}
+				// a stay that ends on a date frees the night of that date
+				if t.to <= s.from && t.to > left {
+					left = t.to
+				}
+			}
+			if ok && (!found || left > last) {
+				best, found, last = id, true, left
+			}
+		}
+		if !found {
+			return false
+		}
+		taken[best] = append(taken[best], s)
+	}
+	return true
+}
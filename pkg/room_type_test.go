@@ -0,0 +1,81 @@
+package pkg
+
+import "testing"
+
+func TestAssignable(t *testing.T) {
+	tests := []struct {
+		name  string
+		rooms []int64
+		stays []Booking
+		want  bool
+	}{
+		{
+			name:  "Free rooms",
+			rooms: []int64{1, 2},
+			stays: []Booking{{Start: "2018-02-01", End: "2018-02-12"}},
+			want:  true,
+		},
+		{
+			name:  "Without rooms",
+			stays: []Booking{{Start: "2018-02-01", End: "2018-02-05"}},
+		},
+		{
+			name:  "Peak of the stays",
+			rooms: []int64{1, 2},
+			stays: []Booking{
+				{Start: "2018-02-01", End: "2018-02-05"},
+				{Start: "2018-02-03", End: "2018-02-06"},
+				{Start: "2018-02-04", End: "2018-02-08"},
+			},
+		},
+		{
+			name:  "Stay ends on the first night of the next one",
+			rooms: []int64{1},
+			stays: []Booking{
+				{RoomID: 1, Start: "2018-02-01", End: "2018-02-05"},
+				{Start: "2018-02-05", End: "2018-02-08"},
+			},
+			want: true,
+		},
+		{
+			// one room is free on every night, but no room on all of them
+			name:  "Split between the rooms",
+			rooms: []int64{1, 2},
+			stays: []Booking{
+				{RoomID: 1, Start: "2018-02-01", End: "2018-02-05"},
+				{RoomID: 2, Start: "2018-02-06", End: "2018-02-12"},
+				{Start: "2018-02-03", End: "2018-02-10"},
+			},
+		},
+		{
+			name:  "Stays one after another",
+			rooms: []int64{1, 2},
+			stays: []Booking{
+				{RoomID: 1, Start: "2018-02-01", End: "2018-02-03"},
+				{RoomID: 2, Start: "2018-02-01", End: "2018-02-05"},
+				{Start: "2018-02-05", End: "2018-02-08"},
+				{Start: "2018-02-03", End: "2018-02-10"},
+			},
+			want: true,
+		},
+		{
+			name:  "Stays outside of the dates",
+			rooms: []int64{1},
+			stays: []Booking{
+				{RoomID: 1, Start: "2018-01-20", End: "2018-02-01"},
+				{Start: "2018-02-01", End: "2018-02-05"},
+				{Start: "2018-02-12", End: "2018-02-15"},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Assignable(tt.rooms, tt.stays, "2018-02-01", "2018-02-12")
+			if got != tt.want {
+				t.Error("wrong assignment: ", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type APIKeysService struct {
+	repo repository.APIKeys
+}
+
+func NewAPIKeysService(repo repository.APIKeys) *APIKeysService {
+	return &APIKeysService{repo: repo}
+}
+
+// random bytes of a key, it is sent as hex
+const keySize = 32
+
+// Issues a random key of the tenant with the role,
+// only its hash is saved, so the returned key can not be shown again.
+func (s *APIKeysService) Issue(ctx context.Context, tenant int64, role pkg.Role) (string, *pkg.APIKey, error) {
+	if tenant < 1 {
+		return "", nil, pkg.ErrTenantNotValid
+	}
+	if !role.Valid() {
+		return "", nil, pkg.ErrRoleNotValid
+	}
+
+	b := make([]byte, keySize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", nil, err
+	}
+	secret := hex.EncodeToString(b)
+
+	key := &pkg.APIKey{TenantID: tenant, Role: role, Hash: hashKey(secret)}
+	err = s.repo.Add(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return secret, key, nil
+}
+
+// the requests with the key are refused at once
+func (s *APIKeysService) Revoke(ctx context.Context, id int64) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Returns the key that is not revoked,
+// pkg.ErrUnauthenticated if there is none.
+func (s *APIKeysService) Authenticate(ctx context.Context, secret string) (*pkg.APIKey, error) {
+	if secret == "" {
+		return nil, pkg.ErrUnauthenticated
+	}
+
+	key, err := s.repo.GetByHash(ctx, hashKey(secret))
+	if errors.Is(err, pkg.ErrIDNotFound) {
+		return nil, pkg.ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// the keys are long random strings,
+// so a fast hash is enough to keep them secret
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestAPIKeysService_Issue(t *testing.T) {
+	tests := []struct {
+		name          string
+		tenant        int64
+		role          pkg.Role
+		expectedError error
+	}{
+		{
+			name:   "OK",
+			tenant: 2,
+			role:   pkg.RoleStaff,
+		},
+		{
+			name:          "Tenant not valid",
+			tenant:        0,
+			role:          pkg.RoleAdmin,
+			expectedError: pkg.ErrTenantNotValid,
+		},
+		{
+			name:          "Role not valid",
+			tenant:        1,
+			role:          "owner",
+			expectedError: pkg.ErrRoleNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockAPIKeys(c)
+			if tt.expectedError == nil {
+				repo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, key *pkg.APIKey) error {
+						key.ID = 3
+						return nil
+					},
+				)
+			}
+
+			secret, key, err := NewAPIKeysService(repo).Issue(context.Background(), tt.tenant, tt.role)
+			if err != tt.expectedError {
+				t.Fatal("incorrect error received: ", err)
+			}
+			if err != nil {
+				return
+			}
+
+			// the key is not saved, only its hash
+			if len(secret) != 2*keySize || key.Hash != hashKey(secret) || key.Hash == secret {
+				t.Error("wrong key issued: ", secret, key)
+			}
+			if key.ID != 3 || key.TenantID != tt.tenant || key.Role != tt.role {
+				t.Error("wrong key saved: ", key)
+			}
+		})
+	}
+}
+
+func TestAPIKeysService_Authenticate(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	staff := &pkg.APIKey{ID: 3, TenantID: 2, Role: pkg.RoleStaff, Hash: hashKey("secret")}
+	repo := mock_repository.NewMockAPIKeys(c)
+	repo.EXPECT().GetByHash(gomock.Any(), hashKey("secret")).Return(staff, nil)
+	repo.EXPECT().GetByHash(gomock.Any(), hashKey("revoked")).Return(nil, pkg.ErrIDNotFound)
+	repo.EXPECT().GetByHash(gomock.Any(), hashKey("broken")).Return(nil, pkg.ErrFailedGet)
+	s := NewAPIKeysService(repo)
+	ctx := context.Background()
+
+	key, err := s.Authenticate(ctx, "secret")
+	if err != nil || key != staff {
+		t.Error("wrong key received: ", key, err)
+	}
+	for _, secret := range []string{"", "revoked"} {
+		if _, err := s.Authenticate(ctx, secret); err != pkg.ErrUnauthenticated {
+			t.Error("incorrect error received: ", secret, err)
+		}
+	}
+	if _, err := s.Authenticate(ctx, "broken"); err != pkg.ErrFailedGet {
+		t.Error("incorrect error received: ", err)
+	}
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/Avepa/booking/pkg"
@@ -10,32 +12,537 @@ import (
 const form = "2006-01-02"
 
 type BookingsService struct {
-	repo repository.Bookings
+	repo       repository.Bookings
+	rooms      repository.Room
+	types      repository.RoomTypes
+	rates      repository.RatePlans
+	guests     repository.Guests
+	properties repository.Properties
+	rules      DateRules
+	now        func() time.Time
 }
 
-func NewBookingsService(repo repository.Bookings) *BookingsService {
-	return &BookingsService{repo: repo}
+func NewBookingsService(repo repository.Bookings, rooms repository.Room, types repository.RoomTypes, rates repository.RatePlans, guests repository.Guests, properties repository.Properties, rules DateRules) *BookingsService {
+	return &BookingsService{
+		repo:       repo,
+		rooms:      rooms,
+		types:      types,
+		rates:      rates,
+		guests:     guests,
+		properties: properties,
+		rules:      rules,
+		now:        time.Now,
+	}
 }
 
-func (s *BookingsService) Add(id int64, booking *pkg.Booking) (int64, error) {
-	_, err := time.Parse(form, booking.Start)
+// Books the room, the price of the stay is saved with the booking,
+// so later changes of the room price do not change it.
+// The guest is optional, returns pkg.ErrNoGuest if it does not exist.
+// A booking without the number of adults is made for one adult,
+// returns pkg.ErrCapacityExceeded if the room has no places for everyone.
+// The dates are checked against today in the timezone of the property.
+func (s *BookingsService) Add(ctx context.Context, id int64, booking *pkg.Booking) (int64, error) {
+	room, err := s.rooms.GetByID(ctx, id)
+	if errors.Is(err, pkg.ErrIDNotFound) {
+		return 0, pkg.ErrNoForeignKey
+	} else if err != nil {
+		return 0, err
+	}
+	if !inScope(ctx, room.PropertyID) {
+		return 0, pkg.ErrNoForeignKey
+	}
+
+	err = s.checkStay(ctx, room.PropertyID, booking)
 	if err != nil {
-		return 0, pkg.ErrDateIsIncorrect
+		return 0, err
+	}
+
+	if !room.Fits(booking.Adults, booking.Children) {
+		return 0, pkg.ErrCapacityExceeded
 	}
 
-	_, err = time.Parse(form, booking.End)
+	err = s.checkGuest(ctx, booking.GuestID)
 	if err != nil {
-		return 0, pkg.ErrDateIsIncorrect
+		return 0, err
 	}
 
-	err = s.repo.Add(id, booking)
+	err = s.save(ctx, room, id, booking)
 	return booking.ID, err
 }
 
-func (s *BookingsService) Get(roomID int64) ([]pkg.Booking, error) {
-	return s.repo.Get(roomID)
+// Books a room of the type. A deferred booking is saved without
+// a room, a free room of the type is assigned to it at check-in,
+// otherwise a free room is assigned now. The stay is priced
+// like in the first free room of the type.
+// Returns pkg.ErrNoRoomType if the type does not exist
+// and pkg.ErrNoFreeRoom if no room of the type is free for the dates.
+func (s *BookingsService) AddByType(ctx context.Context, typeID int64, booking *pkg.Booking, deferred bool) (int64, error) {
+	t, err := s.types.GetByID(ctx, typeID)
+	if errors.Is(err, pkg.ErrIDNotFound) {
+		return 0, pkg.ErrNoRoomType
+	} else if err != nil {
+		return 0, err
+	}
+	if !inScope(ctx, t.PropertyID) {
+		return 0, pkg.ErrNoRoomType
+	}
+
+	err = s.checkStay(ctx, t.PropertyID, booking)
+	if err != nil {
+		return 0, err
+	}
+
+	if !t.Fits(booking.Adults, booking.Children) {
+		return 0, pkg.ErrCapacityExceeded
+	}
+
+	err = s.checkGuest(ctx, booking.GuestID)
+	if err != nil {
+		return 0, err
+	}
+
+	free, err := s.rooms.GetAvailable(ctx, booking.Start, booking.End, freeRooms(typeID))
+	if err != nil {
+		return 0, err
+	}
+	if len(free) == 0 {
+		return 0, pkg.ErrNoFreeRoom
+	}
+
+	if deferred {
+		booking.TypeID = typeID
+		err = s.save(ctx, &free[0], 0, booking)
+		if errors.Is(err, pkg.ErrBookingConflict) {
+			return 0, pkg.ErrNoFreeRoom
+		}
+		return booking.ID, err
+	}
+
+	// a room may be taken by a concurrent booking
+	// or by the bookings of the type without a room
+	for i := range free {
+		err = s.save(ctx, &free[i], free[i].ID, booking)
+		if !errors.Is(err, pkg.ErrBookingConflict) {
+			return booking.ID, err
+		}
+	}
+	return 0, pkg.ErrNoFreeRoom
+}
+
+// query of the free rooms of the type, the oldest rooms go first
+func freeRooms(typeID int64) pkg.RoomQuery {
+	return pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{TypeID: typeID}}
+}
+
+// checks the dates of the booking at the property and the number
+// of its guests, a booking without the number of adults is made for one adult
+func (s *BookingsService) checkStay(ctx context.Context, property int64, booking *pkg.Booking) error {
+	err := s.checkDates(ctx, property, booking.Start, booking.End)
+	if err != nil {
+		return err
+	}
+
+	if booking.Adults == 0 {
+		booking.Adults = 1
+	}
+	if booking.Adults < 0 || booking.Children < 0 {
+		return pkg.ErrGuestsNotValid
+	}
+
+	return nil
+}
+
+// a zero guest is a booking without a guest
+func (s *BookingsService) checkGuest(ctx context.Context, guest int64) error {
+	if guest == 0 {
+		return nil
+	}
+
+	_, err := s.guests.GetByID(ctx, guest)
+	if errors.Is(err, pkg.ErrIDNotFound) {
+		return pkg.ErrNoGuest
+	}
+	return err
+}
+
+// prices the stay in the room and saves the booking
+// as pending in the room with the id, a zero id
+// saves the booking without a room
+func (s *BookingsService) save(ctx context.Context, room *pkg.Room, id int64, booking *pkg.Booking) error {
+	plans, err := s.rates.Get(ctx, []int64{room.ID})
+	if err != nil {
+		return err
+	}
+
+	err = setTotal(booking, room, plans)
+	if err != nil {
+		return err
+	}
+
+	booking.Status = pkg.StatusPending
+	return s.repo.Add(ctx, id, booking)
+}
+
+// sets the total of the booking to the price
+// of its stay in the room by the plans
+func setTotal(booking *pkg.Booking, room *pkg.Room, plans []pkg.RatePlan) error {
+	quote, err := newQuote(room, plans, booking.Start, booking.End)
+	if err != nil {
+		return err
+	}
+
+	booking.Total = &quote.Total
+	return nil
+}
+
+// Changes the dates or the room of an active booking, keeping its id.
+// The date rules and conflicts are checked in the same transaction
+// in which the booking is saved. An unchanged arrival date
+// is not checked against the past and the booking horizon.
+// The new room must have places for the guests of the booking,
+// the booking gets the type of the new room.
+// The stay is priced again in the room the booking ends up in,
+// the dates are checked at the property of that room.
+func (s *BookingsService) Update(ctx context.Context, id int64, update *pkg.BookingUpdate) (*pkg.Booking, error) {
+	if update.RoomID == nil && update.Start == nil && update.End == nil {
+		return nil, pkg.ErrNothingToUpdate
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = s.bookingInScope(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	var room *pkg.Room
+	if update.RoomID != nil {
+		room, err = s.rooms.GetByID(ctx, *update.RoomID)
+		if errors.Is(err, pkg.ErrIDNotFound) {
+			return nil, pkg.ErrNoForeignKey
+		} else if err != nil {
+			return nil, err
+		}
+		if !inScope(ctx, room.PropertyID) {
+			return nil, pkg.ErrNoForeignKey
+		}
+	}
+
+	rules := s.rules
+	if update.Start == nil {
+		rules.AllowPast = true
+		rules.Horizon = 0
+	}
+
+	priced := room
+	if priced == nil {
+		priced, err = s.bookedRoom(ctx, current, update, rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	today, err := s.today(ctx, priced.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.rates.Get(ctx, []int64{priced.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	var booking pkg.Booking
+	err = s.repo.Update(ctx, id, func(b *pkg.Booking) error {
+		if !b.Status.Active() {
+			return pkg.ErrStatusChange
+		}
+		// the stay was priced in another room
+		// than the one of a concurrent check-in
+		if b.RoomID != current.RoomID {
+			return pkg.ErrBookingConflict
+		}
+		if room != nil {
+			if !room.Fits(b.Adults, b.Children) {
+				return pkg.ErrCapacityExceeded
+			}
+			b.RoomID = room.ID
+			b.TypeID = room.TypeID
+		}
+		if update.Start != nil {
+			b.Start = *update.Start
+		}
+		if update.End != nil {
+			b.End = *update.End
+		}
+
+		err := checkDates(rules, b.Start, b.End, today)
+		if err != nil {
+			return err
+		}
+
+		err = setTotal(b, priced, plans)
+		if err != nil {
+			return err
+		}
+
+		booking = *b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// returns the room the stay of the booking is priced in, a booking
+// without a room is priced like in the first free room of its type
+// for the new dates, returns pkg.ErrNoFreeRoom if there is none
+func (s *BookingsService) bookedRoom(ctx context.Context, b *pkg.Booking, update *pkg.BookingUpdate, rules DateRules) (*pkg.Room, error) {
+	if b.RoomID != 0 {
+		return s.rooms.GetByID(ctx, b.RoomID)
+	}
+
+	start, end := b.Start, b.End
+	if update.Start != nil {
+		start = *update.Start
+	}
+	if update.End != nil {
+		end = *update.End
+	}
+
+	t, err := s.types.GetByID(ctx, b.TypeID)
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.today(ctx, t.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkDates(rules, start, end, today)
+	if err != nil {
+		return nil, err
+	}
+
+	free, err := s.rooms.GetAvailable(ctx, start, end, freeRooms(b.TypeID))
+	if err != nil {
+		return nil, err
+	}
+	if len(free) == 0 {
+		return nil, pkg.ErrNoFreeRoom
+	}
+
+	return &free[0], nil
+}
+
+// returns pkg.ErrIDNotFound for a booking of a property
+// other than the one of the request scope, the property
+// of a booking without a room is the property of its type
+func (s *BookingsService) checkScope(ctx context.Context, id int64) error {
+	if propertyFrom(ctx) == 0 {
+		return nil
+	}
+
+	b, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.bookingInScope(ctx, b)
+}
+
+// like checkScope, for a booking that is already read
+func (s *BookingsService) bookingInScope(ctx context.Context, b *pkg.Booking) error {
+	if propertyFrom(ctx) == 0 {
+		return nil
+	}
+
+	var property int64
+	var err error
+	if b.RoomID != 0 {
+		property, err = s.rooms.GetProperty(ctx, b.RoomID)
+		if err != nil {
+			return err
+		}
+	} else {
+		t, err := s.types.GetByID(ctx, b.TypeID)
+		if err != nil {
+			return err
+		}
+		property = t.PropertyID
+	}
+
+	if !inScope(ctx, property) {
+		return pkg.ErrIDNotFound
+	}
+	return nil
+}
+
+// returns the current date in the timezone of the property
+func (s *BookingsService) today(ctx context.Context, property int64) (time.Time, error) {
+	return propertyToday(ctx, s.properties, property, s.now())
+}
+
+// checks the format of the dates and the date rules
+// against today at the property
+func (s *BookingsService) checkDates(ctx context.Context, property int64, start, end string) error {
+	today, err := s.today(ctx, property)
+	if err != nil {
+		return err
+	}
+
+	return checkDates(s.rules, start, end, today)
+}
+
+func checkDates(rules DateRules, start, end string, today time.Time) error {
+	dateStart, err := time.Parse(form, start)
+	if err != nil {
+		return pkg.ErrDateIsIncorrect
+	}
+
+	dateEnd, err := time.Parse(form, end)
+	if err != nil {
+		return pkg.ErrDateIsIncorrect
+	}
+
+	return rules.check(dateStart, dateEnd, today)
+}
+
+// allowed changes of the booking status,
+// cancelled, no_show and checked_out bookings are final
+var transitions = map[pkg.BookingStatus][]pkg.BookingStatus{
+	pkg.StatusPending:   {pkg.StatusConfirmed, pkg.StatusCancelled},
+	pkg.StatusConfirmed: {pkg.StatusCheckedIn, pkg.StatusCancelled, pkg.StatusNoShow},
+	pkg.StatusCheckedIn: {pkg.StatusCheckedOut},
+}
+
+// reports whether the booking can move from one status to the other
+func canMove(from, to pkg.BookingStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Moves the booking to the status,
+// returns pkg.ErrStatusChange if the transition is not allowed.
+// A booking of a type made without a room gets a free room
+// of the type at check-in, returns pkg.ErrNoFreeRoom if there is none.
+func (s *BookingsService) SetStatus(ctx context.Context, id int64, status pkg.BookingStatus) (*pkg.Booking, error) {
+	if !status.Valid() {
+		return nil, pkg.ErrStatusNotValid
+	}
+
+	err := s.checkScope(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == pkg.StatusCheckedIn {
+		b, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if b.RoomID == 0 {
+			return s.checkIn(ctx, b)
+		}
+	}
+
+	return s.setStatus(ctx, id, status, nil, nil)
+}
+
+// checks in the booking without a room, the free rooms of its type
+// are tried one by one, rooms taken by concurrent bookings are skipped,
+// the stay is priced again in the assigned room
+func (s *BookingsService) checkIn(ctx context.Context, b *pkg.Booking) (*pkg.Booking, error) {
+	if !canMove(b.Status, pkg.StatusCheckedIn) {
+		return nil, pkg.ErrStatusChange
+	}
+
+	free, err := s.rooms.GetAvailable(ctx, b.Start, b.End, freeRooms(b.TypeID))
+	if err != nil {
+		return nil, err
+	}
+	if len(free) == 0 {
+		return nil, pkg.ErrNoFreeRoom
+	}
+
+	ids := make([]int64, len(free))
+	for i := range free {
+		ids[i] = free[i].ID
+	}
+	plans, err := s.rates.Get(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range free {
+		booking, err := s.setStatus(ctx, b.ID, pkg.StatusCheckedIn, &free[i], plans)
+		if !errors.Is(err, pkg.ErrBookingConflict) {
+			return booking, err
+		}
+	}
+	return nil, pkg.ErrNoFreeRoom
+}
+
+// moves the booking to the status in one transaction,
+// a booking without a room gets the room if it is not nil
+// and the price of the stay in it by the plans
+func (s *BookingsService) setStatus(ctx context.Context, id int64, status pkg.BookingStatus, room *pkg.Room, plans []pkg.RatePlan) (*pkg.Booking, error) {
+	var booking pkg.Booking
+	err := s.repo.Update(ctx, id, func(b *pkg.Booking) error {
+		if !canMove(b.Status, status) {
+			return pkg.ErrStatusChange
+		}
+
+		b.Status = status
+		if b.RoomID == 0 && room != nil {
+			b.RoomID = room.ID
+			err := setTotal(b, room, plans)
+			if err != nil {
+				return err
+			}
+		}
+		booking = *b
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+// returns bookings of the room,
+// if statuses are passed, only bookings in them
+func (s *BookingsService) Get(ctx context.Context, roomID int64, status []pkg.BookingStatus) ([]pkg.Booking, error) {
+	for _, st := range status {
+		if !st.Valid() {
+			return nil, pkg.ErrStatusNotValid
+		}
+	}
+
+	if propertyFrom(ctx) != 0 {
+		property, err := s.rooms.GetProperty(ctx, roomID)
+		if err != nil {
+			return nil, err
+		}
+		if !inScope(ctx, property) {
+			return nil, pkg.ErrIDNotFound
+		}
+	}
+
+	return s.repo.Get(ctx, roomID, status)
 }
 
-func (s *BookingsService) Delete(id int64) error {
-	return s.repo.Delete(id)
+// Cancels the booking, the booking stays in the history.
+func (s *BookingsService) Delete(ctx context.Context, id int64) error {
+	_, err := s.SetStatus(ctx, id, pkg.StatusCancelled)
+	return err
 }
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/Avepa/booking/pkg"
 	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
@@ -9,7 +12,9 @@ import (
 )
 
 func TestBookingsService_Add(t *testing.T) {
-	type mockBehavior func(r *mock_repository.MockBookings, room int64, booking *pkg.Booking)
+	type mockBehavior func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking)
+
+	price := pkg.Money{Amount: 1250, Currency: "EUR"}
 
 	tests := []struct {
 		name          string
@@ -17,6 +22,7 @@ func TestBookingsService_Add(t *testing.T) {
 		inputBooking  pkg.Booking
 		mock          mockBehavior
 		expected      int64
+		expectedTotal *pkg.Money
 		expectedError error
 	}{
 		{
@@ -27,10 +33,43 @@ func TestBookingsService_Add(t *testing.T) {
 				Start: "2018-02-05",
 				End:   "2018-02-07",
 			},
-			mock: func(r *mock_repository.MockBookings, room int64, booking *pkg.Booking) {
-				r.EXPECT().Add(room, booking).Return(nil)
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{
+					{ID: 1, RoomID: room, Weekdays: 1 << time.Tuesday, Price: pkg.Money{Amount: 2000, Currency: "EUR"}},
+				}, nil)
+				r.EXPECT().Add(gomock.Any(), room, booking).Return(nil)
 			},
-			expected: 4,
+			expected:      4,
+			expectedTotal: &pkg.Money{Amount: 3250, Currency: "EUR"},
+		},
+		{
+			name:    "Room not found",
+			inputID: 3,
+			inputBooking: pkg.Booking{
+				Start: "2018-02-05",
+				End:   "2018-02-07",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(nil, pkg.ErrIDNotFound)
+			},
+			expected:      0,
+			expectedError: pkg.ErrNoForeignKey,
+		},
+		{
+			name:    "Booking conflict",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2018-02-05",
+				End:   "2018-02-07",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+				r.EXPECT().Add(gomock.Any(), room, booking).Return(pkg.ErrBookingConflict)
+			},
+			expected:      0,
+			expectedError: pkg.ErrBookingConflict,
 		},
 		{
 			name:    "Date is incorrect",
@@ -40,10 +79,170 @@ func TestBookingsService_Add(t *testing.T) {
 				Start: "2018.02.05",
 				End:   "2018.02.07",
 			},
-			mock:          func(r *mock_repository.MockBookings, room int64, booking *pkg.Booking) {},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
 			expected:      0,
 			expectedError: pkg.ErrDateIsIncorrect,
 		},
+		{
+			name:    "End before start",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2018-02-07",
+				End:   "2018-02-05",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrDateOrder,
+		},
+		{
+			name:    "Zero length stay",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2018-02-05",
+				End:   "2018-02-05",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrDateOrder,
+		},
+		{
+			name:    "Stay too long",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2018-02-05",
+				End:   "2018-03-15",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrStayTooLong,
+		},
+		{
+			name:    "Date in past",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2017-12-31",
+				End:   "2018-01-03",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrDateInPast,
+		},
+		{
+			name:    "Beyond horizon",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Start: "2019-01-02",
+				End:   "2019-01-05",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrDateTooFar,
+		},
+		{
+			name:    "OK with guest",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				ID:      5,
+				GuestID: 2,
+				Start:   "2018-02-05",
+				End:     "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+				guests.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.Guest{ID: 2}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+				r.EXPECT().Add(gomock.Any(), room, booking).Return(nil)
+			},
+			expected:      5,
+			expectedTotal: &pkg.Money{Amount: 1250, Currency: "EUR"},
+		},
+		{
+			name:    "Guest not found",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				GuestID: 3,
+				Start:   "2018-02-05",
+				End:     "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+				guests.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+			},
+			expected:      0,
+			expectedError: pkg.ErrNoGuest,
+		},
+		{
+			name:    "OK children on adult places",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				ID:       6,
+				Adults:   1,
+				Children: 2,
+				Start:    "2018-02-05",
+				End:      "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2, MaxChildren: 1}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+				r.EXPECT().Add(gomock.Any(), room, booking).Return(nil)
+			},
+			expected: 6,
+		},
+		{
+			name:    "Capacity exceeded",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Adults:   2,
+				Children: 4,
+				Start:    "2018-02-05",
+				End:      "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2, MaxChildren: 1}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrCapacityExceeded,
+		},
+		{
+			name:    "Too many adults",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Adults: 3,
+				Start:  "2018-02-05",
+				End:    "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2, MaxChildren: 1}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrCapacityExceeded,
+		},
+		{
+			name:    "Guests not valid",
+			inputID: 1,
+			inputBooking: pkg.Booking{
+				Children: -1,
+				Start:    "2018-02-05",
+				End:      "2018-02-06",
+			},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, guests *mock_repository.MockGuests, room int64, booking *pkg.Booking) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price, MaxAdults: 2}, nil)
+			},
+			expected:      0,
+			expectedError: pkg.ErrGuestsNotValid,
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,16 +251,147 @@ func TestBookingsService_Add(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_repository.NewMockBookings(c)
-			tt.mock(repo, tt.inputID, &tt.inputBooking)
+			rooms := mock_repository.NewMockRoom(c)
+			rates := mock_repository.NewMockRatePlans(c)
+			guests := mock_repository.NewMockGuests(c)
+			tt.mock(repo, rooms, rates, guests, tt.inputID, &tt.inputBooking)
 
-			services := NewBookingsService(repo)
-			id, err := services.Add(tt.inputID, &tt.inputBooking)
-			if err != tt.expectedError {
+			services := NewBookingsService(repo, rooms, nil, rates, guests, nil, DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+			}
+			id, err := services.Add(context.Background(), tt.inputID, &tt.inputBooking)
+			if !errors.Is(err, tt.expectedError) {
 				t.Error("incorrect error received: ", err)
 			}
 			if id != tt.expected {
 				t.Error("incorrect id received: ", id)
 			}
+			if tt.expectedTotal != nil && *tt.inputBooking.Total != *tt.expectedTotal {
+				t.Error("incorrect total saved: ", tt.inputBooking.Total)
+			}
+		})
+	}
+}
+
+func TestBookingsService_AddByType(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking)
+
+	price := pkg.Money{Amount: 1250, Currency: "EUR"}
+	double := &pkg.RoomType{ID: 2, Name: "Double", Price: price, MaxAdults: 2}
+	free := []pkg.Room{
+		{ID: 4, TypeID: 2, Price: price, MaxAdults: 2},
+		{ID: 5, TypeID: 2, Price: price, MaxAdults: 2},
+	}
+
+	tests := []struct {
+		name          string
+		deferred      bool
+		inputBooking  pkg.Booking
+		mock          mockBehavior
+		expected      int64
+		expectedRoom  int64
+		expectedError error
+	}{
+		{
+			name:         "OK assign now",
+			inputBooking: pkg.Booking{Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil)
+				rooms.EXPECT().GetAvailable(gomock.Any(), "2018-02-05", "2018-02-07", freeRooms(2)).Return(free, nil)
+				rates.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]pkg.RatePlan{}, nil).Times(2)
+				r.EXPECT().Add(gomock.Any(), int64(4), booking).Return(pkg.ErrBookingConflict)
+				r.EXPECT().Add(gomock.Any(), int64(5), booking).DoAndReturn(
+					func(ctx context.Context, room int64, b *pkg.Booking) error {
+						b.ID, b.RoomID = 7, room
+						return nil
+					},
+				)
+			},
+			expected:     7,
+			expectedRoom: 5,
+		},
+		{
+			name:         "OK assign at check in",
+			deferred:     true,
+			inputBooking: pkg.Booking{Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil)
+				rooms.EXPECT().GetAvailable(gomock.Any(), "2018-02-05", "2018-02-07", freeRooms(2)).Return(free, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{4}).Return([]pkg.RatePlan{}, nil)
+				r.EXPECT().Add(gomock.Any(), int64(0), booking).DoAndReturn(
+					func(ctx context.Context, room int64, b *pkg.Booking) error {
+						if b.TypeID != 2 {
+							t.Error("booking saved without the type")
+						}
+						b.ID = 8
+						return nil
+					},
+				)
+			},
+			expected: 8,
+		},
+		{
+			name:         "No free room",
+			inputBooking: pkg.Booking{Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil)
+				rooms.EXPECT().GetAvailable(gomock.Any(), "2018-02-05", "2018-02-07", freeRooms(2)).Return(free, nil)
+				rates.EXPECT().Get(gomock.Any(), gomock.Any()).Return([]pkg.RatePlan{}, nil).Times(2)
+				r.EXPECT().Add(gomock.Any(), gomock.Any(), booking).Return(pkg.ErrBookingConflict).Times(2)
+			},
+			expectedError: pkg.ErrNoFreeRoom,
+		},
+		{
+			name:         "No free room at check in",
+			deferred:     true,
+			inputBooking: pkg.Booking{Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil)
+				rooms.EXPECT().GetAvailable(gomock.Any(), "2018-02-05", "2018-02-07", freeRooms(2)).Return([]pkg.Room{}, nil)
+			},
+			expectedError: pkg.ErrNoFreeRoom,
+		},
+		{
+			name:         "Type not found",
+			inputBooking: pkg.Booking{Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrNoRoomType,
+		},
+		{
+			name:         "Capacity exceeded",
+			inputBooking: pkg.Booking{Adults: 3, Start: "2018-02-05", End: "2018-02-07"},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans, booking *pkg.Booking) {
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil)
+			},
+			expectedError: pkg.ErrCapacityExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockBookings(c)
+			rooms := mock_repository.NewMockRoom(c)
+			types := mock_repository.NewMockRoomTypes(c)
+			rates := mock_repository.NewMockRatePlans(c)
+			tt.mock(repo, rooms, types, rates, &tt.inputBooking)
+
+			services := NewBookingsService(repo, rooms, types, rates, nil, nil, DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+			}
+			id, err := services.AddByType(context.Background(), 2, &tt.inputBooking, tt.deferred)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+			if id != tt.expected || err == nil && tt.inputBooking.RoomID != tt.expectedRoom {
+				t.Error("incorrect booking saved: ", id, tt.inputBooking)
+			}
 		})
 	}
 }
@@ -81,7 +411,7 @@ func TestBookingsService_Get(t *testing.T) {
 			name:  "OK",
 			input: 1,
 			mock: func(r *mock_repository.MockBookings, room int64, booking []pkg.Booking) {
-				r.EXPECT().Get(room).Return(booking, nil)
+				r.EXPECT().Get(gomock.Any(), room, []pkg.BookingStatus{pkg.StatusConfirmed}).Return(booking, nil)
 			},
 			expected: []pkg.Booking{
 				{
@@ -100,7 +430,7 @@ func TestBookingsService_Get(t *testing.T) {
 			name:  "Date is incorrect",
 			input: 1,
 			mock: func(r *mock_repository.MockBookings, room int64, booking []pkg.Booking) {
-				r.EXPECT().Get(room).Return(booking, pkg.ErrIDNotFound)
+				r.EXPECT().Get(gomock.Any(), room, []pkg.BookingStatus{pkg.StatusConfirmed}).Return(booking, pkg.ErrIDNotFound)
 			},
 			expectedError: pkg.ErrIDNotFound,
 		},
@@ -114,8 +444,8 @@ func TestBookingsService_Get(t *testing.T) {
 			repo := mock_repository.NewMockBookings(c)
 			tt.mock(repo, tt.input, tt.expected)
 
-			services := NewBookingsService(repo)
-			bookings, err := services.Get(tt.input)
+			services := NewBookingsService(repo, nil, nil, nil, nil, nil, DefaultDateRules)
+			bookings, err := services.Get(context.Background(), tt.input, []pkg.BookingStatus{pkg.StatusConfirmed})
 			if err != tt.expectedError {
 				t.Error("incorrect error received: ", err)
 			} else if err != nil {
@@ -145,16 +475,25 @@ func TestBookingsService_Delete(t *testing.T) {
 			name:  "OK",
 			input: 12,
 			mock: func(r *mock_repository.MockBookings, booking int64) {
-				r.EXPECT().Delete(booking).Return(nil)
+				r.EXPECT().Update(gomock.Any(), booking, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, id int64, change func(b *pkg.Booking) error) error {
+						b := pkg.Booking{ID: id, Status: pkg.StatusConfirmed}
+						err := change(&b)
+						if b.Status != pkg.StatusCancelled {
+							t.Error("booking is not cancelled")
+						}
+						return err
+					},
+				)
 			},
 		},
 		{
 			name:  "Failed delete",
 			input: 15,
 			mock: func(r *mock_repository.MockBookings, booking int64) {
-				r.EXPECT().Delete(booking).Return(pkg.ErrFailedDelete)
+				r.EXPECT().Update(gomock.Any(), booking, gomock.Any()).Return(pkg.ErrFailedSave)
 			},
-			expectedError: pkg.ErrFailedDelete,
+			expectedError: pkg.ErrFailedSave,
 		},
 	}
 
@@ -166,11 +505,534 @@ func TestBookingsService_Delete(t *testing.T) {
 			repo := mock_repository.NewMockBookings(c)
 			tt.mock(repo, tt.input)
 
-			services := NewBookingsService(repo)
-			err := services.Delete(tt.input)
+			services := NewBookingsService(repo, nil, nil, nil, nil, nil, DefaultDateRules)
+			err := services.Delete(context.Background(), tt.input)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+		})
+	}
+}
+
+func TestBookingsService_SetStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       pkg.BookingStatus
+		input         pkg.BookingStatus
+		expectedError error
+	}{
+		{
+			name:    "OK confirm",
+			current: pkg.StatusPending,
+			input:   pkg.StatusConfirmed,
+		},
+		{
+			name:    "OK check in",
+			current: pkg.StatusConfirmed,
+			input:   pkg.StatusCheckedIn,
+		},
+		{
+			name:    "OK check out",
+			current: pkg.StatusCheckedIn,
+			input:   pkg.StatusCheckedOut,
+		},
+		{
+			name:    "OK no show",
+			current: pkg.StatusConfirmed,
+			input:   pkg.StatusNoShow,
+		},
+		{
+			name:          "Check in pending",
+			current:       pkg.StatusPending,
+			input:         pkg.StatusCheckedIn,
+			expectedError: pkg.ErrStatusChange,
+		},
+		{
+			name:          "Cancel checked in",
+			current:       pkg.StatusCheckedIn,
+			input:         pkg.StatusCancelled,
+			expectedError: pkg.ErrStatusChange,
+		},
+		{
+			name:          "Confirm cancelled",
+			current:       pkg.StatusCancelled,
+			input:         pkg.StatusConfirmed,
+			expectedError: pkg.ErrStatusChange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockBookings(c)
+			if tt.input == pkg.StatusCheckedIn {
+				repo.EXPECT().GetByID(gomock.Any(), int64(3)).
+					Return(&pkg.Booking{ID: 3, RoomID: 4, Status: tt.current}, nil)
+			}
+			repo.EXPECT().Update(gomock.Any(), int64(3), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, id int64, change func(b *pkg.Booking) error) error {
+					b := pkg.Booking{ID: id, RoomID: 4, Status: tt.current}
+					return change(&b)
+				},
+			)
+
+			services := NewBookingsService(repo, nil, nil, nil, nil, nil, DefaultDateRules)
+			booking, err := services.SetStatus(context.Background(), 3, tt.input)
 			if err != tt.expectedError {
 				t.Error("incorrect error received: ", err)
+			} else if err == nil && booking.Status != tt.input {
+				t.Error("incorrect status received: ", booking.Status)
 			}
 		})
 	}
 }
+
+func TestBookingsService_CheckIn(t *testing.T) {
+	free := []pkg.Room{
+		{ID: 4, TypeID: 2, Price: pkg.Money{Amount: 3000, Currency: "USD"}},
+		{ID: 5, TypeID: 2, Price: pkg.Money{Amount: 4000, Currency: "USD"}},
+	}
+	stored := pkg.Booking{ID: 3, TypeID: 2, Start: "2018-02-05", End: "2018-02-07", Status: pkg.StatusConfirmed,
+		Total: &pkg.Money{Amount: 5000, Currency: "USD"}}
+
+	// taken is the number of the free rooms taken
+	// by concurrent bookings before the check-in
+	tests := []struct {
+		name          string
+		taken         int
+		updates       int
+		expectedRoom  int64
+		expectedTotal pkg.Money
+		expectedError error
+	}{
+		{
+			name:          "OK first free room",
+			updates:       1,
+			expectedRoom:  4,
+			expectedTotal: pkg.Money{Amount: 6000, Currency: "USD"},
+		},
+		{
+			name:          "OK room taken",
+			taken:         1,
+			updates:       2,
+			expectedRoom:  5,
+			expectedTotal: pkg.Money{Amount: 8000, Currency: "USD"},
+		},
+		{
+			name:          "No free room",
+			taken:         2,
+			updates:       2,
+			expectedError: pkg.ErrNoFreeRoom,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockBookings(c)
+			rooms := mock_repository.NewMockRoom(c)
+			b := stored
+			repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&b, nil)
+			rooms.EXPECT().GetAvailable(gomock.Any(), "2018-02-05", "2018-02-07", freeRooms(2)).Return(free, nil)
+			rates := mock_repository.NewMockRatePlans(c)
+			rates.EXPECT().Get(gomock.Any(), []int64{4, 5}).Return([]pkg.RatePlan{}, nil)
+			calls := 0
+			repo.EXPECT().Update(gomock.Any(), int64(3), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, id int64, change func(b *pkg.Booking) error) error {
+					b := stored
+					if err := change(&b); err != nil {
+						return err
+					}
+					if calls++; calls <= tt.taken {
+						return pkg.ErrBookingConflict
+					}
+					return nil
+				},
+			).Times(tt.updates)
+
+			services := NewBookingsService(repo, rooms, nil, rates, nil, nil, DefaultDateRules)
+			booking, err := services.SetStatus(context.Background(), 3, pkg.StatusCheckedIn)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			} else if err == nil && (booking.RoomID != tt.expectedRoom || booking.Status != pkg.StatusCheckedIn) {
+				t.Error("incorrect booking received: ", booking)
+			} else if err == nil && *booking.Total != tt.expectedTotal {
+				// the stay is priced again in the assigned room
+				t.Error("incorrect total received: ", booking.Total)
+			}
+		})
+	}
+
+	c := gomock.NewController(t)
+	defer c.Finish()
+	repo := mock_repository.NewMockBookings(c)
+	pending := stored
+	pending.Status = pkg.StatusPending
+	repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&pending, nil)
+	_, err := NewBookingsService(repo, nil, nil, nil, nil, nil, DefaultDateRules).SetStatus(context.Background(), 3, pkg.StatusCheckedIn)
+	if err != pkg.ErrStatusChange {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestDateRules_check(t *testing.T) {
+	today := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(s string) time.Time {
+		d, err := time.Parse(form, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name          string
+		rules         DateRules
+		start         string
+		end           string
+		expectedField string
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			rules: DefaultDateRules,
+			start: "2018-01-01",
+			end:   "2018-01-31",
+		},
+		{
+			name:          "Stay too short",
+			rules:         DateRules{MinNights: 3},
+			start:         "2018-01-05",
+			end:           "2018-01-07",
+			expectedField: "date_end",
+			expectedError: pkg.ErrStayTooShort,
+		},
+		{
+			name:  "Limits disabled",
+			rules: DateRules{AllowPast: true},
+			start: "2010-01-05",
+			end:   "2030-01-07",
+		},
+		{
+			name:          "Date in past",
+			rules:         DateRules{},
+			start:         "2017-12-31",
+			end:           "2018-01-07",
+			expectedField: "date_start",
+			expectedError: pkg.ErrDateInPast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.check(date(tt.start), date(tt.end), today)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			}
+
+			var dateErr *pkg.DateError
+			if errors.As(err, &dateErr) && dateErr.Field != tt.expectedField {
+				t.Error("incorrect field received: ", dateErr.Field)
+			}
+		})
+	}
+}
+
+func TestBookingsService_Update(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans)
+
+	room := int64(4)
+	start := "2018-02-06"
+	end := "2018-02-09"
+	past := "2017-12-20"
+	before := "2018-01-29"
+	extended := "2018-01-10"
+
+	// the stored booking of 8 nights at 10 USD
+	status := pkg.StatusConfirmed
+	booking := func() pkg.Booking {
+		return pkg.Booking{
+			ID: 7, RoomID: 3, Adults: 2, Start: "2017-12-28", End: "2018-01-05", Status: status,
+			Total: &pkg.Money{Amount: 8000, Currency: "USD"},
+		}
+	}
+	read := func(r *mock_repository.MockBookings) {
+		b := booking()
+		r.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&b, nil)
+	}
+	// the stored booking is passed to the change
+	stored := func(r *mock_repository.MockBookings, err error) {
+		r.EXPECT().Update(gomock.Any(), int64(7), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, id int64, change func(b *pkg.Booking) error) error {
+				b := booking()
+				if e := change(&b); e != nil {
+					return e
+				}
+				return err
+			},
+		)
+	}
+
+	// the room of the booking and its plans
+	booked := func(rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {
+		rooms.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&pkg.Room{ID: 3, MaxAdults: 2, Price: pkg.Money{Amount: 1000, Currency: "USD"}}, nil)
+		rates.EXPECT().Get(gomock.Any(), []int64{3}).Return([]pkg.RatePlan{}, nil)
+	}
+	// the new room of the booking and its plans
+	found := func(rooms *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, adults int) {
+		rooms.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, MaxAdults: adults, Price: pkg.Money{Amount: 2500, Currency: "USD"}}, nil)
+		rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+	}
+
+	tests := []struct {
+		name          string
+		input         pkg.BookingUpdate
+		mock          mockBehavior
+		expected      *pkg.Booking
+		expectedTotal pkg.Money
+		expectedError error
+	}{
+		{
+			name:  "OK move",
+			input: pkg.BookingUpdate{RoomID: &room, Start: &start, End: &end},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				found(rooms, rates, 2)
+				stored(r, nil)
+			},
+			expected:      &pkg.Booking{ID: 7, RoomID: 4, Adults: 2, Start: start, End: end, Status: status},
+			expectedTotal: pkg.Money{Amount: 7500, Currency: "USD"},
+		},
+		{
+			name:  "OK end of started stay",
+			input: pkg.BookingUpdate{End: &extended},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				booked(rooms, rates)
+				stored(r, nil)
+			},
+			expected:      &pkg.Booking{ID: 7, RoomID: 3, Adults: 2, Start: "2017-12-28", End: extended, Status: status},
+			expectedTotal: pkg.Money{Amount: 13000, Currency: "USD"},
+		},
+		{
+			name:  "Arrival moved to past",
+			input: pkg.BookingUpdate{Start: &past},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				booked(rooms, rates)
+				stored(r, nil)
+			},
+			expectedError: pkg.ErrDateInPast,
+		},
+		{
+			name:  "End before start",
+			input: pkg.BookingUpdate{Start: &start, End: &before},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				booked(rooms, rates)
+				stored(r, nil)
+			},
+			expectedError: pkg.ErrDateOrder,
+		},
+		{
+			name:  "Booking conflict",
+			input: pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				found(rooms, rates, 2)
+				stored(r, pkg.ErrBookingConflict)
+			},
+			expectedError: pkg.ErrBookingConflict,
+		},
+		{
+			name:  "Checked in concurrently",
+			input: pkg.BookingUpdate{End: &extended},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				b := booking()
+				b.RoomID, b.TypeID = 0, 2
+				r.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&b, nil)
+				types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.RoomType{ID: 2}, nil)
+				rooms.EXPECT().GetAvailable(gomock.Any(), "2017-12-28", extended, freeRooms(2)).Return([]pkg.Room{{ID: 5, TypeID: 2}}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{5}).Return([]pkg.RatePlan{}, nil)
+				stored(r, nil)
+			},
+			expectedError: pkg.ErrBookingConflict,
+		},
+		{
+			name:  "Capacity exceeded",
+			input: pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				found(rooms, rates, 1)
+				stored(r, nil)
+			},
+			expectedError: pkg.ErrCapacityExceeded,
+		},
+		{
+			name:  "Room not found",
+			input: pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrNoForeignKey,
+		},
+		{
+			name:  "ID not found",
+			input: pkg.BookingUpdate{RoomID: &room},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				r.EXPECT().GetByID(gomock.Any(), int64(7)).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Booking cancelled",
+			input: pkg.BookingUpdate{End: &extended},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+				read(r)
+				booked(rooms, rates)
+				r.EXPECT().Update(gomock.Any(), int64(7), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, id int64, change func(b *pkg.Booking) error) error {
+						b := pkg.Booking{ID: 7, RoomID: 3, Status: pkg.StatusCancelled}
+						return change(&b)
+					},
+				)
+			},
+			expectedError: pkg.ErrStatusChange,
+		},
+		{
+			name:  "Nothing to update",
+			input: pkg.BookingUpdate{},
+			mock: func(r *mock_repository.MockBookings, rooms *mock_repository.MockRoom, types *mock_repository.MockRoomTypes, rates *mock_repository.MockRatePlans) {
+			},
+			expectedError: pkg.ErrNothingToUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockBookings(c)
+			rooms := mock_repository.NewMockRoom(c)
+			types := mock_repository.NewMockRoomTypes(c)
+			rates := mock_repository.NewMockRatePlans(c)
+			tt.mock(repo, rooms, types, rates)
+
+			services := NewBookingsService(repo, rooms, types, rates, nil, nil, DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+			}
+			booking, err := services.Update(context.Background(), 7, &tt.input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			} else if err == nil {
+				// the total of the changed stay is priced again
+				if booking.Total == nil || *booking.Total != tt.expectedTotal {
+					t.Error("incorrect total received: ", booking.Total)
+				}
+				booking.Total = nil
+				if *booking != *tt.expected {
+					t.Error("incorrect booking received: ", booking)
+				}
+			}
+		})
+	}
+}
+
+func TestBookingsService_Scope(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockBookings(c)
+	rooms := mock_repository.NewMockRoom(c)
+	types := mock_repository.NewMockRoomTypes(c)
+	services := NewBookingsService(repo, rooms, types, nil, nil, nil, DefaultDateRules)
+	services.now = func() time.Time {
+		return time.Date(2018, 2, 1, 15, 4, 5, 0, time.UTC)
+	}
+	seaside := WithProperty(context.Background(), 2)
+
+	// rooms and types of other properties can not be booked
+	rooms.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&pkg.Room{ID: 1, PropertyID: 1, MaxAdults: 2}, nil).Times(3)
+	_, err := services.Add(seaside, 1, &pkg.Booking{Start: "2018-02-05", End: "2018-02-07"})
+	if err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+	_, err = services.Quote(seaside, 1, "2018-02-05", "2018-02-07")
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.RoomType{ID: 2, PropertyID: 1, MaxAdults: 2}, nil).Times(2)
+	_, err = services.AddByType(seaside, 2, &pkg.Booking{Start: "2018-02-05", End: "2018-02-07"}, false)
+	if err != pkg.ErrNoRoomType {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// bookings of other properties are not found, the property of
+	// a booking without a room is the property of its type
+	repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&pkg.Booking{ID: 3, RoomID: 1, Status: pkg.StatusPending}, nil).Times(2)
+	rooms.EXPECT().GetProperty(gomock.Any(), int64(1)).Return(int64(1), nil).Times(3)
+	_, err = services.SetStatus(seaside, 3, pkg.StatusConfirmed)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	err = services.Delete(seaside, 3)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	repo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&pkg.Booking{ID: 4, TypeID: 2, Status: pkg.StatusPending}, nil)
+	end := "2018-02-08"
+	_, err = services.Update(seaside, 4, &pkg.BookingUpdate{End: &end})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	_, err = services.Get(seaside, 1, nil)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// a booking can not be moved to a room of another property
+	repo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&pkg.Booking{ID: 5, RoomID: 6, Status: pkg.StatusPending}, nil)
+	rooms.EXPECT().GetProperty(gomock.Any(), int64(6)).Return(int64(2), nil)
+	room := int64(1)
+	_, err = services.Update(seaside, 5, &pkg.BookingUpdate{RoomID: &room})
+	if err != pkg.ErrNoForeignKey {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestBookingsService_Timezone(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	rooms := mock_repository.NewMockRoom(c)
+	rates := mock_repository.NewMockRatePlans(c)
+	properties := mock_repository.NewMockProperties(c)
+	services := NewBookingsService(nil, rooms, nil, rates, nil, properties, DefaultDateRules)
+	// the evening of December 31 in Los Angeles and the noon of January 1 in Tokyo
+	services.now = func() time.Time {
+		return time.Date(2018, 1, 1, 3, 0, 0, 0, time.UTC)
+	}
+	price := pkg.Money{Amount: 1000, Currency: "USD"}
+
+	rooms.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&pkg.Room{ID: 1, PropertyID: 2, Price: price}, nil)
+	properties.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.Property{ID: 2, Timezone: "America/Los_Angeles"}, nil)
+	rates.EXPECT().Get(gomock.Any(), []int64{1}).Return([]pkg.RatePlan{}, nil)
+	_, err := services.Quote(context.Background(), 1, "2017-12-31", "2018-01-02")
+	if err != nil {
+		t.Error("arrival today at the property is rejected: ", err)
+	}
+
+	rooms.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&pkg.Room{ID: 2, PropertyID: 3, Price: price}, nil)
+	properties.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&pkg.Property{ID: 3, Timezone: "Asia/Tokyo"}, nil)
+	_, err = services.Quote(context.Background(), 2, "2017-12-31", "2018-01-02")
+	if !errors.Is(err, pkg.ErrDateInPast) {
+		t.Error("incorrect error received: ", err)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+// DateRules are the limits checked for the dates of a booking.
+// A zero MinNights, MaxNights or Horizon disables the limit.
+type DateRules struct {
+	MinNights int
+	MaxNights int
+	// the number of days from today
+	// within which the arrival date must be
+	Horizon   int
+	AllowPast bool
+}
+
+var DefaultDateRules = DateRules{
+	MinNights: 1,
+	MaxNights: 30,
+	Horizon:   365,
+}
+
+// checks the booking dates against the rules,
+// the dates must already be in the form format
+func (r DateRules) check(start, end, today time.Time) error {
+	if !end.After(start) {
+		return &pkg.DateError{Field: "date_end", Err: pkg.ErrDateOrder}
+	}
+
+	nights := int(end.Sub(start).Hours() / 24)
+	if r.MinNights > 0 && nights < r.MinNights {
+		return &pkg.DateError{
+			Field: "date_end",
+			Err:   pkg.ErrStayTooShort,
+			Limit: fmt.Sprintf("minimum %d nights", r.MinNights),
+		}
+	}
+	if r.MaxNights > 0 && nights > r.MaxNights {
+		return &pkg.DateError{
+			Field: "date_end",
+			Err:   pkg.ErrStayTooLong,
+			Limit: fmt.Sprintf("maximum %d nights", r.MaxNights),
+		}
+	}
+
+	if !r.AllowPast && start.Before(today) {
+		return &pkg.DateError{
+			Field: "date_start",
+			Err:   pkg.ErrDateInPast,
+			Limit: "earliest " + today.Format(form),
+		}
+	}
+	if r.Horizon > 0 && start.After(today.AddDate(0, 0, r.Horizon)) {
+		return &pkg.DateError{
+			Field: "date_start",
+			Err:   pkg.ErrDateTooFar,
+			Limit: "latest " + today.AddDate(0, 0, r.Horizon).Format(form),
+		}
+	}
+
+	return nil
+}
+
+// returns the current date in the location without the time,
+// the date is kept in UTC like the parsed booking dates
+func today(now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// returns the current date in the timezone of the property,
+// a zero property is a date in UTC
+func propertyToday(ctx context.Context, repo repository.Properties, id int64, now time.Time) (time.Time, error) {
+	if id == 0 {
+		return today(now, time.UTC), nil
+	}
+
+	p, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Time{}, pkg.ErrFailedGet.Wrap(err)
+	}
+	return today(now, loc), nil
+}
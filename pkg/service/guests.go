@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type GuestsService struct {
+	repo     repository.Guests
+	bookings repository.Bookings
+}
+
+func NewGuestsService(repo repository.Guests, bookings repository.Bookings) *GuestsService {
+	return &GuestsService{repo: repo, bookings: bookings}
+}
+
+// Adds the guest, the email is kept in lower case.
+// Returns pkg.ErrGuestExists if the email is taken.
+func (s *GuestsService) Add(ctx context.Context, guest *pkg.Guest) (int64, error) {
+	err := checkGuest(guest)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.repo.Add(ctx, guest)
+	return guest.ID, err
+}
+
+func (s *GuestsService) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// the email is compared in lower case
+func (s *GuestsService) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	email, ok := normalizeEmail(email)
+	if !ok {
+		return nil, pkg.ErrEmailNotValid
+	}
+
+	return s.repo.GetByEmail(ctx, email)
+}
+
+// returns bookings of the guest sorted by start date
+func (s *GuestsService) GetBookings(ctx context.Context, id int64) ([]pkg.Booking, error) {
+	_, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bookings.GetByGuest(ctx, id)
+}
+
+// digits with an optional leading plus,
+// spaces, dashes and brackets between them
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{3,18}[0-9]$`)
+
+// checks and trims the fields of the guest,
+// the phone and the document are optional
+func checkGuest(guest *pkg.Guest) error {
+	guest.Name = strings.TrimSpace(guest.Name)
+	if guest.Name == "" {
+		return pkg.ErrNameNotValid
+	}
+
+	email, ok := normalizeEmail(guest.Email)
+	if !ok {
+		return pkg.ErrEmailNotValid
+	}
+	guest.Email = email
+
+	guest.Phone = strings.TrimSpace(guest.Phone)
+	if guest.Phone != "" && !phonePattern.MatchString(guest.Phone) {
+		return pkg.ErrPhoneNotValid
+	}
+
+	guest.Document = strings.TrimSpace(guest.Document)
+	return nil
+}
+
+// returns the bare address in lower case,
+// an address with a display name is not accepted
+func normalizeEmail(email string) (string, bool) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", false
+	}
+
+	return strings.ToLower(email), true
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestGuestsService_Add(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockGuests, guest *pkg.Guest)
+
+	tests := []struct {
+		name          string
+		input         pkg.Guest
+		mock          mockBehavior
+		expected      int64
+		expectedGuest pkg.Guest
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: pkg.Guest{Name: " Anna ", Email: "Anna@Example.com", Phone: "+1 (555) 010-0000", Document: "AB123 "},
+			mock: func(r *mock_repository.MockGuests, guest *pkg.Guest) {
+				r.EXPECT().Add(gomock.Any(), guest).DoAndReturn(func(ctx context.Context, guest *pkg.Guest) error {
+					guest.ID = 2
+					return nil
+				})
+			},
+			expected:      2,
+			expectedGuest: pkg.Guest{ID: 2, Name: "Anna", Email: "anna@example.com", Phone: "+1 (555) 010-0000", Document: "AB123"},
+		},
+		{
+			name:  "Guest exists",
+			input: pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			mock: func(r *mock_repository.MockGuests, guest *pkg.Guest) {
+				r.EXPECT().Add(gomock.Any(), guest).Return(pkg.ErrGuestExists)
+			},
+			expectedGuest: pkg.Guest{Name: "Anna", Email: "anna@example.com"},
+			expectedError: pkg.ErrGuestExists,
+		},
+		{
+			name:          "Name not valid",
+			input:         pkg.Guest{Name: "  ", Email: "anna@example.com"},
+			mock:          func(r *mock_repository.MockGuests, guest *pkg.Guest) {},
+			expectedGuest: pkg.Guest{Email: "anna@example.com"},
+			expectedError: pkg.ErrNameNotValid,
+		},
+		{
+			name:          "Email not valid",
+			input:         pkg.Guest{Name: "Anna", Email: "Anna <anna@example.com>"},
+			mock:          func(r *mock_repository.MockGuests, guest *pkg.Guest) {},
+			expectedGuest: pkg.Guest{Name: "Anna", Email: "Anna <anna@example.com>"},
+			expectedError: pkg.ErrEmailNotValid,
+		},
+		{
+			name:          "Phone not valid",
+			input:         pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "call me"},
+			mock:          func(r *mock_repository.MockGuests, guest *pkg.Guest) {},
+			expectedGuest: pkg.Guest{Name: "Anna", Email: "anna@example.com", Phone: "call me"},
+			expectedError: pkg.ErrPhoneNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockGuests(c)
+			tt.mock(repo, &tt.input)
+
+			services := NewGuestsService(repo, nil)
+			id, err := services.Add(context.Background(), &tt.input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			}
+			if id != tt.expected {
+				t.Error("incorrect id received: ", id)
+			}
+			if tt.input != tt.expectedGuest {
+				t.Error("incorrect guest saved: ", tt.input)
+			}
+		})
+	}
+}
+
+func TestGuestsService_GetByEmail(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	guest := &pkg.Guest{ID: 2, Name: "Anna", Email: "anna@example.com"}
+	repo := mock_repository.NewMockGuests(c)
+	repo.EXPECT().GetByEmail(gomock.Any(), "anna@example.com").Return(guest, nil)
+
+	services := NewGuestsService(repo, nil)
+	got, err := services.GetByEmail(context.Background(), " ANNA@example.com")
+	if err != nil || got != guest {
+		t.Error("incorrect guest received: ", got, err)
+	}
+
+	if _, err := services.GetByEmail(context.Background(), "anna"); err != pkg.ErrEmailNotValid {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestGuestsService_GetBookings(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockGuests, bookings *mock_repository.MockBookings, id int64)
+
+	list := []pkg.Booking{
+		{ID: 4, RoomID: 1, GuestID: 2, Start: "2018-02-03", End: "2018-02-10", Status: pkg.StatusConfirmed},
+	}
+
+	tests := []struct {
+		name          string
+		id            int64
+		mock          mockBehavior
+		expected      []pkg.Booking
+		expectedError error
+	}{
+		{
+			name: "OK",
+			id:   2,
+			mock: func(r *mock_repository.MockGuests, bookings *mock_repository.MockBookings, id int64) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Guest{ID: id}, nil)
+				bookings.EXPECT().GetByGuest(gomock.Any(), id).Return(list, nil)
+			},
+			expected: list,
+		},
+		{
+			name: "Guest not found",
+			id:   3,
+			mock: func(r *mock_repository.MockGuests, bookings *mock_repository.MockBookings, id int64) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockGuests(c)
+			bookings := mock_repository.NewMockBookings(c)
+			tt.mock(repo, bookings, tt.id)
+
+			services := NewGuestsService(repo, bookings)
+			got, err := services.GetBookings(context.Background(), tt.id)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Error("incorrect bookings received: ", got)
+			}
+		})
+	}
+}
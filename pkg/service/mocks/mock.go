@@ -5,6 +5,7 @@
 package mock_service
 
 import (
+	context "context"
 	reflect "reflect"
 
 	pkg "github.com/Avepa/booking/pkg"
@@ -35,47 +36,173 @@ func (m *MockRoom) EXPECT() *MockRoomMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockRoom) Add(room *pkg.Room) (int64, error) {
+func (m *MockRoom) Add(ctx context.Context, room *pkg.Room) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", room)
+	ret := m.ctrl.Call(m, "Add", ctx, room)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockRoomMockRecorder) Add(room interface{}) *gomock.Call {
+func (mr *MockRoomMockRecorder) Add(ctx, room interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoom)(nil).Add), room)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoom)(nil).Add), ctx, room)
 }
 
 // Delete mocks base method.
-func (m *MockRoom) Delete(id int64) error {
+func (m *MockRoom) Delete(ctx context.Context, id int64, force bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, force)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRoomMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockRoomMockRecorder) Delete(ctx, id, force interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoom)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRoom)(nil).Delete), ctx, id, force)
 }
 
 // Get mocks base method.
-func (m *MockRoom) Get(sort string) ([]pkg.Room, error) {
+func (m *MockRoom) Get(ctx context.Context, sort, cursor string, filter *pkg.RoomFilter) (*pkg.RoomPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", sort)
-	ret0, _ := ret[0].([]pkg.Room)
+	ret := m.ctrl.Call(m, "Get", ctx, sort, cursor, filter)
+	ret0, _ := ret[0].(*pkg.RoomPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockRoomMockRecorder) Get(sort interface{}) *gomock.Call {
+func (mr *MockRoomMockRecorder) Get(ctx, sort, cursor, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRoom)(nil).Get), ctx, sort, cursor, filter)
+}
+
+// GetAvailable mocks base method.
+func (m *MockRoom) GetAvailable(ctx context.Context, start, end, sort string, adults, children int, typeID int64) ([]pkg.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailable", ctx, start, end, sort, adults, children, typeID)
+	ret0, _ := ret[0].([]pkg.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailable indicates an expected call of GetAvailable.
+func (mr *MockRoomMockRecorder) GetAvailable(ctx, start, end, sort, adults, children, typeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailable", reflect.TypeOf((*MockRoom)(nil).GetAvailable), ctx, start, end, sort, adults, children, typeID)
+}
+
+// Restore mocks base method.
+func (m *MockRoom) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRoomMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRoom)(nil).Restore), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockRoom) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomMockRecorder) Update(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoom)(nil).Update), ctx, id, update)
+}
+
+// MockRoomTypes is a mock of RoomTypes interface.
+type MockRoomTypes struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoomTypesMockRecorder
+}
+
+// MockRoomTypesMockRecorder is the mock recorder for MockRoomTypes.
+type MockRoomTypesMockRecorder struct {
+	mock *MockRoomTypes
+}
+
+// NewMockRoomTypes creates a new mock instance.
+func NewMockRoomTypes(ctrl *gomock.Controller) *MockRoomTypes {
+	mock := &MockRoomTypes{ctrl: ctrl}
+	mock.recorder = &MockRoomTypesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoomTypes) EXPECT() *MockRoomTypesMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRoomTypes) Add(ctx context.Context, t *pkg.RoomType) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRoomTypesMockRecorder) Add(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRoomTypes)(nil).Add), ctx, t)
+}
+
+// GetByID mocks base method.
+func (m *MockRoomTypes) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.RoomType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRoomTypesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRoomTypes)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockRoomTypes) List(ctx context.Context) ([]pkg.RoomType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]pkg.RoomType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoomTypesMockRecorder) List(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRoom)(nil).Get), sort)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoomTypes)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockRoomTypes) Update(ctx context.Context, id int64, t *pkg.RoomType) (*pkg.RoomType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, t)
+	ret0, _ := ret[0].(*pkg.RoomType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRoomTypesMockRecorder) Update(ctx, id, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRoomTypes)(nil).Update), ctx, id, t)
 }
 
 // MockBookings is a mock of Bookings interface.
@@ -102,45 +229,421 @@ func (m *MockBookings) EXPECT() *MockBookingsMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockBookings) Add(room int64, booking *pkg.Booking) (int64, error) {
+func (m *MockBookings) Add(ctx context.Context, room int64, booking *pkg.Booking) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", room, booking)
+	ret := m.ctrl.Call(m, "Add", ctx, room, booking)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockBookingsMockRecorder) Add(room, booking interface{}) *gomock.Call {
+func (mr *MockBookingsMockRecorder) Add(ctx, room, booking interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBookings)(nil).Add), ctx, room, booking)
+}
+
+// AddByType mocks base method.
+func (m *MockBookings) AddByType(ctx context.Context, typeID int64, booking *pkg.Booking, deferred bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddByType", ctx, typeID, booking, deferred)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddByType indicates an expected call of AddByType.
+func (mr *MockBookingsMockRecorder) AddByType(ctx, typeID, booking, deferred interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockBookings)(nil).Add), room, booking)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddByType", reflect.TypeOf((*MockBookings)(nil).AddByType), ctx, typeID, booking, deferred)
 }
 
 // Delete mocks base method.
-func (m *MockBookings) Delete(id int64) error {
+func (m *MockBookings) Delete(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockBookingsMockRecorder) Delete(id interface{}) *gomock.Call {
+func (mr *MockBookingsMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBookings)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBookings)(nil).Delete), ctx, id)
 }
 
 // Get mocks base method.
-func (m *MockBookings) Get(roomID int64) ([]pkg.Booking, error) {
+func (m *MockBookings) Get(ctx context.Context, roomID int64, status []pkg.BookingStatus) ([]pkg.Booking, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", roomID)
+	ret := m.ctrl.Call(m, "Get", ctx, roomID, status)
 	ret0, _ := ret[0].([]pkg.Booking)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockBookingsMockRecorder) Get(roomID interface{}) *gomock.Call {
+func (mr *MockBookingsMockRecorder) Get(ctx, roomID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBookings)(nil).Get), ctx, roomID, status)
+}
+
+// Quote mocks base method.
+func (m *MockBookings) Quote(ctx context.Context, room int64, start, end string) (*pkg.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, room, start, end)
+	ret0, _ := ret[0].(*pkg.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockBookingsMockRecorder) Quote(ctx, room, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockBookings)(nil).Quote), ctx, room, start, end)
+}
+
+// SetStatus mocks base method.
+func (m *MockBookings) SetStatus(ctx context.Context, id int64, status pkg.BookingStatus) (*pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, id, status)
+	ret0, _ := ret[0].(*pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockBookingsMockRecorder) SetStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockBookings)(nil).SetStatus), ctx, id, status)
+}
+
+// Update mocks base method.
+func (m *MockBookings) Update(ctx context.Context, id int64, update *pkg.BookingUpdate) (*pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, update)
+	ret0, _ := ret[0].(*pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBookingsMockRecorder) Update(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBookings)(nil).Update), ctx, id, update)
+}
+
+// MockProperties is a mock of Properties interface.
+type MockProperties struct {
+	ctrl     *gomock.Controller
+	recorder *MockPropertiesMockRecorder
+}
+
+// MockPropertiesMockRecorder is the mock recorder for MockProperties.
+type MockPropertiesMockRecorder struct {
+	mock *MockProperties
+}
+
+// NewMockProperties creates a new mock instance.
+func NewMockProperties(ctrl *gomock.Controller) *MockProperties {
+	mock := &MockProperties{ctrl: ctrl}
+	mock.recorder = &MockPropertiesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProperties) EXPECT() *MockPropertiesMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockProperties) Add(ctx context.Context, p *pkg.Property) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, p)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockPropertiesMockRecorder) Add(ctx, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockProperties)(nil).Add), ctx, p)
+}
+
+// GetByID mocks base method.
+func (m *MockProperties) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPropertiesMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProperties)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockProperties) List(ctx context.Context) ([]pkg.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]pkg.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPropertiesMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProperties)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockProperties) Update(ctx context.Context, id int64, p *pkg.Property) (*pkg.Property, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, p)
+	ret0, _ := ret[0].(*pkg.Property)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPropertiesMockRecorder) Update(ctx, id, p interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProperties)(nil).Update), ctx, id, p)
+}
+
+// MockRatePlans is a mock of RatePlans interface.
+type MockRatePlans struct {
+	ctrl     *gomock.Controller
+	recorder *MockRatePlansMockRecorder
+}
+
+// MockRatePlansMockRecorder is the mock recorder for MockRatePlans.
+type MockRatePlansMockRecorder struct {
+	mock *MockRatePlans
+}
+
+// NewMockRatePlans creates a new mock instance.
+func NewMockRatePlans(ctrl *gomock.Controller) *MockRatePlans {
+	mock := &MockRatePlans{ctrl: ctrl}
+	mock.recorder = &MockRatePlansMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRatePlans) EXPECT() *MockRatePlansMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockRatePlans) Add(ctx context.Context, room int64, plan *pkg.RatePlan) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, room, plan)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockRatePlansMockRecorder) Add(ctx, room, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockRatePlans)(nil).Add), ctx, room, plan)
+}
+
+// Delete mocks base method.
+func (m *MockRatePlans) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRatePlansMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRatePlans)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockRatePlans) Get(ctx context.Context, room int64) ([]pkg.RatePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, room)
+	ret0, _ := ret[0].([]pkg.RatePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRatePlansMockRecorder) Get(ctx, room interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRatePlans)(nil).Get), ctx, room)
+}
+
+// Update mocks base method.
+func (m *MockRatePlans) Update(ctx context.Context, id int64, plan *pkg.RatePlan) (*pkg.RatePlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, plan)
+	ret0, _ := ret[0].(*pkg.RatePlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRatePlansMockRecorder) Update(ctx, id, plan interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRatePlans)(nil).Update), ctx, id, plan)
+}
+
+// MockGuests is a mock of Guests interface.
+type MockGuests struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestsMockRecorder
+}
+
+// MockGuestsMockRecorder is the mock recorder for MockGuests.
+type MockGuestsMockRecorder struct {
+	mock *MockGuests
+}
+
+// NewMockGuests creates a new mock instance.
+func NewMockGuests(ctrl *gomock.Controller) *MockGuests {
+	mock := &MockGuests{ctrl: ctrl}
+	mock.recorder = &MockGuestsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuests) EXPECT() *MockGuestsMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockGuests) Add(ctx context.Context, guest *pkg.Guest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, guest)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockGuestsMockRecorder) Add(ctx, guest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockGuests)(nil).Add), ctx, guest)
+}
+
+// GetBookings mocks base method.
+func (m *MockGuests) GetBookings(ctx context.Context, id int64) ([]pkg.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookings", ctx, id)
+	ret0, _ := ret[0].([]pkg.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookings indicates an expected call of GetBookings.
+func (mr *MockGuestsMockRecorder) GetBookings(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookings", reflect.TypeOf((*MockGuests)(nil).GetBookings), ctx, id)
+}
+
+// GetByEmail mocks base method.
+func (m *MockGuests) GetByEmail(ctx context.Context, email string) (*pkg.Guest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret0, _ := ret[0].(*pkg.Guest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockGuestsMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockGuests)(nil).GetByEmail), ctx, email)
+}
+
+// GetByID mocks base method.
+func (m *MockGuests) GetByID(ctx context.Context, id int64) (*pkg.Guest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*pkg.Guest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockGuestsMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockGuests)(nil).GetByID), ctx, id)
+}
+
+// MockAPIKeys is a mock of APIKeys interface.
+type MockAPIKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeysMockRecorder
+}
+
+// MockAPIKeysMockRecorder is the mock recorder for MockAPIKeys.
+type MockAPIKeysMockRecorder struct {
+	mock *MockAPIKeys
+}
+
+// NewMockAPIKeys creates a new mock instance.
+func NewMockAPIKeys(ctrl *gomock.Controller) *MockAPIKeys {
+	mock := &MockAPIKeys{ctrl: ctrl}
+	mock.recorder = &MockAPIKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeys) EXPECT() *MockAPIKeysMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeys) Authenticate(ctx context.Context, key string) (*pkg.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*pkg.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeysMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeys)(nil).Authenticate), ctx, key)
+}
+
+// Issue mocks base method.
+func (m *MockAPIKeys) Issue(ctx context.Context, tenant int64, role pkg.Role) (string, *pkg.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", ctx, tenant, role)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*pkg.APIKey)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockAPIKeysMockRecorder) Issue(ctx, tenant, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockAPIKeys)(nil).Issue), ctx, tenant, role)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeys) Revoke(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeysMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBookings)(nil).Get), roomID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeys)(nil).Revoke), ctx, id)
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type PropertiesService struct {
+	repo  repository.Properties
+	rooms repository.Room
+	types repository.RoomTypes
+}
+
+func NewPropertiesService(repo repository.Properties, rooms repository.Room, types repository.RoomTypes) *PropertiesService {
+	return &PropertiesService{repo: repo, rooms: rooms, types: types}
+}
+
+// A property without a timezone gets UTC.
+func (s *PropertiesService) Add(ctx context.Context, p *pkg.Property) (int64, error) {
+	err := checkProperty(p)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.repo.Add(ctx, p)
+	return p.ID, err
+}
+
+// Replaces the property, returns pkg.ErrCurrencyChange
+// if the currency changes while the property has rooms or types,
+// their prices are kept in the currency of the property.
+func (s *PropertiesService) Update(ctx context.Context, id int64, p *pkg.Property) (*pkg.Property, error) {
+	err := checkProperty(p)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Currency != old.Currency {
+		err = s.checkEmpty(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.Update(ctx, id, p)
+	if err != nil {
+		return nil, err
+	}
+
+	p.ID = id
+	return p, nil
+}
+
+func (s *PropertiesService) GetByID(ctx context.Context, id int64) (*pkg.Property, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// returns all properties sorted by id
+func (s *PropertiesService) List(ctx context.Context) ([]pkg.Property, error) {
+	return s.repo.List(ctx)
+}
+
+// returns pkg.ErrCurrencyChange if the property has rooms or types
+func (s *PropertiesService) checkEmpty(ctx context.Context, id int64) error {
+	rooms, err := s.rooms.Count(ctx, &pkg.RoomFilter{PropertyID: id})
+	if err != nil {
+		return err
+	}
+	types, err := s.types.List(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if rooms > 0 || len(types) > 0 {
+		return pkg.ErrCurrencyChange
+	}
+	return nil
+}
+
+// checks and trims the fields of the property
+func checkProperty(p *pkg.Property) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return pkg.ErrNameNotValid
+	}
+	p.Address = strings.TrimSpace(p.Address)
+
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	// "Local" is accepted by LoadLocation, but depends on the server
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "Local" {
+		return pkg.ErrTimezoneNotValid
+	}
+
+	return pkg.Money{Currency: p.Currency}.Validate()
+}
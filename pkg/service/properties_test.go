@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+// returns a repository of two properties, the default one
+// in dollars and the second one in euros
+func newMockProperties(c *gomock.Controller) *mock_repository.MockProperties {
+	properties := mock_repository.NewMockProperties(c)
+	properties.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, id int64) (*pkg.Property, error) {
+			switch id {
+			case pkg.DefaultPropertyID:
+				return &pkg.Property{ID: id, Name: "Default", Timezone: "UTC", Currency: "USD"}, nil
+			case 2:
+				return &pkg.Property{ID: id, Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "EUR"}, nil
+			}
+			return nil, pkg.ErrIDNotFound
+		},
+	).AnyTimes()
+	return properties
+}
+
+func TestPropertiesService_Add(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         pkg.Property
+		expected      pkg.Property
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			input:    pkg.Property{Name: " Seaside ", Address: "1 Beach Road ", Timezone: "Europe/Lisbon", Currency: "EUR"},
+			expected: pkg.Property{ID: 2, Name: "Seaside", Address: "1 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"},
+		},
+		{
+			name:     "OK default timezone",
+			input:    pkg.Property{Name: "Seaside", Currency: "EUR"},
+			expected: pkg.Property{ID: 2, Name: "Seaside", Timezone: "UTC", Currency: "EUR"},
+		},
+		{
+			name:          "Name not valid",
+			input:         pkg.Property{Name: " ", Currency: "EUR"},
+			expectedError: pkg.ErrNameNotValid,
+		},
+		{
+			name:          "Timezone not valid",
+			input:         pkg.Property{Name: "Seaside", Timezone: "Europe/Atlantis", Currency: "EUR"},
+			expectedError: pkg.ErrTimezoneNotValid,
+		},
+		{
+			name:          "Local timezone",
+			input:         pkg.Property{Name: "Seaside", Timezone: "Local", Currency: "EUR"},
+			expectedError: pkg.ErrTimezoneNotValid,
+		},
+		{
+			name:          "Currency not valid",
+			input:         pkg.Property{Name: "Seaside", Currency: "eur"},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockProperties(c)
+			if tt.expectedError == nil {
+				repo.EXPECT().Add(gomock.Any(), &tt.input).DoAndReturn(func(ctx context.Context, p *pkg.Property) error {
+					p.ID = 2
+					return nil
+				})
+			}
+
+			services := NewPropertiesService(repo, nil, nil)
+			id, err := services.Add(context.Background(), &tt.input)
+			if err != tt.expectedError {
+				t.Fatal("incorrect error received: ", err)
+			}
+			if err == nil && (id != 2 || tt.input != tt.expected) {
+				t.Error("wrong property saved: ", id, tt.input)
+			}
+		})
+	}
+}
+
+func TestPropertiesService_Update(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := newMockProperties(c)
+	rooms := mock_repository.NewMockRoom(c)
+	types := mock_repository.NewMockRoomTypes(c)
+	services := NewPropertiesService(repo, rooms, types)
+
+	input := &pkg.Property{Name: "Seaside", Address: "2 Beach Road", Timezone: "Europe/Lisbon", Currency: "EUR"}
+	repo.EXPECT().Update(gomock.Any(), int64(2), input).Return(nil)
+	got, err := services.Update(context.Background(), 2, input)
+	if err != nil || got.ID != 2 {
+		t.Error("wrong property received: ", got, err)
+	}
+
+	_, err = services.Update(context.Background(), 3, &pkg.Property{Name: "Seaside", Currency: "EUR"})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	_, err = services.Update(context.Background(), 2, &pkg.Property{Name: "Seaside", Currency: ""})
+	if err != pkg.ErrCurrencyNotValid {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the prices of the rooms and types would stay in euros
+	rooms.EXPECT().Count(gomock.Any(), &pkg.RoomFilter{PropertyID: 2}).Return(int64(1), nil)
+	types.EXPECT().List(gomock.Any(), int64(2)).Return([]pkg.RoomType{}, nil)
+	_, err = services.Update(context.Background(), 2, &pkg.Property{Name: "Seaside", Currency: "USD"})
+	if err != pkg.ErrCurrencyChange {
+		t.Error("incorrect error received: ", err)
+	}
+
+	rooms.EXPECT().Count(gomock.Any(), &pkg.RoomFilter{PropertyID: 2}).Return(int64(0), nil)
+	types.EXPECT().List(gomock.Any(), int64(2)).Return([]pkg.RoomType{{ID: 4, PropertyID: 2}}, nil)
+	_, err = services.Update(context.Background(), 2, &pkg.Property{Name: "Seaside", Currency: "USD"})
+	if err != pkg.ErrCurrencyChange {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// a property without rooms and types can change its currency
+	empty := &pkg.Property{Name: "Seaside", Timezone: "Europe/Lisbon", Currency: "USD"}
+	rooms.EXPECT().Count(gomock.Any(), &pkg.RoomFilter{PropertyID: 2}).Return(int64(0), nil)
+	types.EXPECT().List(gomock.Any(), int64(2)).Return([]pkg.RoomType{}, nil)
+	repo.EXPECT().Update(gomock.Any(), int64(2), empty).Return(nil)
+	got, err = services.Update(context.Background(), 2, empty)
+	if err != nil || got.Currency != "USD" {
+		t.Error("wrong property received: ", got, err)
+	}
+}
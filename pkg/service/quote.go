@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+)
+
+// Prices a stay in the room, each night at the rate of its rate plans,
+// the dates are checked by the same rules as when the room is booked.
+// Returns pkg.ErrIDNotFound if the room does not exist or is archived.
+func (s *BookingsService) Quote(ctx context.Context, room int64, start, end string) (*pkg.Quote, error) {
+	r, err := s.rooms.GetByID(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+	if !inScope(ctx, r.PropertyID) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	err = s.checkDates(ctx, r.PropertyID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.rates.Get(ctx, []int64{room})
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuote(r, plans, start, end)
+}
+
+// returns one line for each night from start to end
+// priced by the plans of the room, the dates must already be checked
+func newQuote(room *pkg.Room, plans []pkg.RatePlan, start, end string) (*pkg.Quote, error) {
+	dateStart, err := time.Parse(form, start)
+	if err != nil {
+		return nil, pkg.ErrDateIsIncorrect
+	}
+	dateEnd, err := time.Parse(form, end)
+	if err != nil {
+		return nil, pkg.ErrDateIsIncorrect
+	}
+
+	q := &pkg.Quote{
+		RoomID: room.ID,
+		Start:  start,
+		End:    end,
+		Total:  pkg.Money{Currency: room.Price.Currency},
+	}
+	for night := dateStart; night.Before(dateEnd); night = night.AddDate(0, 0, 1) {
+		price := nightlyRate(room, plans, night)
+		if q.Total.Amount > math.MaxInt64-price.Amount {
+			return nil, pkg.ErrPriceNotValid
+		}
+
+		q.Lines = append(q.Lines, pkg.QuoteLine{
+			Date:  night.Format(form),
+			Price: price,
+		})
+		q.Total.Amount += price.Amount
+	}
+	q.Nights = len(q.Lines)
+
+	return q, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestBookingsService_Quote(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64)
+
+	price := pkg.Money{Amount: 1999, Currency: "USD"}
+	plans := []pkg.RatePlan{
+		{ID: 1, RoomID: 3, Name: "March", Start: "2018-03-01", End: "2018-04-01", Price: pkg.Money{Amount: 2999, Currency: "USD"}},
+		{ID: 2, RoomID: 3, Name: "Wednesday", Weekdays: 1 << time.Wednesday, Price: pkg.Money{Amount: 2499, Currency: "USD"}},
+		{ID: 3, RoomID: 3, Name: "Euro", Price: pkg.Money{Amount: 100, Currency: "EUR"}},
+		{ID: 4, RoomID: 3, Name: "Thursday", Weekdays: 1 << time.Thursday, Price: pkg.Money{Amount: 999, Currency: "USD"}, Priority: -1},
+	}
+
+	tests := []struct {
+		name          string
+		room          int64
+		start         string
+		end           string
+		mock          mockBehavior
+		expected      *pkg.Quote
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			room:  3,
+			start: "2018-02-27",
+			end:   "2018-03-02",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+			},
+			expected: &pkg.Quote{
+				RoomID: 3,
+				Start:  "2018-02-27",
+				End:    "2018-03-02",
+				Nights: 3,
+				Lines: []pkg.QuoteLine{
+					{Date: "2018-02-27", Price: price},
+					{Date: "2018-02-28", Price: price},
+					{Date: "2018-03-01", Price: price},
+				},
+				Total: pkg.Money{Amount: 5997, Currency: "USD"},
+			},
+		},
+		{
+			name:  "OK rate plans",
+			room:  3,
+			start: "2018-02-27",
+			end:   "2018-03-02",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return(plans, nil)
+			},
+			expected: &pkg.Quote{
+				RoomID: 3,
+				Start:  "2018-02-27",
+				End:    "2018-03-02",
+				Nights: 3,
+				Lines: []pkg.QuoteLine{
+					{Date: "2018-02-27", Price: price},
+					{Date: "2018-02-28", Price: pkg.Money{Amount: 2499, Currency: "USD"}},
+					{Date: "2018-03-01", Price: pkg.Money{Amount: 2999, Currency: "USD"}},
+				},
+				Total: pkg.Money{Amount: 7497, Currency: "USD"},
+			},
+		},
+		{
+			name:  "Rate plans failed",
+			room:  3,
+			start: "2018-02-27",
+			end:   "2018-03-02",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return(nil, pkg.ErrFailedGet)
+			},
+			expectedError: pkg.ErrFailedGet,
+		},
+		{
+			name:  "ID not found",
+			room:  9,
+			start: "2018-02-05",
+			end:   "2018-02-07",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Total too large",
+			room:  3,
+			start: "2018-02-05",
+			end:   "2018-02-07",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				huge := pkg.Money{Amount: math.MaxInt64/2 + 1, Currency: "USD"}
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: huge}, nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{room}).Return([]pkg.RatePlan{}, nil)
+			},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:  "Date is incorrect",
+			room:  3,
+			start: "2018.02.05",
+			end:   "2018-02-07",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price}, nil)
+			},
+			expectedError: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:  "End before start",
+			room:  3,
+			start: "2018-02-07",
+			end:   "2018-02-05",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans, room int64) {
+				r.EXPECT().GetByID(gomock.Any(), room).Return(&pkg.Room{ID: room, Price: price}, nil)
+			},
+			expectedError: pkg.ErrDateOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			rooms := mock_repository.NewMockRoom(c)
+			rates := mock_repository.NewMockRatePlans(c)
+			tt.mock(rooms, rates, tt.room)
+
+			services := NewBookingsService(nil, rooms, nil, rates, nil, nil, DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+			}
+			quote, err := services.Quote(context.Background(), tt.room, tt.start, tt.end)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			}
+			if !reflect.DeepEqual(quote, tt.expected) {
+				t.Error("incorrect quote received: ", quote)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type RatePlansService struct {
+	repo  repository.RatePlans
+	rooms repository.Room
+}
+
+func NewRatePlansService(repo repository.RatePlans, rooms repository.Room) *RatePlansService {
+	return &RatePlansService{repo: repo, rooms: rooms}
+}
+
+// Adds the plan to the room, the price of the plan
+// must be in the currency of the room.
+// Returns pkg.ErrIDNotFound if the room does not exist or is archived.
+func (s *RatePlansService) Add(ctx context.Context, room int64, plan *pkg.RatePlan) (int64, error) {
+	r, err := s.room(ctx, room)
+	if err != nil {
+		return 0, err
+	}
+
+	err = checkRatePlan(r, plan)
+	if err != nil {
+		return 0, err
+	}
+
+	plan.RoomID = room
+	err = s.repo.Add(ctx, plan)
+	return plan.ID, err
+}
+
+// Replaces the plan, its room is not changed.
+func (s *RatePlansService) Update(ctx context.Context, id int64, plan *pkg.RatePlan) (*pkg.RatePlan, error) {
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.room(ctx, old.RoomID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRatePlan(r, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.ID, plan.RoomID = id, old.RoomID
+	err = s.repo.Update(ctx, id, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *RatePlansService) Delete(ctx context.Context, id int64) error {
+	if propertyFrom(ctx) != 0 {
+		plan, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		property, err := s.rooms.GetProperty(ctx, plan.RoomID)
+		if err != nil {
+			return err
+		}
+		if !inScope(ctx, property) {
+			return pkg.ErrIDNotFound
+		}
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// returns the plans of the room sorted by id
+func (s *RatePlansService) Get(ctx context.Context, room int64) ([]pkg.RatePlan, error) {
+	_, err := s.room(ctx, room)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(ctx, []int64{room})
+}
+
+// returns the room if it is not archived and is in
+// the property of the request scope, else pkg.ErrIDNotFound
+func (s *RatePlansService) room(ctx context.Context, id int64) (*pkg.Room, error) {
+	r, err := s.rooms.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !inScope(ctx, r.PropertyID) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return r, nil
+}
+
+// checks the plan against the room it prices,
+// the dates of the range are optional
+func checkRatePlan(room *pkg.Room, plan *pkg.RatePlan) error {
+	err := plan.Price.Validate()
+	if err != nil {
+		return err
+	}
+	// the nights of a stay are summed in the currency of the room
+	if plan.Price.Currency != room.Price.Currency {
+		return pkg.ErrCurrencyNotValid
+	}
+	if plan.Weekdays&^pkg.AllWeekdays != 0 {
+		return pkg.ErrWeekdayNotValid
+	}
+
+	var start, end time.Time
+	if plan.Start != "" {
+		start, err = time.Parse(form, plan.Start)
+		if err != nil {
+			return pkg.ErrDateIsIncorrect
+		}
+	}
+	if plan.End != "" {
+		end, err = time.Parse(form, plan.End)
+		if err != nil {
+			return pkg.ErrDateIsIncorrect
+		}
+	}
+	if plan.Start != "" && plan.End != "" && !end.After(start) {
+		return &pkg.DateError{Field: "date_end", Err: pkg.ErrDateOrder}
+	}
+
+	return nil
+}
+
+// returns the price of the night in the room, the plan
+// with the highest priority or else the price of the room,
+// plans in another currency than the room are not used
+func nightlyRate(room *pkg.Room, plans []pkg.RatePlan, night time.Time) pkg.Money {
+	date, day := night.Format(form), night.Weekday()
+
+	var best *pkg.RatePlan
+	for i := range plans {
+		p := &plans[i]
+		if p.RoomID != room.ID || p.Price.Currency != room.Price.Currency ||
+			!p.Applies(date, day) {
+			continue
+		}
+		if best == nil || p.Priority > best.Priority ||
+			p.Priority == best.Priority && p.ID > best.ID {
+			best = p
+		}
+	}
+
+	if best == nil {
+		return room.Price
+	}
+	return best.Price
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestRatePlansService_Add(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan)
+
+	weekend := pkg.Weekdays(1<<time.Saturday | 1<<time.Sunday)
+
+	tests := []struct {
+		name          string
+		room          int64
+		input         pkg.RatePlan
+		mock          mockBehavior
+		expected      int64
+		expectedError error
+	}{
+		{
+			name: "OK",
+			room: 3,
+			input: pkg.RatePlan{
+				Name:     "Summer weekends",
+				Start:    "2018-06-01",
+				End:      "2018-09-01",
+				Weekdays: weekend,
+				Price:    pkg.Money{Amount: 1500, Currency: "USD"},
+			},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+				r.EXPECT().Add(gomock.Any(), plan).DoAndReturn(func(ctx context.Context, plan *pkg.RatePlan) error {
+					if plan.RoomID != room {
+						t.Error("wrong room of the plan: ", plan.RoomID)
+					}
+					plan.ID = 7
+					return nil
+				})
+			},
+			expected: 7,
+		},
+		{
+			name:  "Room not found",
+			room:  9,
+			input: pkg.RatePlan{Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Other currency",
+			room:  3,
+			input: pkg.RatePlan{Price: pkg.Money{Amount: 1500, Currency: "EUR"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name:  "Negative price",
+			room:  3,
+			input: pkg.RatePlan{Price: pkg.Money{Amount: -1, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:  "Date is incorrect",
+			room:  3,
+			input: pkg.RatePlan{Start: "2018.06.01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:  "End before start",
+			room:  3,
+			input: pkg.RatePlan{Start: "2018-09-01", End: "2018-06-01", Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrDateOrder,
+		},
+		{
+			name:  "Weekday not valid",
+			room:  3,
+			input: pkg.RatePlan{Weekdays: 1 << 7, Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64, plan *pkg.RatePlan) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrWeekdayNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRatePlans(c)
+			rooms := mock_repository.NewMockRoom(c)
+			tt.mock(repo, rooms, tt.room, &tt.input)
+
+			services := NewRatePlansService(repo, rooms)
+			id, err := services.Add(context.Background(), tt.room, &tt.input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			}
+			if id != tt.expected {
+				t.Error("incorrect id received: ", id)
+			}
+		})
+	}
+}
+
+// a room priced in dollars
+func room3() *pkg.Room {
+	return &pkg.Room{ID: 3, Price: pkg.Money{Amount: 1000, Currency: "USD"}}
+}
+
+func TestRatePlansService_Update(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, id int64, plan *pkg.RatePlan)
+
+	price := pkg.Money{Amount: 1500, Currency: "USD"}
+
+	tests := []struct {
+		name          string
+		id            int64
+		input         pkg.RatePlan
+		mock          mockBehavior
+		expected      *pkg.RatePlan
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			id:    7,
+			input: pkg.RatePlan{Name: "High season", Start: "2018-06-01", End: "2018-09-01", Price: price, Priority: 2},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, id int64, plan *pkg.RatePlan) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.RatePlan{ID: id, RoomID: 3, Price: price}, nil)
+				rooms.EXPECT().GetByID(gomock.Any(), int64(3)).Return(room3(), nil)
+				r.EXPECT().Update(gomock.Any(), id, plan).Return(nil)
+			},
+			expected: &pkg.RatePlan{
+				ID:       7,
+				RoomID:   3,
+				Name:     "High season",
+				Start:    "2018-06-01",
+				End:      "2018-09-01",
+				Price:    price,
+				Priority: 2,
+			},
+		},
+		{
+			name:  "ID not found",
+			id:    9,
+			input: pkg.RatePlan{Price: price},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, id int64, plan *pkg.RatePlan) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Other currency",
+			id:    7,
+			input: pkg.RatePlan{Price: pkg.Money{Amount: 1500, Currency: "EUR"}},
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, id int64, plan *pkg.RatePlan) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.RatePlan{ID: id, RoomID: 3, Price: price}, nil)
+				rooms.EXPECT().GetByID(gomock.Any(), int64(3)).Return(room3(), nil)
+			},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRatePlans(c)
+			rooms := mock_repository.NewMockRoom(c)
+			tt.mock(repo, rooms, tt.id, &tt.input)
+
+			services := NewRatePlansService(repo, rooms)
+			plan, err := services.Update(context.Background(), tt.id, &tt.input)
+			if !errors.Is(err, tt.expectedError) {
+				t.Error("incorrect error received: ", err)
+			}
+			if !reflect.DeepEqual(plan, tt.expected) {
+				t.Error("incorrect plan received: ", plan)
+			}
+		})
+	}
+}
+
+func TestRatePlansService_Get(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64)
+
+	plans := []pkg.RatePlan{
+		{ID: 1, RoomID: 3, Name: "Weekend", Weekdays: 1 << time.Saturday, Price: pkg.Money{Amount: 1500, Currency: "USD"}},
+	}
+
+	tests := []struct {
+		name          string
+		room          int64
+		mock          mockBehavior
+		expected      []pkg.RatePlan
+		expectedError error
+	}{
+		{
+			name: "OK",
+			room: 3,
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(room3(), nil)
+				r.EXPECT().Get(gomock.Any(), []int64{room}).Return(plans, nil)
+			},
+			expected: plans,
+		},
+		{
+			name: "Room not found",
+			room: 9,
+			mock: func(r *mock_repository.MockRatePlans, rooms *mock_repository.MockRoom, room int64) {
+				rooms.EXPECT().GetByID(gomock.Any(), room).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRatePlans(c)
+			rooms := mock_repository.NewMockRoom(c)
+			tt.mock(repo, rooms, tt.room)
+
+			services := NewRatePlansService(repo, rooms)
+			got, err := services.Get(context.Background(), tt.room)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Error("incorrect plans received: ", got)
+			}
+		})
+	}
+}
+
+func TestRatePlansService_Scope(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockRatePlans(c)
+	rooms := mock_repository.NewMockRoom(c)
+	services := NewRatePlansService(repo, rooms)
+	seaside := WithProperty(context.Background(), 2)
+
+	// the plans of rooms of other properties are not found
+	rooms.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&pkg.Room{ID: 1, PropertyID: 1, Price: pkg.Money{Currency: "USD"}}, nil).Times(3)
+	_, err := services.Add(seaside, 1, &pkg.RatePlan{Price: pkg.Money{Amount: 700, Currency: "USD"}})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	_, err = services.Get(seaside, 1)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	repo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&pkg.RatePlan{ID: 7, RoomID: 1}, nil).Times(2)
+	_, err = services.Update(seaside, 7, &pkg.RatePlan{Price: pkg.Money{Amount: 700, Currency: "USD"}})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	rooms.EXPECT().GetProperty(gomock.Any(), int64(1)).Return(int64(1), nil)
+	err = services.Delete(seaside, 7)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the plans of the rooms of the property are changed
+	repo.EXPECT().GetByID(gomock.Any(), int64(8)).Return(&pkg.RatePlan{ID: 8, RoomID: 4}, nil)
+	rooms.EXPECT().GetProperty(gomock.Any(), int64(4)).Return(int64(2), nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(8)).Return(nil)
+	err = services.Delete(seaside, 8)
+	if err != nil {
+		t.Error(err)
+	}
+}
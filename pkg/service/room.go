@@ -1,40 +1,365 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+
 	"github.com/Avepa/booking/pkg"
 	"github.com/Avepa/booking/pkg/repository"
 )
 
 type RoomService struct {
-	repo repository.Room
+	repo       repository.Room
+	types      repository.RoomTypes
+	rates      repository.RatePlans
+	properties repository.Properties
+	rules      DateRules
+	now        func() time.Time
 }
 
-func NewRoomService(repo repository.Room) *RoomService {
-	return &RoomService{repo: repo}
+func NewRoomService(repo repository.Room, types repository.RoomTypes, rates repository.RatePlans, properties repository.Properties, rules DateRules) *RoomService {
+	return &RoomService{
+		repo:       repo,
+		types:      types,
+		rates:      rates,
+		properties: properties,
+		rules:      rules,
+		now:        time.Now,
+	}
 }
 
-func (s *RoomService) Add(room *pkg.Room) (int64, error) {
-	if room.Price < 0.0 {
-		return 0, pkg.ErrPriceNotValid
+// Returns pkg.ErrCurrencyNotValid for an unknown currency
+// and pkg.ErrPriceNotValid for a negative price.
+// A room without max_adults gets pkg.DefaultMaxAdults.
+// A room of a type takes the description, price and capacity
+// of the type, returns pkg.ErrNoRoomType if it does not exist
+// or belongs to another property.
+// The room is added to the property of the request scope, else of its
+// type or of the property_id field, else to pkg.DefaultPropertyID.
+// Its price must be in the currency of the property.
+func (s *RoomService) Add(ctx context.Context, room *pkg.Room) (int64, error) {
+	var t *pkg.RoomType
+	if room.TypeID != 0 {
+		var err error
+		t, err = s.types.GetByID(ctx, room.TypeID)
+		if errors.Is(err, pkg.ErrIDNotFound) {
+			return 0, pkg.ErrNoRoomType
+		} else if err != nil {
+			return 0, err
+		}
+
+		room.Description = t.Description
+		room.Price = t.Price
+		room.MaxAdults = t.MaxAdults
+		room.MaxChildren = t.MaxChildren
+		if room.PropertyID == 0 && propertyFrom(ctx) == 0 {
+			room.PropertyID = t.PropertyID
+		}
+	}
+
+	err := room.Price.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	if room.MaxAdults == 0 {
+		room.MaxAdults = pkg.DefaultMaxAdults
+	}
+	if room.MaxAdults < 1 || room.MaxChildren < 0 {
+		return 0, pkg.ErrCapacityNotValid
+	}
+
+	p, err := findProperty(ctx, s.properties, room.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+	if t != nil && t.PropertyID != p.ID {
+		return 0, pkg.ErrNoRoomType
+	}
+	if room.Price.Currency != p.Currency {
+		return 0, pkg.ErrCurrencyNotValid
 	}
+	room.PropertyID = p.ID
 
-	err := s.repo.Add(room)
+	err = s.repo.Add(ctx, room)
 	return room.ID, err
 }
 
-func (s *RoomService) Delete(id int64) error {
-	return s.repo.Delete(id)
+// Archives the room, with force also a room
+// whose bookings have not ended yet, a booking
+// ends by today in the timezone of the property.
+func (s *RoomService) Delete(ctx context.Context, id int64, force bool) error {
+	property, err := s.repo.GetProperty(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !inScope(ctx, property) {
+		return pkg.ErrIDNotFound
+	}
+
+	today, err := propertyToday(ctx, s.properties, property, s.now())
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(ctx, id, force, today.Format(form))
+}
+
+func (s *RoomService) Restore(ctx context.Context, id int64) error {
+	err := s.checkScope(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Restore(ctx, id)
+}
+
+// returns pkg.ErrIDNotFound for a room of a property
+// other than the one of the request scope,
+// archived rooms are also checked
+func (s *RoomService) checkScope(ctx context.Context, id int64) error {
+	if propertyFrom(ctx) == 0 {
+		return nil
+	}
+
+	property, err := s.repo.GetProperty(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !inScope(ctx, property) {
+		return pkg.ErrIDNotFound
+	}
+
+	return nil
+}
+
+// Returns pkg.ErrRoomHasType for a room of a type,
+// it is changed together with its type.
+// A new price must be in the currency of the property of the room.
+func (s *RoomService) Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error {
+	if update.Description == nil && update.Price == nil &&
+		update.MaxAdults == nil && update.MaxChildren == nil {
+		return pkg.ErrNothingToUpdate
+	}
+	if update.Price != nil {
+		err := update.Price.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if update.MaxAdults != nil && *update.MaxAdults < 1 ||
+		update.MaxChildren != nil && *update.MaxChildren < 0 {
+		return pkg.ErrCapacityNotValid
+	}
+
+	room, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !inScope(ctx, room.PropertyID) {
+		return pkg.ErrIDNotFound
+	}
+	if room.TypeID != 0 {
+		return pkg.ErrRoomHasType
+	}
+	if update.Price != nil && update.Price.Currency != room.Price.Currency {
+		p, err := s.properties.GetByID(ctx, room.PropertyID)
+		if err != nil {
+			return err
+		}
+		if update.Price.Currency != p.Currency {
+			return pkg.ErrCurrencyNotValid
+		}
+	}
+
+	return s.repo.Update(ctx, id, update)
+}
+
+// page size limits of the room list
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// the cursor is the offset of the next page,
+// clients must treat it as an opaque string
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, pkg.ErrCursorNotValid
+	}
+
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, pkg.ErrCursorNotValid
+	}
+
+	return offset, nil
+}
+
+// returns the page of rooms after the cursor, in a scoped
+// request only the rooms of the property of the scope,
+// a zero limit is replaced by the default page size,
+// a price range needs the currency of its prices.
+// If the stay is set, the rooms are priced for it like in a quote,
+// its dates are checked by the booking rules against today
+// at the property of the rooms, in UTC for rooms of all properties.
+func (s *RoomService) Get(ctx context.Context, sort, cursor string, filter *pkg.RoomFilter) (*pkg.RoomPage, error) {
+	if property := propertyFrom(ctx); property != 0 {
+		filter.PropertyID = property
+	}
+
+	stay := filter.StayStart != "" || filter.StayEnd != ""
+	if stay {
+		today, err := propertyToday(ctx, s.properties, filter.PropertyID, s.now())
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkDates(s.rules, filter.StayStart, filter.StayEnd, today)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageSize {
+		return nil, pkg.ErrLimitNotValid
+	}
+	if filter.Adults < 0 || filter.Children < 0 {
+		return nil, pkg.ErrGuestsNotValid
+	}
+	if filter.Currency != "" {
+		err := pkg.Money{Currency: filter.Currency}.Validate()
+		if err != nil {
+			return nil, err
+		}
+	} else if filter.MinPrice != nil || filter.MaxPrice != nil {
+		// amounts of different currencies can not be compared
+		return nil, pkg.ErrCurrencyNotValid
+	}
+	if filter.MinPrice != nil && *filter.MinPrice < 0 ||
+		filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return nil, pkg.ErrPriceNotValid
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil &&
+		*filter.MinPrice > *filter.MaxPrice {
+		return nil, pkg.ErrPriceNotValid
+	}
+
+	var err error
+	filter.Offset, err = decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	query := sortQuery(sort)
+	query.RoomFilter = *filter
+	rooms, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if stay && len(rooms) > 0 {
+		err = s.priceStay(ctx, rooms, filter.StayStart, filter.StayEnd)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	page := &pkg.RoomPage{
+		Rooms: rooms,
+		Total: total,
+	}
+	if next := filter.Offset + len(rooms); len(rooms) > 0 && int64(next) < total {
+		page.NextCursor = encodeCursor(next)
+	}
+
+	return page, nil
+}
+
+// sets the price of the stay from start to end to the rooms,
+// the plans of all the rooms are read at once
+func (s *RoomService) priceStay(ctx context.Context, rooms []pkg.Room, start, end string) error {
+	ids := make([]int64, len(rooms))
+	for i := range rooms {
+		ids[i] = rooms[i].ID
+	}
+
+	plans, err := s.rates.Get(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range rooms {
+		quote, err := newQuote(&rooms[i], plans, start, end)
+		if err != nil {
+			return err
+		}
+		rooms[i].StayPrice = &quote.Total
+	}
+
+	return nil
 }
 
-func (s *RoomService) Get(sort string) ([]pkg.Room, error) {
+// returns the query sorted by the sorting type of the API,
+// any unknown type is sorted by descending date
+func sortQuery(sort string) pkg.RoomQuery {
 	switch sort {
 	case "date":
-		return s.repo.GetByDate()
+		return pkg.RoomQuery{Sort: pkg.SortByDate}
 	case "price":
-		return s.repo.GetByPrice()
+		return pkg.RoomQuery{Sort: pkg.SortByPrice}
 	case "price_desc":
-		return s.repo.GetByPriceDESC()
-	default:
-		return s.repo.GetByDateDESC()
+		return pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true}
+	}
+	return pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true}
+}
+
+// returns rooms free for the whole range,
+// if adults or children are not zero, only rooms with places for them,
+// if typeID is not zero, only rooms of the type,
+// in a scoped request only rooms of the property of the scope,
+// sorting types are the same as in Get
+func (s *RoomService) GetAvailable(ctx context.Context, start, end, sort string, adults, children int, typeID int64) ([]pkg.Room, error) {
+	dateStart, err := time.Parse(form, start)
+	if err != nil {
+		return nil, pkg.ErrDateIsIncorrect
+	}
+
+	dateEnd, err := time.Parse(form, end)
+	if err != nil {
+		return nil, pkg.ErrDateIsIncorrect
 	}
+
+	if !dateEnd.After(dateStart) {
+		return nil, pkg.ErrDateIsIncorrect
+	}
+
+	if adults < 0 || children < 0 {
+		return nil, pkg.ErrGuestsNotValid
+	}
+
+	query := sortQuery(sort)
+	query.Adults = adults
+	query.Children = children
+	query.TypeID = typeID
+	query.PropertyID = propertyFrom(ctx)
+	return s.repo.GetAvailable(ctx, start, end, query)
 }
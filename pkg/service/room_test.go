@@ -1,7 +1,11 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Avepa/booking/pkg"
 	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
@@ -23,22 +27,94 @@ func TestRoomService_Add(t *testing.T) {
 			input: pkg.Room{
 				ID:          54,
 				Description: "Good",
-				Price:       5.14,
+				Price:       pkg.Money{Amount: 514, Currency: "USD"},
 			},
 			mock: func(r *mock_repository.MockRoom, room *pkg.Room) {
-				r.EXPECT().Add(room).Return(nil)
+				r.EXPECT().Add(gomock.Any(), room).DoAndReturn(func(ctx context.Context, room *pkg.Room) error {
+					if room.MaxAdults != pkg.DefaultMaxAdults {
+						t.Error("default capacity is not set: ", room.MaxAdults)
+					}
+					return nil
+				})
 			},
 			expectedID: 54,
 		},
+		{
+			name: "OK capacity",
+			input: pkg.Room{
+				ID:          55,
+				Description: "Family",
+				Price:       pkg.Money{Amount: 900, Currency: "USD"},
+				MaxAdults:   4,
+				MaxChildren: 2,
+			},
+			mock: func(r *mock_repository.MockRoom, room *pkg.Room) {
+				want := *room
+				want.PropertyID = pkg.DefaultPropertyID
+				r.EXPECT().Add(gomock.Any(), &want).Return(nil)
+			},
+			expectedID: 55,
+		},
+		{
+			name: "OK property",
+			input: pkg.Room{
+				ID:          56,
+				PropertyID:  2,
+				Description: "Sea view",
+				Price:       pkg.Money{Amount: 900, Currency: "EUR"},
+			},
+			mock: func(r *mock_repository.MockRoom, room *pkg.Room) {
+				r.EXPECT().Add(gomock.Any(), room).Return(nil)
+			},
+			expectedID: 56,
+		},
+		{
+			name: "Currency of another property",
+			input: pkg.Room{
+				Description: "Good",
+				Price:       pkg.Money{Amount: 514, Currency: "EUR"},
+			},
+			mock:          func(r *mock_repository.MockRoom, room *pkg.Room) {},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name: "No property",
+			input: pkg.Room{
+				PropertyID:  9,
+				Description: "Good",
+				Price:       pkg.Money{Amount: 514, Currency: "USD"},
+			},
+			mock:          func(r *mock_repository.MockRoom, room *pkg.Room) {},
+			expectedError: pkg.ErrNoProperty,
+		},
+		{
+			name: "Capacity not valid",
+			input: pkg.Room{
+				Description: "Good",
+				Price:       pkg.Money{Amount: 514, Currency: "USD"},
+				MaxChildren: -1,
+			},
+			mock:          func(r *mock_repository.MockRoom, room *pkg.Room) {},
+			expectedError: pkg.ErrCapacityNotValid,
+		},
 		{
 			name: "Price not valid",
 			input: pkg.Room{
 				Description: "Good",
-				Price:       -5.14,
+				Price:       pkg.Money{Amount: -514, Currency: "USD"},
 			},
 			mock:          func(r *mock_repository.MockRoom, room *pkg.Room) {},
 			expectedError: pkg.ErrPriceNotValid,
 		},
+		{
+			name: "Currency not valid",
+			input: pkg.Room{
+				Description: "Good",
+				Price:       pkg.Money{Amount: 514, Currency: "usd"},
+			},
+			mock:          func(r *mock_repository.MockRoom, room *pkg.Room) {},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
 	}
 
 	for _, tt := range tests {
@@ -49,8 +125,8 @@ func TestRoomService_Add(t *testing.T) {
 			repo := mock_repository.NewMockRoom(c)
 			tt.mock(repo, &tt.input)
 
-			services := NewRoomService(repo)
-			id, err := services.Add(&tt.input)
+			services := NewRoomService(repo, nil, nil, newMockProperties(c), DefaultDateRules)
+			id, err := services.Add(context.Background(), &tt.input)
 			if id != tt.expectedID {
 				t.Error("incorrect id received: ", id)
 			}
@@ -61,9 +137,42 @@ func TestRoomService_Add(t *testing.T) {
 	}
 }
 
+func TestRoomService_AddType(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockRoom(c)
+	types := mock_repository.NewMockRoomTypes(c)
+	services := NewRoomService(repo, types, nil, newMockProperties(c), DefaultDateRules)
+
+	double := &pkg.RoomType{ID: 2, PropertyID: 1, Name: "Double", Description: "two beds", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2, MaxChildren: 1}
+	types.EXPECT().GetByID(gomock.Any(), int64(2)).Return(double, nil).Times(2)
+	want := &pkg.Room{ID: 7, PropertyID: 1, TypeID: 2, Description: "two beds", Price: double.Price, MaxAdults: 2, MaxChildren: 1}
+	repo.EXPECT().Add(gomock.Any(), want).Return(nil)
+
+	id, err := services.Add(context.Background(), &pkg.Room{ID: 7, TypeID: 2, Description: "room 101"})
+	if err != nil || id != 7 {
+		t.Error("room of the type not saved: ", id, err)
+	}
+
+	types.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+	_, err = services.Add(context.Background(), &pkg.Room{TypeID: 3})
+	if err != pkg.ErrNoRoomType {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// a room of the type can not be added to another property
+	_, err = services.Add(WithProperty(context.Background(), 2), &pkg.Room{TypeID: 2})
+	if err != pkg.ErrNoRoomType {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
 func TestRoomService_Get(t *testing.T) {
 	type mockBehavior func(r *mock_repository.MockRoom, room []pkg.Room)
 
+	filter := pkg.RoomFilter{Limit: defaultPageSize}
+
 	tests := []struct {
 		name          string
 		input         string
@@ -75,19 +184,20 @@ func TestRoomService_Get(t *testing.T) {
 			name:  "OK date",
 			input: "date",
 			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
-				r.EXPECT().GetByDate().Return(room, nil)
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: filter}).Return(room, nil)
 			},
 			expected: []pkg.Room{
 				{
 					ID:          1,
 					Description: "VIP",
-					Price:       10.0,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2018.01.01",
 				},
 				{
 					ID:          2,
 					Description: "Good",
-					Price:       7.0,
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
 					Date:        "2018.01.02",
 				},
 			},
@@ -96,19 +206,20 @@ func TestRoomService_Get(t *testing.T) {
 			name:  "OK price",
 			input: "price",
 			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
-				r.EXPECT().GetByPrice().Return(room, nil)
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: filter}).Return(room, nil)
 			},
 			expected: []pkg.Room{
 				{
 					ID:          1,
 					Description: "VIP",
-					Price:       10.0,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2018.01.01",
 				},
 				{
 					ID:          2,
 					Description: "Good",
-					Price:       7.0,
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
 					Date:        "2018.01.02",
 				},
 			},
@@ -117,19 +228,20 @@ func TestRoomService_Get(t *testing.T) {
 			name:  "OK price desc",
 			input: "price_desc",
 			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
-				r.EXPECT().GetByPriceDESC().Return(room, nil)
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByPrice, Desc: true, RoomFilter: filter}).Return(room, nil)
 			},
 			expected: []pkg.Room{
 				{
 					ID:          1,
 					Description: "VIP",
-					Price:       10.0,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2018.01.01",
 				},
 				{
 					ID:          2,
 					Description: "Good",
-					Price:       7.0,
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
 					Date:        "2018.01.02",
 				},
 			},
@@ -138,19 +250,20 @@ func TestRoomService_Get(t *testing.T) {
 			name:  "OK date desc",
 			input: "date_desc",
 			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
-				r.EXPECT().GetByDateDESC().Return(room, nil)
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: filter}).Return(room, nil)
 			},
 			expected: []pkg.Room{
 				{
 					ID:          1,
 					Description: "VIP",
-					Price:       10.0,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2018.01.01",
 				},
 				{
 					ID:          2,
 					Description: "Good",
-					Price:       7.0,
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
 					Date:        "2018.01.02",
 				},
 			},
@@ -159,19 +272,20 @@ func TestRoomService_Get(t *testing.T) {
 			name:  "OK date desc",
 			input: "dsg",
 			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
-				r.EXPECT().GetByDateDESC().Return(room, nil)
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: filter}).Return(room, nil)
 			},
 			expected: []pkg.Room{
 				{
 					ID:          1,
 					Description: "VIP",
-					Price:       10.0,
+					Price:       pkg.Money{Amount: 1000, Currency: "USD"},
 					Date:        "2018.01.01",
 				},
 				{
 					ID:          2,
 					Description: "Good",
-					Price:       7.0,
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
 					Date:        "2018.01.02",
 				},
 			},
@@ -186,14 +300,20 @@ func TestRoomService_Get(t *testing.T) {
 			repo := mock_repository.NewMockRoom(c)
 			tt.mock(repo, tt.expected)
 
-			services := NewRoomService(repo)
-			room, err := services.Get(tt.input)
+			services := NewRoomService(repo, nil, nil, nil, DefaultDateRules)
+			page, err := services.Get(context.Background(), tt.input, "", &pkg.RoomFilter{})
 			if err != tt.expectedError {
 				t.Error("incorrect error received: ", err)
 			} else if err != nil {
 				return
 			}
 
+			room := page.Rooms
+			if len(room) != len(tt.expected) || page.Total != 2 || page.NextCursor != "" {
+				t.Error("incorrect page received: ", page)
+				return
+			}
+
 			for k := range room {
 				if room[k] != tt.expected[k] {
 					t.Error("incorrect data received: ", room)
@@ -204,7 +324,325 @@ func TestRoomService_Get(t *testing.T) {
 	}
 }
 
+func TestRoomService_GetPage(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom)
+
+	rooms := []pkg.Room{
+		{ID: 1, Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018.01.01"},
+		{ID: 2, Description: "Good", Price: pkg.Money{Amount: 700, Currency: "USD"}, Date: "2018.01.02"},
+	}
+	var minPrice, maxPrice, negative int64 = 500, 400, -100
+
+	tests := []struct {
+		name          string
+		cursor        string
+		filter        pkg.RoomFilter
+		mock          mockBehavior
+		expectedNext  string
+		expectedError error
+	}{
+		{
+			name:   "OK first page",
+			filter: pkg.RoomFilter{Limit: 2},
+			mock: func(r *mock_repository.MockRoom) {
+				filter := &pkg.RoomFilter{Limit: 2}
+				r.EXPECT().Count(gomock.Any(), filter).Return(int64(5), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: *filter}).Return(rooms, nil)
+			},
+			expectedNext: encodeCursor(2),
+		},
+		{
+			name:   "OK next page",
+			cursor: encodeCursor(2),
+			filter: pkg.RoomFilter{Limit: 2, Currency: "USD", MinPrice: &minPrice},
+			mock: func(r *mock_repository.MockRoom) {
+				filter := &pkg.RoomFilter{Limit: 2, Offset: 2, Currency: "USD", MinPrice: &minPrice}
+				r.EXPECT().Count(gomock.Any(), filter).Return(int64(5), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: *filter}).Return(rooms, nil)
+			},
+			expectedNext: encodeCursor(4),
+		},
+		{
+			name:   "OK guests",
+			filter: pkg.RoomFilter{Limit: 2, Adults: 2, Children: 1},
+			mock: func(r *mock_repository.MockRoom) {
+				filter := &pkg.RoomFilter{Limit: 2, Adults: 2, Children: 1}
+				r.EXPECT().Count(gomock.Any(), filter).Return(int64(5), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: *filter}).Return(rooms, nil)
+			},
+			expectedNext: encodeCursor(2),
+		},
+		{
+			name:   "OK last page",
+			cursor: encodeCursor(4),
+			mock: func(r *mock_repository.MockRoom) {
+				filter := &pkg.RoomFilter{Limit: defaultPageSize, Offset: 4}
+				r.EXPECT().Count(gomock.Any(), filter).Return(int64(5), nil)
+				r.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByDate, Desc: true, RoomFilter: *filter}).Return(rooms[:1], nil)
+			},
+		},
+		{
+			name:          "Limit too large",
+			filter:        pkg.RoomFilter{Limit: maxPageSize + 1},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrLimitNotValid,
+		},
+		{
+			name:          "Guests not valid",
+			filter:        pkg.RoomFilter{Children: -2},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrGuestsNotValid,
+		},
+		{
+			name:          "Cursor not valid",
+			cursor:        "!!",
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrCursorNotValid,
+		},
+		{
+			name:          "Negative cursor",
+			cursor:        encodeCursor(-3),
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrCursorNotValid,
+		},
+		{
+			name:          "Price range reversed",
+			filter:        pkg.RoomFilter{Currency: "USD", MinPrice: &minPrice, MaxPrice: &maxPrice},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:          "Negative price",
+			filter:        pkg.RoomFilter{Currency: "USD", MaxPrice: &negative},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:          "Price without currency",
+			filter:        pkg.RoomFilter{MinPrice: &minPrice},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name:          "Currency not valid",
+			filter:        pkg.RoomFilter{Currency: "XXX"},
+			mock:          func(r *mock_repository.MockRoom) {},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name: "Failed count",
+			mock: func(r *mock_repository.MockRoom) {
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(0), pkg.ErrFailedGet)
+			},
+			expectedError: pkg.ErrFailedGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoom(c)
+			tt.mock(repo)
+
+			services := NewRoomService(repo, nil, nil, nil, DefaultDateRules)
+			page, err := services.Get(context.Background(), "", tt.cursor, &tt.filter)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			} else if err != nil {
+				return
+			}
+
+			if page.NextCursor != tt.expectedNext || page.Total != 5 {
+				t.Error("incorrect page received: ", page)
+			}
+		})
+	}
+}
+
+func TestRoomService_GetStay(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans)
+
+	rooms := func() []pkg.Room {
+		return []pkg.Room{
+			{ID: 1, Description: "VIP", Price: pkg.Money{Amount: 1000, Currency: "USD"}, Date: "2018-01-01"},
+			{ID: 2, Description: "Good", Price: pkg.Money{Amount: 700, Currency: "EUR"}, Date: "2018-01-02"},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		filter        pkg.RoomFilter
+		mock          mockBehavior
+		expected      []*pkg.Money
+		expectedError error
+	}{
+		{
+			name:   "OK",
+			filter: pkg.RoomFilter{StayStart: "2018-02-09", StayEnd: "2018-02-12"},
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), gomock.Any()).Return(rooms(), nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{1, 2}).Return([]pkg.RatePlan{
+					{ID: 1, RoomID: 2, Weekdays: 1<<time.Saturday | 1<<time.Sunday, Price: pkg.Money{Amount: 900, Currency: "EUR"}},
+				}, nil)
+			},
+			expected: []*pkg.Money{
+				{Amount: 3000, Currency: "USD"},
+				{Amount: 2500, Currency: "EUR"},
+			},
+		},
+		{
+			name: "OK without stay",
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), gomock.Any()).Return(rooms(), nil)
+			},
+			expected: []*pkg.Money{nil, nil},
+		},
+		{
+			name:          "Stay without end",
+			filter:        pkg.RoomFilter{StayStart: "2018-02-09"},
+			mock:          func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {},
+			expectedError: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:          "Stay too long",
+			filter:        pkg.RoomFilter{StayStart: "2018-02-09", StayEnd: "2018-04-09"},
+			mock:          func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {},
+			expectedError: pkg.ErrStayTooLong,
+		},
+		{
+			name:   "Failed rate plans",
+			filter: pkg.RoomFilter{StayStart: "2018-02-09", StayEnd: "2018-02-12"},
+			mock: func(r *mock_repository.MockRoom, rates *mock_repository.MockRatePlans) {
+				r.EXPECT().Count(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+				r.EXPECT().List(gomock.Any(), gomock.Any()).Return(rooms(), nil)
+				rates.EXPECT().Get(gomock.Any(), []int64{1, 2}).Return(nil, pkg.ErrFailedGet)
+			},
+			expectedError: pkg.ErrFailedGet,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoom(c)
+			rates := mock_repository.NewMockRatePlans(c)
+			tt.mock(repo, rates)
+
+			services := NewRoomService(repo, nil, rates, nil, DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 1, 1, 15, 4, 5, 0, time.UTC)
+			}
+			page, err := services.Get(context.Background(), "", "", &tt.filter)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatal("incorrect error received: ", err)
+			} else if err != nil {
+				return
+			}
+
+			for i, room := range page.Rooms {
+				if !reflect.DeepEqual(room.StayPrice, tt.expected[i]) {
+					t.Error("incorrect stay price received: ", room.StayPrice)
+				}
+			}
+		})
+	}
+}
+
 func TestRoomService_Delete(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom, room int64, force bool)
+
+	tests := []struct {
+		name          string
+		input         int64
+		property      int64
+		force         bool
+		mock          mockBehavior
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			input:    1,
+			property: 1,
+			mock: func(r *mock_repository.MockRoom, room int64, force bool) {
+				r.EXPECT().Delete(gomock.Any(), room, force, "2018-06-01").Return(nil)
+			},
+		},
+		{
+			name:     "OK force",
+			input:    1,
+			property: 1,
+			force:    true,
+			mock: func(r *mock_repository.MockRoom, room int64, force bool) {
+				r.EXPECT().Delete(gomock.Any(), room, force, "2018-06-01").Return(nil)
+			},
+		},
+		{
+			// it is already the next day in Lisbon
+			name:     "OK property timezone",
+			input:    1,
+			property: 2,
+			mock: func(r *mock_repository.MockRoom, room int64, force bool) {
+				r.EXPECT().Delete(gomock.Any(), room, force, "2018-06-02").Return(nil)
+			},
+		},
+		{
+			name:     "Has bookings",
+			input:    1,
+			property: 1,
+			mock: func(r *mock_repository.MockRoom, room int64, force bool) {
+				r.EXPECT().Delete(gomock.Any(), room, force, "2018-06-01").Return(pkg.ErrRoomHasBookings)
+			},
+			expectedError: pkg.ErrRoomHasBookings,
+		},
+		{
+			name:          "Not Found",
+			input:         5,
+			mock:          func(r *mock_repository.MockRoom, room int64, force bool) {},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:     "Failed delete",
+			input:    1,
+			property: 1,
+			mock: func(r *mock_repository.MockRoom, room int64, force bool) {
+				r.EXPECT().Delete(gomock.Any(), room, force, "2018-06-01").Return(pkg.ErrFailedDelete)
+			},
+			expectedError: pkg.ErrFailedDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoom(c)
+			if tt.property == 0 {
+				repo.EXPECT().GetProperty(gomock.Any(), tt.input).Return(int64(0), pkg.ErrIDNotFound)
+			} else {
+				repo.EXPECT().GetProperty(gomock.Any(), tt.input).Return(tt.property, nil)
+			}
+			tt.mock(repo, tt.input, tt.force)
+
+			services := NewRoomService(repo, nil, nil, newMockProperties(c), DefaultDateRules)
+			services.now = func() time.Time {
+				return time.Date(2018, 6, 1, 23, 30, 0, 0, time.UTC)
+			}
+			err := services.Delete(context.Background(), tt.input, tt.force)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+		})
+	}
+}
+
+func TestRoomService_Restore(t *testing.T) {
 	type mockBehavior func(r *mock_repository.MockRoom, room int64)
 
 	tests := []struct {
@@ -217,16 +655,16 @@ func TestRoomService_Delete(t *testing.T) {
 			name:  "OK",
 			input: 1,
 			mock: func(r *mock_repository.MockRoom, room int64) {
-				r.EXPECT().Delete(room).Return(nil)
+				r.EXPECT().Restore(gomock.Any(), room).Return(nil)
 			},
 		},
 		{
-			name:  "Failed delete",
-			input: 1,
+			name:  "Not found",
+			input: 2,
 			mock: func(r *mock_repository.MockRoom, room int64) {
-				r.EXPECT().Delete(room).Return(pkg.ErrFailedDelete)
+				r.EXPECT().Restore(gomock.Any(), room).Return(pkg.ErrIDNotFound)
 			},
-			expectedError: pkg.ErrFailedDelete,
+			expectedError: pkg.ErrIDNotFound,
 		},
 	}
 
@@ -238,11 +676,249 @@ func TestRoomService_Delete(t *testing.T) {
 			repo := mock_repository.NewMockRoom(c)
 			tt.mock(repo, tt.input)
 
-			services := NewRoomService(repo)
-			err := services.Delete(tt.input)
+			services := NewRoomService(repo, nil, nil, nil, DefaultDateRules)
+			err := services.Restore(context.Background(), tt.input)
 			if err != tt.expectedError {
 				t.Error("incorrect error received: ", err)
 			}
 		})
 	}
 }
+
+func TestRoomService_GetAvailable(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom, room []pkg.Room)
+
+	tests := []struct {
+		name          string
+		start         string
+		end           string
+		adults        int
+		children      int
+		mock          mockBehavior
+		expected      []pkg.Room
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			start:    "2018-01-05",
+			end:      "2018-01-08",
+			adults:   2,
+			children: 1,
+			mock: func(r *mock_repository.MockRoom, room []pkg.Room) {
+				query := pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: pkg.RoomFilter{Adults: 2, Children: 1}}
+				r.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-08", query).Return(room, nil)
+			},
+			expected: []pkg.Room{
+				{
+					ID:          2,
+					Description: "Good",
+					Price:       pkg.Money{Amount: 700, Currency: "USD"},
+					Date:        "2018.01.02",
+				},
+			},
+		},
+		{
+			name:          "Date is incorrect",
+			start:         "2018.01.05",
+			end:           "2018-01-08",
+			mock:          func(r *mock_repository.MockRoom, room []pkg.Room) {},
+			expectedError: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:          "End before start",
+			start:         "2018-01-08",
+			end:           "2018-01-05",
+			mock:          func(r *mock_repository.MockRoom, room []pkg.Room) {},
+			expectedError: pkg.ErrDateIsIncorrect,
+		},
+		{
+			name:          "Guests not valid",
+			start:         "2018-01-05",
+			end:           "2018-01-08",
+			adults:        -1,
+			mock:          func(r *mock_repository.MockRoom, room []pkg.Room) {},
+			expectedError: pkg.ErrGuestsNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoom(c)
+			tt.mock(repo, tt.expected)
+
+			services := NewRoomService(repo, nil, nil, nil, DefaultDateRules)
+			room, err := services.GetAvailable(context.Background(), tt.start, tt.end, "price", tt.adults, tt.children, 0)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			} else if err != nil {
+				return
+			}
+
+			for k := range room {
+				if room[k] != tt.expected[k] {
+					t.Error("incorrect data received: ", room)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestRoomService_Update(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate)
+
+	description := "VIP"
+	price := pkg.Money{Amount: 1050, Currency: "USD"}
+	negative := pkg.Money{Amount: -100, Currency: "USD"}
+	euro := pkg.Money{Amount: 900, Currency: "EUR"}
+	adults, none := 3, 0
+
+	tests := []struct {
+		name          string
+		input         pkg.RoomUpdate
+		mock          mockBehavior
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: pkg.RoomUpdate{Description: &description, Price: &price},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Room{ID: id, PropertyID: 1, Price: pkg.Money{Currency: "USD"}}, nil)
+				r.EXPECT().Update(gomock.Any(), id, update).Return(nil)
+			},
+		},
+		{
+			name:  "OK currency of the property",
+			input: pkg.RoomUpdate{Price: &price},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Room{ID: id, PropertyID: 1, Price: pkg.Money{Currency: "EUR"}}, nil)
+				r.EXPECT().Update(gomock.Any(), id, update).Return(nil)
+			},
+		},
+		{
+			name:  "Currency of another property",
+			input: pkg.RoomUpdate{Price: &euro},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Room{ID: id, PropertyID: 1, Price: pkg.Money{Currency: "USD"}}, nil)
+			},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name:  "OK capacity",
+			input: pkg.RoomUpdate{MaxAdults: &adults, MaxChildren: &none},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Room{ID: id}, nil)
+				r.EXPECT().Update(gomock.Any(), id, update).Return(nil)
+			},
+		},
+		{
+			name:          "Capacity not valid",
+			input:         pkg.RoomUpdate{MaxAdults: &none},
+			mock:          func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {},
+			expectedError: pkg.ErrCapacityNotValid,
+		},
+		{
+			name:  "ID not found",
+			input: pkg.RoomUpdate{Description: &description},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(nil, pkg.ErrIDNotFound)
+			},
+			expectedError: pkg.ErrIDNotFound,
+		},
+		{
+			name:  "Room has type",
+			input: pkg.RoomUpdate{Description: &description},
+			mock: func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {
+				r.EXPECT().GetByID(gomock.Any(), id).Return(&pkg.Room{ID: id, TypeID: 2}, nil)
+			},
+			expectedError: pkg.ErrRoomHasType,
+		},
+		{
+			name:          "Price not valid",
+			input:         pkg.RoomUpdate{Price: &negative},
+			mock:          func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:          "Nothing to update",
+			input:         pkg.RoomUpdate{},
+			mock:          func(r *mock_repository.MockRoom, id int64, update *pkg.RoomUpdate) {},
+			expectedError: pkg.ErrNothingToUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoom(c)
+			tt.mock(repo, 3, &tt.input)
+
+			services := NewRoomService(repo, nil, nil, newMockProperties(c), DefaultDateRules)
+			err := services.Update(context.Background(), 3, &tt.input)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+		})
+	}
+}
+
+func TestRoomService_Scope(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockRoom(c)
+	services := NewRoomService(repo, nil, nil, newMockProperties(c), DefaultDateRules)
+	seaside := WithProperty(context.Background(), 2)
+
+	// the lists of a scoped request have only the rooms of its property
+	filter := pkg.RoomFilter{PropertyID: 2, Limit: defaultPageSize}
+	repo.EXPECT().Count(gomock.Any(), &filter).Return(int64(0), nil)
+	repo.EXPECT().List(gomock.Any(), pkg.RoomQuery{Sort: pkg.SortByPrice, RoomFilter: filter}).Return([]pkg.Room{}, nil)
+	_, err := services.Get(seaside, "price", "", &pkg.RoomFilter{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	repo.EXPECT().GetAvailable(gomock.Any(), "2018-01-05", "2018-01-08", pkg.RoomQuery{Sort: pkg.SortByDate, RoomFilter: pkg.RoomFilter{PropertyID: 2}}).Return([]pkg.Room{}, nil)
+	_, err = services.GetAvailable(seaside, "2018-01-05", "2018-01-08", "date", 0, 0, 0)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// a room added in the scope goes to its property
+	room := &pkg.Room{Description: "Sea view", Price: pkg.Money{Amount: 900, Currency: "EUR"}}
+	repo.EXPECT().Add(gomock.Any(), room).Return(nil)
+	_, err = services.Add(seaside, room)
+	if err != nil || room.PropertyID != 2 {
+		t.Error("wrong room saved: ", room, err)
+	}
+	_, err = services.Add(seaside, &pkg.Room{PropertyID: 1, Price: pkg.Money{Amount: 900, Currency: "EUR"}})
+	if err != pkg.ErrNoProperty {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// rooms of other properties are not found
+	repo.EXPECT().GetProperty(gomock.Any(), int64(1)).Return(int64(1), nil).Times(2)
+	if err := services.Delete(seaside, 1, false); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	if err := services.Restore(seaside, 1); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+	description := "VIP"
+	repo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&pkg.Room{ID: 1, PropertyID: 1}, nil)
+	if err := services.Update(seaside, 1, &pkg.RoomUpdate{Description: &description}); err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	repo.EXPECT().GetProperty(gomock.Any(), int64(4)).Return(int64(2), nil)
+	repo.EXPECT().Delete(gomock.Any(), int64(4), false, gomock.Any()).Return(nil)
+	if err := services.Delete(seaside, 4, false); err != nil {
+		t.Error(err)
+	}
+}
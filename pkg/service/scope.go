@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type propertyKey struct{}
+
+// WithProperty scopes the request to the property, the services
+// see only its rooms, room types, rate plans and bookings,
+// rows of other properties are reported as not found.
+func WithProperty(ctx context.Context, property int64) context.Context {
+	return context.WithValue(ctx, propertyKey{}, property)
+}
+
+// returns the property of the request scope,
+// zero if the request is not scoped
+func propertyFrom(ctx context.Context) int64 {
+	property, _ := ctx.Value(propertyKey{}).(int64)
+	return property
+}
+
+// reports whether a row of the property
+// is seen in the scope of the request
+func inScope(ctx context.Context, property int64) bool {
+	scope := propertyFrom(ctx)
+	return scope == 0 || scope == property
+}
+
+// returns the property of a new room or type: the property of the
+// request scope, else the one with the id, else the default one.
+// Returns pkg.ErrNoProperty if it does not exist.
+func findProperty(ctx context.Context, repo repository.Properties, id int64) (*pkg.Property, error) {
+	if scope := propertyFrom(ctx); scope != 0 {
+		if id != 0 && id != scope {
+			return nil, pkg.ErrNoProperty
+		}
+		id = scope
+	}
+	if id == 0 {
+		id = pkg.DefaultPropertyID
+	}
+
+	p, err := repo.GetByID(ctx, id)
+	if errors.Is(err, pkg.ErrIDNotFound) {
+		return nil, pkg.ErrNoProperty
+	}
+	return p, err
+}
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/Avepa/booking/pkg"
 	"github.com/Avepa/booking/pkg/repository"
 )
@@ -8,25 +10,80 @@ import (
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
 
 type Room interface {
-	Add(room *pkg.Room) (int64, error)
-	Delete(id int64) error
-	Get(sort string) ([]pkg.Room, error)
+	Add(ctx context.Context, room *pkg.Room) (int64, error)
+	Delete(ctx context.Context, id int64, force bool) error
+	Restore(ctx context.Context, id int64) error
+	Update(ctx context.Context, id int64, update *pkg.RoomUpdate) error
+	Get(ctx context.Context, sort, cursor string, filter *pkg.RoomFilter) (*pkg.RoomPage, error)
+	GetAvailable(ctx context.Context, start, end, sort string, adults, children int, typeID int64) ([]pkg.Room, error)
+}
+
+type RoomTypes interface {
+	Add(ctx context.Context, t *pkg.RoomType) (int64, error)
+	Update(ctx context.Context, id int64, t *pkg.RoomType) (*pkg.RoomType, error)
+	GetByID(ctx context.Context, id int64) (*pkg.RoomType, error)
+	List(ctx context.Context) ([]pkg.RoomType, error)
 }
 
 type Bookings interface {
-	Add(room int64, booking *pkg.Booking) (int64, error)
-	Delete(id int64) error
-	Get(roomID int64) ([]pkg.Booking, error)
+	Add(ctx context.Context, room int64, booking *pkg.Booking) (int64, error)
+	AddByType(ctx context.Context, typeID int64, booking *pkg.Booking, deferred bool) (int64, error)
+	Update(ctx context.Context, id int64, update *pkg.BookingUpdate) (*pkg.Booking, error)
+	SetStatus(ctx context.Context, id int64, status pkg.BookingStatus) (*pkg.Booking, error)
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, roomID int64, status []pkg.BookingStatus) ([]pkg.Booking, error)
+	Quote(ctx context.Context, room int64, start, end string) (*pkg.Quote, error)
+}
+
+type Properties interface {
+	Add(ctx context.Context, p *pkg.Property) (int64, error)
+	Update(ctx context.Context, id int64, p *pkg.Property) (*pkg.Property, error)
+	GetByID(ctx context.Context, id int64) (*pkg.Property, error)
+	List(ctx context.Context) ([]pkg.Property, error)
+}
+
+type RatePlans interface {
+	Add(ctx context.Context, room int64, plan *pkg.RatePlan) (int64, error)
+	Update(ctx context.Context, id int64, plan *pkg.RatePlan) (*pkg.RatePlan, error)
+	Delete(ctx context.Context, id int64) error
+	Get(ctx context.Context, room int64) ([]pkg.RatePlan, error)
+}
+
+type Guests interface {
+	Add(ctx context.Context, guest *pkg.Guest) (int64, error)
+	GetByID(ctx context.Context, id int64) (*pkg.Guest, error)
+	GetByEmail(ctx context.Context, email string) (*pkg.Guest, error)
+	GetBookings(ctx context.Context, id int64) ([]pkg.Booking, error)
+}
+
+type APIKeys interface {
+	Issue(ctx context.Context, tenant int64, role pkg.Role) (string, *pkg.APIKey, error)
+	Revoke(ctx context.Context, id int64) error
+	Authenticate(ctx context.Context, key string) (*pkg.APIKey, error)
 }
 
 type Service struct {
+	Properties
 	Room
+	RoomTypes
 	Bookings
+	RatePlans
+	Guests
+	APIKeys
+}
+
+type Config struct {
+	Dates DateRules
 }
 
-func NewService(repos *repository.Repository) *Service {
+func NewService(repos *repository.Repository, cfg Config) *Service {
 	return &Service{
-		Room:     NewRoomService(repos.Room),
-		Bookings: NewBookingsService(repos.Bookings),
+		Properties: NewPropertiesService(repos.Properties, repos.Room, repos.RoomTypes),
+		Room:       NewRoomService(repos.Room, repos.RoomTypes, repos.RatePlans, repos.Properties, cfg.Dates),
+		RoomTypes:  NewRoomTypesService(repos.RoomTypes, repos.Properties),
+		Bookings:   NewBookingsService(repos.Bookings, repos.Room, repos.RoomTypes, repos.RatePlans, repos.Guests, repos.Properties, cfg.Dates),
+		RatePlans:  NewRatePlansService(repos.RatePlans, repos.Room),
+		Guests:     NewGuestsService(repos.Guests, repos.Bookings),
+		APIKeys:    NewAPIKeysService(repos.APIKeys),
 	}
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Avepa/booking/pkg"
+	"github.com/Avepa/booking/pkg/repository"
+)
+
+type RoomTypesService struct {
+	repo       repository.RoomTypes
+	properties repository.Properties
+}
+
+func NewRoomTypesService(repo repository.RoomTypes, properties repository.Properties) *RoomTypesService {
+	return &RoomTypesService{repo: repo, properties: properties}
+}
+
+// A type without max_adults gets pkg.DefaultMaxAdults.
+// The type is added to the property like a room,
+// its price must be in the currency of the property.
+func (s *RoomTypesService) Add(ctx context.Context, t *pkg.RoomType) (int64, error) {
+	err := checkRoomType(t)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := findProperty(ctx, s.properties, t.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+	if t.Price.Currency != p.Currency {
+		return 0, pkg.ErrCurrencyNotValid
+	}
+	t.PropertyID = p.ID
+
+	err = s.repo.Add(ctx, t)
+	return t.ID, err
+}
+
+// Replaces the type, its rooms get the new
+// description, price and capacity.
+// The property of the type is not changed.
+func (s *RoomTypesService) Update(ctx context.Context, id int64, t *pkg.RoomType) (*pkg.RoomType, error) {
+	err := checkRoomType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Price.Currency != old.Price.Currency {
+		p, err := s.properties.GetByID(ctx, old.PropertyID)
+		if err != nil {
+			return nil, err
+		}
+		if t.Price.Currency != p.Currency {
+			return nil, pkg.ErrCurrencyNotValid
+		}
+	}
+	t.PropertyID = old.PropertyID
+
+	err = s.repo.Update(ctx, id, t)
+	if err != nil {
+		return nil, err
+	}
+
+	t.ID = id
+	return t, nil
+}
+
+// returns pkg.ErrIDNotFound for a type of a property
+// other than the one of the request scope
+func (s *RoomTypesService) GetByID(ctx context.Context, id int64) (*pkg.RoomType, error) {
+	t, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !inScope(ctx, t.PropertyID) {
+		return nil, pkg.ErrIDNotFound
+	}
+
+	return t, nil
+}
+
+// returns all types sorted by id,
+// in a scoped request only the types of its property
+func (s *RoomTypesService) List(ctx context.Context) ([]pkg.RoomType, error) {
+	return s.repo.List(ctx, propertyFrom(ctx))
+}
+
+// checks and trims the fields of the type
+func checkRoomType(t *pkg.RoomType) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return pkg.ErrNameNotValid
+	}
+
+	err := t.Price.Validate()
+	if err != nil {
+		return err
+	}
+
+	if t.MaxAdults == 0 {
+		t.MaxAdults = pkg.DefaultMaxAdults
+	}
+	if t.MaxAdults < 1 || t.MaxChildren < 0 {
+		return pkg.ErrCapacityNotValid
+	}
+
+	return nil
+}
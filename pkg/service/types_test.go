@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Avepa/booking/pkg"
+	mock_repository "github.com/Avepa/booking/pkg/repository/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestRoomTypesService_Add(t *testing.T) {
+	type mockBehavior func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType)
+
+	tests := []struct {
+		name          string
+		input         pkg.RoomType
+		mock          mockBehavior
+		expected      int64
+		expectedType  pkg.RoomType
+		expectedError error
+	}{
+		{
+			name:  "OK",
+			input: pkg.RoomType{Name: " Double Deluxe ", Price: pkg.Money{Amount: 12000, Currency: "USD"}},
+			mock: func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {
+				r.EXPECT().Add(gomock.Any(), roomType).DoAndReturn(func(ctx context.Context, roomType *pkg.RoomType) error {
+					roomType.ID = 2
+					return nil
+				})
+			},
+			expected:     2,
+			expectedType: pkg.RoomType{ID: 2, PropertyID: 1, Name: "Double Deluxe", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: pkg.DefaultMaxAdults},
+		},
+		{
+			name:          "Currency of another property",
+			input:         pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "EUR"}},
+			mock:          func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {},
+			expectedType:  pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "EUR"}, MaxAdults: pkg.DefaultMaxAdults},
+			expectedError: pkg.ErrCurrencyNotValid,
+		},
+		{
+			name:          "No property",
+			input:         pkg.RoomType{PropertyID: 9, Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}},
+			mock:          func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {},
+			expectedType:  pkg.RoomType{PropertyID: 9, Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: pkg.DefaultMaxAdults},
+			expectedError: pkg.ErrNoProperty,
+		},
+		{
+			name:          "Name not valid",
+			input:         pkg.RoomType{Name: " ", Price: pkg.Money{Amount: 12000, Currency: "USD"}},
+			mock:          func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {},
+			expectedType:  pkg.RoomType{Price: pkg.Money{Amount: 12000, Currency: "USD"}},
+			expectedError: pkg.ErrNameNotValid,
+		},
+		{
+			name:          "Price not valid",
+			input:         pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: -1, Currency: "USD"}},
+			mock:          func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {},
+			expectedType:  pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: -1, Currency: "USD"}},
+			expectedError: pkg.ErrPriceNotValid,
+		},
+		{
+			name:          "Capacity not valid",
+			input:         pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxChildren: -1},
+			mock:          func(r *mock_repository.MockRoomTypes, roomType *pkg.RoomType) {},
+			expectedType:  pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: pkg.DefaultMaxAdults, MaxChildren: -1},
+			expectedError: pkg.ErrCapacityNotValid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_repository.NewMockRoomTypes(c)
+			tt.mock(repo, &tt.input)
+			properties := newMockProperties(c)
+
+			services := NewRoomTypesService(repo, properties)
+			id, err := services.Add(context.Background(), &tt.input)
+			if err != tt.expectedError {
+				t.Error("incorrect error received: ", err)
+			}
+			if id != tt.expected || tt.input != tt.expectedType {
+				t.Error("incorrect type saved: ", id, tt.input)
+			}
+		})
+	}
+}
+
+func TestRoomTypesService_Update(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockRoomTypes(c)
+	services := NewRoomTypesService(repo, newMockProperties(c))
+
+	old := &pkg.RoomType{ID: 2, PropertyID: 1, Name: "Double", Price: pkg.Money{Amount: 12000, Currency: "USD"}, MaxAdults: 2}
+	repo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(old, nil).AnyTimes()
+
+	input := &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 15000, Currency: "USD"}, MaxAdults: 2}
+	repo.EXPECT().Update(gomock.Any(), int64(2), input).Return(nil)
+	got, err := services.Update(context.Background(), 2, input)
+	if err != nil || got.ID != 2 || got.PropertyID != 1 {
+		t.Error("wrong type received: ", got, err)
+	}
+
+	repo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, pkg.ErrIDNotFound)
+	_, err = services.Update(context.Background(), 3, &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 15000, Currency: "USD"}})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	_, err = services.Update(context.Background(), 2, &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 15000, Currency: "EUR"}})
+	if err != pkg.ErrCurrencyNotValid {
+		t.Error("incorrect error received: ", err)
+	}
+
+	// the type is not seen in the scope of another property
+	_, err = services.Update(WithProperty(context.Background(), 2), 2, &pkg.RoomType{Name: "Double", Price: pkg.Money{Amount: 15000, Currency: "USD"}})
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	_, err = services.Update(context.Background(), 2, &pkg.RoomType{Price: pkg.Money{Amount: 15000, Currency: "USD"}})
+	if err != pkg.ErrNameNotValid {
+		t.Error("incorrect error received: ", err)
+	}
+}
+
+func TestRoomTypesService_Scope(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	repo := mock_repository.NewMockRoomTypes(c)
+	services := NewRoomTypesService(repo, newMockProperties(c))
+	seaside := WithProperty(context.Background(), 2)
+
+	suite := &pkg.RoomType{ID: 4, PropertyID: 2, Name: "Suite", Price: pkg.Money{Amount: 30000, Currency: "EUR"}, MaxAdults: 2}
+	repo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(suite, nil).Times(2)
+	got, err := services.GetByID(seaside, 4)
+	if err != nil || got != suite {
+		t.Error("wrong type received: ", got, err)
+	}
+	_, err = services.GetByID(WithProperty(context.Background(), 1), 4)
+	if err != pkg.ErrIDNotFound {
+		t.Error("incorrect error received: ", err)
+	}
+
+	repo.EXPECT().List(gomock.Any(), int64(2)).Return([]pkg.RoomType{*suite}, nil)
+	list, err := services.List(seaside)
+	if err != nil || len(list) != 1 {
+		t.Error("wrong types received: ", list, err)
+	}
+
+	// a type added in the scope goes to its property
+	single := &pkg.RoomType{Name: "Single", Price: pkg.Money{Amount: 9000, Currency: "EUR"}}
+	repo.EXPECT().Add(gomock.Any(), single).Return(nil)
+	_, err = services.Add(seaside, single)
+	if err != nil || single.PropertyID != 2 {
+		t.Error("wrong type saved: ", single, err)
+	}
+
+	_, err = services.Add(seaside, &pkg.RoomType{PropertyID: 1, Name: "Single", Price: pkg.Money{Amount: 9000, Currency: "EUR"}})
+	if err != pkg.ErrNoProperty {
+		t.Error("incorrect error received: ", err)
+	}
+}
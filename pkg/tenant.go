@@ -0,0 +1,25 @@
+package pkg
+
+import "context"
+
+// DefaultTenantID is the tenant of the requests that do not
+// name one, it holds the data saved before tenants.
+const DefaultTenantID = 1
+
+type tenantKey struct{}
+
+// WithTenant returns a context of the requests of the tenant,
+// the repositories see only the rows of the tenant of the context.
+func WithTenant(ctx context.Context, tenant int64) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFrom returns the tenant of the context,
+// DefaultTenantID if the context has none.
+func TenantFrom(ctx context.Context) int64 {
+	tenant, ok := ctx.Value(tenantKey{}).(int64)
+	if !ok {
+		return DefaultTenantID
+	}
+	return tenant
+}
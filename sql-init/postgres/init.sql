@@ -0,0 +1,115 @@
+-- the schema of a new database, the tables of a database created
+-- by an older version are upgraded by the server when it starts
+
+CREATE TABLE properties (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  name 					VARCHAR(255) NOT NULL,
+  address 				VARCHAR(1024) NOT NULL DEFAULT '',
+  timezone 				VARCHAR(64) NOT NULL,
+  currency 				CHAR(3) NOT NULL
+);
+
+-- the property of the rooms and types added without one,
+-- belongs to the first tenant
+INSERT INTO properties (name, timezone, currency) VALUES ('Default', 'UTC', 'USD');
+
+CREATE TABLE room_types (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  property_id 			BIGINT NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT,
+  name 					VARCHAR(255) NOT NULL,
+  description 			VARCHAR(1024) NOT NULL,
+  price_amount 			BIGINT NOT NULL,
+  price_currency 		CHAR(3) NOT NULL,
+  max_adults 			INT NOT NULL DEFAULT 2,
+  max_children 			INT NOT NULL DEFAULT 0,
+
+  CONSTRAINT room_types_price_check CHECK (price_amount >= 0)
+);
+
+CREATE TABLE room (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  property_id 			BIGINT NOT NULL DEFAULT 1 REFERENCES properties (id) ON DELETE RESTRICT,
+  description 			VARCHAR(1024) NOT NULL,
+  price_amount 			BIGINT NOT NULL,
+  price_currency 		CHAR(3) NOT NULL,
+  date 					DATE NOT NULL,
+  max_adults 			INT NOT NULL DEFAULT 2,
+  max_children 			INT NOT NULL DEFAULT 0,
+  type_id 				BIGINT NULL REFERENCES room_types (id) ON DELETE RESTRICT,
+  deleted_at 			TIMESTAMP NULL,
+
+  CONSTRAINT room_price_check CHECK (price_amount >= 0)
+);
+
+CREATE INDEX room_price_date ON room (price_currency, price_amount, date);
+CREATE INDEX room_type ON room (type_id);
+CREATE INDEX room_property ON room (property_id, date);
+CREATE INDEX room_types_property ON room_types (property_id);
+CREATE INDEX room_tenant ON room (tenant_id, date);
+CREATE INDEX room_types_tenant ON room_types (tenant_id);
+CREATE INDEX properties_tenant ON properties (tenant_id);
+
+CREATE TABLE guests (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  name 					VARCHAR(255) NOT NULL,
+  email 				VARCHAR(255) NOT NULL,
+  phone 				VARCHAR(32) NOT NULL DEFAULT '',
+  document_number 		VARCHAR(64) NOT NULL DEFAULT '',
+
+  CONSTRAINT guests_email UNIQUE (tenant_id, email)
+);
+
+CREATE TABLE bookings (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  room_id 				BIGINT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  type_id 				BIGINT NULL REFERENCES room_types (id) ON DELETE RESTRICT,
+  guest_id 				BIGINT NULL REFERENCES guests (id) ON DELETE RESTRICT,
+  adults 				INT NOT NULL DEFAULT 1,
+  children 				INT NOT NULL DEFAULT 0,
+  date_start 			DATE NOT NULL,
+  date_end 				DATE NOT NULL,
+  status 				VARCHAR(16) NOT NULL DEFAULT 'pending',
+  total_amount 			BIGINT NULL,
+  total_currency 		CHAR(3) NULL,
+
+  CONSTRAINT bookings_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX bookings_room_date ON bookings (room_id, date_start);
+CREATE INDEX bookings_guest_date ON bookings (guest_id, date_start);
+CREATE INDEX bookings_type_date ON bookings (type_id, date_start);
+
+CREATE TABLE rate_plans (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL DEFAULT 1,
+  room_id 				BIGINT NOT NULL REFERENCES room (id) ON DELETE RESTRICT,
+  name 					VARCHAR(255) NOT NULL,
+  date_start 			DATE NULL,
+  date_end 				DATE NULL,
+  weekdays 				SMALLINT NOT NULL DEFAULT 0,
+  price_amount 			BIGINT NOT NULL,
+  price_currency 		CHAR(3) NOT NULL,
+  priority 				INT NOT NULL DEFAULT 0,
+
+  CONSTRAINT rate_plans_price_check CHECK (price_amount >= 0),
+  CONSTRAINT rate_plans_dates_check CHECK (date_end > date_start)
+);
+
+CREATE INDEX rate_plans_room ON rate_plans (room_id);
+
+-- only the SHA-256 hashes of the API keys are stored
+CREATE TABLE api_keys (
+  id 					BIGSERIAL PRIMARY KEY,
+  tenant_id 				BIGINT NOT NULL,
+  role 					VARCHAR(16) NOT NULL,
+  key_hash 				CHAR(64) NOT NULL,
+  created_at 			TIMESTAMP NOT NULL DEFAULT NOW(),
+  revoked_at 			TIMESTAMP NULL,
+
+  CONSTRAINT api_keys_hash UNIQUE (key_hash)
+);